@@ -0,0 +1,38 @@
+package main
+
+import (
+	"clean-arch-template/config"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// configCmd — `config check`: печатает действующий конфиг (файл + env) со
+// скрытыми секретами и все ошибки проверки; код выхода 1, если они есть.
+// БД не трогает — годится для CI и initContainer перед выкаткой.
+func configCmd(_ context.Context, args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return errors.New("usage: config check")
+	}
+
+	cfg, err := config.LoadConfig()
+	var invalid *config.ValidationError
+	if err != nil && !errors.As(err, &invalid) {
+		return err
+	}
+
+	if err := cfg.Print(os.Stdout); err != nil {
+		return err
+	}
+
+	if invalid != nil {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, invalid)
+		return errReported
+	}
+	fmt.Fprintln(os.Stdout)
+	fmt.Fprintln(os.Stdout, "config OK")
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"clean-arch-template/config"
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const healthcheckTimeout = 3 * time.Second
+
+// healthcheckCmd опрашивает /readyz своего же процесса: в образе нет curl/wget,
+// поэтому Docker HEALTHCHECK вызывает сам бинарь.
+func healthcheckCmd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	url := fs.String("url", "", "адрес readiness-пробы (по умолчанию http://127.0.0.1:$HTTP_PORT/readyz)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *url == "" {
+		// Только порт: полная загрузка конфига на каждую пробу лишняя.
+		port, err := config.HTTPPort()
+		if err != nil {
+			return err
+		}
+		*url = "http://127.0.0.1:" + port + "/readyz"
+	}
+
+	return healthcheck(ctx, *url)
+}
+
+func healthcheck(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthcheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -2,63 +2,108 @@ package main
 
 import (
 	"clean-arch-template/config"
-	"clean-arch-template/internal/app"
 	"clean-arch-template/pkg/logger"
-	"clean-arch-template/pkg/tracing"
+	"clean-arch-template/version"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 )
 
+// errReported — ошибка уже записана в лог команды; main только выставляет
+// код выхода.
+var errReported = errors.New("reported")
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"serve":       {"serve [--skip-migrations] [--allow-destructive]  запустить HTTP и gRPC серверы (по умолчанию)", serveCmd},
+	"migrate":     {"migrate [--allow-destructive] up|down|status|plan|redo|to <version>|baseline [<version>]", migrateCmd},
+	"seed":        {"seed                          наполнить пустую БД демо-данными", seedCmd},
+	"config":      {"config check                  проверить конфиг и напечатать его без секретов", configCmd},
+	"version":     {"version                       версия и сведения о сборке", versionCmd},
+	"healthcheck": {"healthcheck [--url URL]       проверить /readyz (Docker HEALTHCHECK)", healthcheckCmd},
+}
+
+// commandOrder — порядок вывода в usage (map не упорядочен).
+var commandOrder = []string{"serve", "migrate", "seed", "config", "version", "healthcheck"}
+
 func main() {
-	// Load environment variables
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		panic(fmt.Sprintf("failed to load config: %v", err))
+	name, args := parseCommand(os.Args[1:])
+
+	if name == "help" || name == "-h" || name == "--help" {
+		usage(os.Stdout)
+		return
 	}
 
-	// Initialize the logger
-	log, err := logger.New(cfg)
-	if err != nil {
-		panic(fmt.Sprintf("failed to init logger: %v", err))
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(2)
 	}
 
-	if err := run(cfg, log); err != nil {
-		log.Error(context.Background(), "application terminated", "error", err.Error())
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err := cmd.run(ctx, args)
+	stop()
+
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errReported):
+		os.Exit(1)
+	default:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
 		os.Exit(1)
 	}
+}
 
-	log.Info(context.Background(), "Server gracefully stopped, bye, bye!")
+// parseCommand: без подкоманды (или сразу с флагами) — serve, как раньше
+// запускался бинарь.
+func parseCommand(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		return "serve", args
+	}
+	return args[0], args[1:]
 }
 
-func run(cfg *config.Config, log logger.Logger) error {
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: template <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range commandOrder {
+		fmt.Fprintln(w, "  "+commands[name].usage)
+	}
+}
 
-	tracerProvider, err := tracing.InitOpenTelemetryGRPC(ctx, cfg, log)
+// setup загружает конфиг и логгер — общий старт команд, работающих с БД.
+func setup() (*config.Config, logger.Logger) {
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		return err
+		panic(fmt.Sprintf("failed to load config: %v", err))
 	}
 
-	// Трейсер гасится после остановки сервера (defer выполняется последним),
-	// чтобы не потерять спаны запросов, дренированных при shutdown.
-	defer func() {
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
-		defer cancel()
+	log, err := logger.New(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("failed to init logger: %v", err))
+	}
 
-		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
-			log.Error(context.Background(), "failed to shutdown tracer provider", "error", err.Error())
-		}
-	}()
+	return cfg, log
+}
 
-	application, err := app.New(ctx, cfg, log)
-	if err != nil {
-		return err
+func versionCmd(_ context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: version")
 	}
 
-	// Блокируемся до сигнала или ошибки сервера: ошибки старта и работы
-	// больше не теряются в горутине.
-	return application.Run(ctx)
+	fmt.Fprint(os.Stdout, version.BuildInfo())
+
+	return nil
 }
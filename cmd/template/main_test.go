@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args     []string
+		wantName string
+		wantArgs []string
+	}{
+		{nil, "serve", nil},
+		{[]string{"--skip-migrations"}, "serve", []string{"--skip-migrations"}},
+		{[]string{"serve", "--skip-migrations"}, "serve", []string{"--skip-migrations"}},
+		{[]string{"migrate", "to", "42"}, "migrate", []string{"to", "42"}},
+		{[]string{"-h"}, "-h", []string{}},
+	}
+	for _, tc := range tests {
+		name, args := parseCommand(tc.args)
+		assert.Equal(t, tc.wantName, name, tc.args)
+		assert.Equal(t, tc.wantArgs, args, tc.args)
+	}
+}
+
+func TestMigrateCmdValidatesArgsBeforeConnecting(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{nil, {"sideways"}, {"up", "1"}, {"to"}, {"to", "abc"}, {"to", "-1"}, {"baseline", "1", "2"}, {"up", "--force"}} {
+		err := migrateCmd(context.Background(), args)
+		require.Error(t, err, args)
+		assert.NotErrorIs(t, err, errReported, args)
+	}
+}
+
+func TestHealthcheck(t *testing.T) {
+	t.Parallel()
+
+	var ready atomic.Bool
+	ready.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	require.NoError(t, healthcheck(context.Background(), srv.URL+"/readyz"))
+
+	ready.Store(false)
+	require.ErrorContains(t, healthcheck(context.Background(), srv.URL+"/readyz"), "status 503")
+
+	srv.Close()
+	require.Error(t, healthcheck(context.Background(), srv.URL+"/readyz"))
+}
+
+func TestParseInterspersed(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"--allow-destructive", "to", "42"},
+		{"to", "--allow-destructive", "42"},
+		{"to", "42", "--allow-destructive"},
+	} {
+		fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+		allow := fs.Bool("allow-destructive", false, "")
+
+		positional, err := parseInterspersed(fs, args)
+		require.NoError(t, err, args)
+		assert.Equal(t, []string{"to", "42"}, positional, args)
+		assert.True(t, *allow, args)
+	}
+}
+
+func TestConfigCmdValidatesArgs(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{nil, {"show"}, {"check", "extra"}} {
+		require.EqualError(t, configCmd(context.Background(), args), "usage: config check", args)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"clean-arch-template/internal/app"
+	"clean-arch-template/internal/migrate"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+var errMigrateUsage = errors.New("usage: migrate [--allow-destructive] up|down|status|plan|redo|to <version>|baseline [<version>]")
+
+func migrateCmd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	allowDestructive := fs.Bool("allow-destructive", false, "разрешить миграции migrate:destructive и откаты")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	// Аргументы проверяем до подключения к БД: опечатка не должна ждать ping.
+	var target int64
+	switch args[0] {
+	case "up", "down", "status", "plan", "redo":
+		if len(args) != 1 {
+			return errMigrateUsage
+		}
+	case "to", "baseline":
+		// baseline без версии отмечает все миграции бинаря.
+		if len(args) > 2 || len(args) == 1 && args[0] == "to" {
+			return errMigrateUsage
+		}
+	default:
+		return errMigrateUsage
+	}
+	if len(args) == 2 {
+		v, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		target = v
+	}
+
+	cfg, log := setup()
+	if *allowDestructive {
+		cfg.DB.AllowDestructiveMigrations = true
+	}
+
+	migrator, err := migrate.New(ctx, cfg.DB, log)
+	if err != nil {
+		log.Error(ctx, "migrate failed", "error", err.Error())
+		return errReported
+	}
+	defer func() {
+		if cerr := migrator.Close(); cerr != nil {
+			log.Error(ctx, "migrate: close db", "error", cerr.Error())
+		}
+	}()
+
+	if err := runMigrate(ctx, migrator, args[0], target, os.Stdout); err != nil {
+		log.Error(ctx, "migrate failed", "command", args[0], "error", err.Error())
+		return errReported
+	}
+
+	return nil
+}
+
+func runMigrate(ctx context.Context, m *migrate.Migrator, sub string, target int64, w io.Writer) error {
+	var (
+		results []*goose.MigrationResult
+		err     error
+	)
+
+	switch sub {
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(w, status)
+		return nil
+	case "plan":
+		plan, err := m.Plan(ctx)
+		if err != nil {
+			return err
+		}
+		printPlan(w, plan)
+		return nil
+	case "baseline":
+		versions, err := m.Baseline(ctx, target)
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			fmt.Fprintf(w, "baseline %d\n", v)
+		}
+	case "up":
+		results, err = m.Up(ctx)
+	case "down":
+		var result *goose.MigrationResult
+		if result, err = m.Down(ctx); result != nil {
+			results = []*goose.MigrationResult{result}
+		}
+	case "redo":
+		results, err = m.Redo(ctx)
+	case "to":
+		results, err = m.To(ctx, target)
+	}
+
+	printResults(w, results)
+	if err != nil {
+		return err
+	}
+
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "schema version: %d\n", version)
+
+	return nil
+}
+
+func printStatus(w io.Writer, status []*goose.MigrationStatus) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATE\tAPPLIED AT\tFILE")
+	for _, s := range status {
+		appliedAt := "-"
+		if !s.AppliedAt.IsZero() {
+			appliedAt = s.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Source.Version, s.State, appliedAt, migrate.SourceName(s.Source))
+	}
+	_ = tw.Flush()
+}
+
+// printPlan — dry-run: что применит up, с SQL и предупреждениями.
+func printPlan(w io.Writer, plan *migrate.Plan) {
+	fmt.Fprintf(w, "schema version: %d\n", plan.Current)
+	if plan.Fresh {
+		fmt.Fprintln(w, "fresh database: destructive markers do not block up")
+	}
+	for _, v := range plan.Unknown {
+		fmt.Fprintf(w, "UNKNOWN  %d applied in database, missing in this binary\n", v)
+	}
+	if len(plan.Pending) == 0 {
+		fmt.Fprintln(w, "no pending migrations")
+	}
+	for _, pm := range plan.Pending {
+		fmt.Fprintf(w, "\n-- pending %d %s", pm.Version, pm.Path)
+		if pm.Destructive {
+			fmt.Fprintf(w, " [DESTRUCTIVE: %s]", pm.Reason)
+		}
+		fmt.Fprintf(w, "\n%s\n", pm.SQL)
+	}
+	if err := plan.Check(0, false); err != nil {
+		fmt.Fprintf(w, "\nup would fail: %v\n", err)
+	}
+}
+
+// parseInterspersed разрешает флаги и до, и после позиционных аргументов:
+// `migrate up --allow-destructive` и `migrate --allow-destructive up`.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func printResults(w io.Writer, results []*goose.MigrationResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "no migrations to run")
+		return
+	}
+	for _, r := range results {
+		fmt.Fprintf(w, "%-4s %d %s (%s)\n", r.Direction, r.Source.Version, migrate.SourceName(r.Source), r.Duration.Round(time.Millisecond))
+	}
+}
+
+func seedCmd(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: seed")
+	}
+
+	cfg, log := setup()
+
+	if err := app.Seed(ctx, cfg.DB, log); err != nil {
+		log.Error(ctx, "seed failed", "error", err.Error())
+		return errReported
+	}
+
+	return nil
+}
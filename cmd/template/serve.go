@@ -0,0 +1,80 @@
+package main
+
+import (
+	"clean-arch-template/config"
+	"clean-arch-template/internal/app"
+	"clean-arch-template/pkg/logger"
+	"clean-arch-template/pkg/tracing"
+	"context"
+	"flag"
+)
+
+func serveCmd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	skipMigrations := fs.Bool("skip-migrations", false, "не применять миграции при старте (их накатывает отдельный `migrate up`)")
+	allowDestructive := fs.Bool("allow-destructive", false, "разрешить миграции migrate:destructive при старте")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, log := setup()
+	if *allowDestructive {
+		cfg.DB.AllowDestructiveMigrations = true
+	}
+
+	var opts []app.Option
+	if *skipMigrations {
+		opts = append(opts, app.SkipMigrations())
+	}
+
+	if err := serve(ctx, cfg, log, opts...); err != nil {
+		log.Error(context.Background(), "application terminated", "error", err.Error())
+		return errReported
+	}
+
+	log.Info(context.Background(), "Server gracefully stopped, bye, bye!")
+
+	return nil
+}
+
+func serve(ctx context.Context, cfg *config.Config, log logger.Logger, opts ...app.Option) error {
+	tracerProvider, err := tracing.InitOpenTelemetry(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+
+	// Трейсер гасится после остановки сервера (defer выполняется последним),
+	// чтобы не потерять спаны запросов, дренированных при shutdown.
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			log.Error(context.Background(), "failed to shutdown tracer provider", "error", err.Error())
+		}
+	}()
+
+	meterProvider, err := tracing.InitMeterProvider(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+
+	// Провайдер метрик гасится так же поздно: финальный push OTLP-читателей.
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := meterProvider.Shutdown(shutdownCtx); err != nil {
+			log.Error(context.Background(), "failed to shutdown meter provider", "error", err.Error())
+		}
+	}()
+
+	application, err := app.New(ctx, cfg, log, opts...)
+	if err != nil {
+		return err
+	}
+
+	// Блокируемся до сигнала или ошибки сервера: ошибки старта и работы
+	// больше не теряются в горутине.
+	return application.Run(ctx)
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
@@ -15,59 +18,265 @@ import (
 )
 
 type (
+	// Config — конфигурация сервиса. Поля с тегом reload:"true" меняются на
+	// лету при перечитывании файла (см. Watcher), остальные — только
+	// перезапуском.
+	//
+	// Выключатели подсистем, в отличие от RATE_LIMIT_ENABLED, — только
+	// перезапуском: OUTBOX_ENABLED, WEBHOOKS_ENABLED и USER_CACHE_ENABLED
+	// решают при старте, какие воркеры запускаются (relay, доставка вебхуков,
+	// слушатель инвалидации кэша) и каким декоратором обёрнут репозиторий.
+	// Остановка на лету бросала бы захваченные пачки до истечения lease, а
+	// включение кэша без слушателя NOTIFY отдавало бы устаревшие данные.
+	// Смена этих флагов в файле попадает в предупреждение Watcher.
 	Config struct {
-		App     `json:"app"     toml:"app"`
-		HTTP    `json:"http"    toml:"http"`
-		DB      `json:"db"      toml:"db"`
-		Log     `json:"logger"  toml:"logger"`
-		Tracing `json:"tracing" toml:"tracing"`
+		App       `json:"app"        toml:"app"`
+		HTTP      `json:"http"       toml:"http"`
+		GRPC      `json:"grpc"       toml:"grpc"`
+		DB        `json:"db"         toml:"db"`
+		Log       `json:"logger"     toml:"logger"`
+		Tracing   `json:"tracing"    toml:"tracing"`
+		Metrics   `json:"metrics"    toml:"metrics"`
+		UserCache `json:"user_cache" toml:"user_cache"`
+		Outbox    `json:"outbox"     toml:"outbox"`
+		Webhooks  `json:"webhooks"   toml:"webhooks"`
+		SSE       `json:"sse"        toml:"sse"`
+		RateLimit `json:"rate_limit" toml:"rate_limit"`
 	}
 
 	App struct {
-		Name        string `json:"name"        toml:"name"        env:"APP_NAME"`
-		Environment string `json:"environment" toml:"environment" env:"ENV_NAME" env-default:"dev"`
-		Debug       bool   `json:"debug"       toml:"debug"       env:"DEBUG"    env-default:"false"`
+		Name        string      `json:"name"        toml:"name"        env:"APP_NAME"`
+		Environment Environment `json:"environment" toml:"environment" env:"ENV_NAME" env-default:"dev"`
+		Debug       bool        `json:"debug"       toml:"debug"       env:"DEBUG"    env-default:"false"`
+		// ReloadInterval — период проверки файла конфигурации на изменения
+		// (см. Watcher); 0 — только по SIGHUP.
+		ReloadInterval time.Duration `json:"reload_interval" toml:"reload_interval" env:"CONFIG_RELOAD_INTERVAL" env-default:"10s"`
 	}
 
 	HTTP struct {
 		Port string `json:"port" toml:"port" env:"HTTP_PORT" env-default:"8000"`
-		// Таймауты задаются только через env: cleanenv не умеет парсить
-		// time.Duration из toml/json файлов.
-		ReadTimeout     time.Duration `env:"HTTP_READ_TIMEOUT"     env-default:"10s"`
-		WriteTimeout    time.Duration `env:"HTTP_WRITE_TIMEOUT"    env-default:"10s"`
-		IdleTimeout     time.Duration `env:"HTTP_IDLE_TIMEOUT"     env-default:"60s"`
-		RequestTimeout  time.Duration `env:"HTTP_REQUEST_TIMEOUT"  env-default:"30s"`
-		ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"10s"`
+		// ProxyHeader — заголовок с IP клиента от ингресса (например, X-Real-IP);
+		// учитывается только для запросов с адресов TrustedProxies (CIDR или IP).
+		// Без них c.IP() — адрес соединения, и за ингрессом все клиенты
+		// делят одно ведро rate limit.
+		ProxyHeader     string        `json:"proxy_header"     toml:"proxy_header"     env:"HTTP_PROXY_HEADER"`
+		TrustedProxies  []string      `json:"trusted_proxies"  toml:"trusted_proxies"  env:"HTTP_TRUSTED_PROXIES"  env-separator:","`
+		ReadTimeout     time.Duration `json:"read_timeout"     toml:"read_timeout"     env:"HTTP_READ_TIMEOUT"     env-default:"10s"`
+		WriteTimeout    time.Duration `json:"write_timeout"    toml:"write_timeout"    env:"HTTP_WRITE_TIMEOUT"    env-default:"10s"`
+		IdleTimeout     time.Duration `json:"idle_timeout"     toml:"idle_timeout"     env:"HTTP_IDLE_TIMEOUT"     env-default:"60s"`
+		RequestTimeout  time.Duration `json:"request_timeout"  toml:"request_timeout"  env:"HTTP_REQUEST_TIMEOUT"  env-default:"30s" reload:"true"`
+		ShutdownTimeout time.Duration `json:"shutdown_timeout" toml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"10s"`
+	}
+
+	// GRPC — транспорт для внутренних сервисов; слушает отдельный порт,
+	// таймаут graceful shutdown общий с HTTP (HTTP_SHUTDOWN_TIMEOUT).
+	GRPC struct {
+		Port string `json:"port" toml:"port" env:"GRPC_PORT" env-default:"8001"`
 	}
 
 	DB struct {
-		DBHost            string `json:"host"     toml:"host"     env:"DB_HOST"`
-		DBPort            int    `json:"port"     toml:"port"     env:"DB_PORT"`
-		DBUser            string `json:"user"     toml:"user"     env:"DB_USER"`
-		DBPassword        string `json:"password" toml:"password" env:"DB_PASSWORD" env-required:"true"`
-		DBName            string `json:"name"     toml:"name"     env:"DB_NAME"`
-		SSLMode           string `json:"sslmode"  toml:"sslmode"  env:"DB_SSLMODE" env-default:"disable"`
-		MigrationsDir     string `json:"migrations_dir" toml:"migrations_dir" env:"MIGRATIONS_DIR" env-default:"migrations"`
-		PoolMax           int32  `json:"pool_max" toml:"pool_max" env:"PG_POOL_MAX" env-required:"true"`
-		PoolMin           int32  `json:"pool_min" toml:"pool_min" env:"PG_POOL_MIN" env-default:"1"`
-		ConnectTimeout    int    `json:"connect_timeout" toml:"connect_timeout" env:"PG_POOL_CONN_TIMEOUT" env-default:"5"`
-		HealthCheckPeriod int    `json:"health_check_period" toml:"health_check_period" env:"PG_POOL_HEALTHCHECK" env-default:"1"`
+		// Backend: postgres | memory. memory — демо-режим без Postgres:
+		// пользователи и переводы в памяти процесса (теряются при рестарте),
+		// outbox, webhooks и SSE-лента выключены.
+		Backend    string `json:"backend"  toml:"backend"  env:"DB_BACKEND" env-default:"postgres"`
+		DBHost     string `json:"host"     toml:"host"     env:"DB_HOST"`
+		DBPort     int    `json:"port"     toml:"port"     env:"DB_PORT"`
+		DBUser     string `json:"user"     toml:"user"     env:"DB_USER"`
+		DBPassword string `json:"password" toml:"password" env:"DB_PASSWORD" secret:"true"` // или ссылка: file:///run/secrets/db_password, env:NAME
+		DBName     string `json:"name"     toml:"name"     env:"DB_NAME"`
+		SSLMode    string `json:"sslmode"  toml:"sslmode"  env:"DB_SSLMODE" env-default:"disable"`
+		// MigrationsDir перекрывает миграции, встроенные в бинарь (пакет
+		// migrations), — для разработки; пусто — встроенные.
+		MigrationsDir string `json:"migrations_dir" toml:"migrations_dir" env:"MIGRATIONS_DIR"`
+		// AllowDestructiveMigrations разрешает Up-миграции с пометкой
+		// migrate:destructive и откаты (migrate down/redo/to); иначе мигратор
+		// отказывает. Флаг --allow-destructive у serve и migrate включает его.
+		AllowDestructiveMigrations bool  `json:"allow_destructive_migrations" toml:"allow_destructive_migrations" env:"MIGRATIONS_ALLOW_DESTRUCTIVE" env-default:"false"`
+		PoolMax                    int32 `json:"pool_max" toml:"pool_max" env:"PG_POOL_MAX"`
+		PoolMin                    int32 `json:"pool_min" toml:"pool_min" env:"PG_POOL_MIN" env-default:"1"`
+		// ConnectTimeout — таймаут установки соединения; HealthCheckPeriod —
+		// период проверки простаивающих соединений пула.
+		ConnectTimeout    time.Duration `json:"connect_timeout"     toml:"connect_timeout"     env:"PG_POOL_CONN_TIMEOUT" env-default:"5s"`
+		HealthCheckPeriod time.Duration `json:"health_check_period" toml:"health_check_period" env:"PG_POOL_HEALTHCHECK"  env-default:"1m"`
+		// StatementTimeout, LockTimeout и IdleInTxTimeout — statement_timeout,
+		// lock_timeout и idle_in_transaction_session_timeout соединений пула и
+		// реплик; 0 — значение сервера. Отдельная транзакция переопределяет их
+		// через database.WithTimeouts. StatementTimeout меньше
+		// HTTP_REQUEST_TIMEOUT: сервер сам прерывает запрос и снимает блокировки,
+		// не дожидаясь отмены ctx.
+		StatementTimeout time.Duration `json:"statement_timeout"   toml:"statement_timeout"   env:"PG_STATEMENT_TIMEOUT"  env-default:"15s"`
+		LockTimeout      time.Duration `json:"lock_timeout"        toml:"lock_timeout"        env:"PG_LOCK_TIMEOUT"       env-default:"5s"`
+		IdleInTxTimeout  time.Duration `json:"idle_in_tx_timeout"  toml:"idle_in_tx_timeout"  env:"PG_IDLE_IN_TX_TIMEOUT" env-default:"60s"`
+		// TxMaxAttempts — попыток транзакции при serialization failure,
+		// deadlock и lock timeout (включая первую; 1 — без повторов). Паузы
+		// между попытками — от TxRetryBaseDelay, удваиваясь, до TxRetryMaxDelay.
+		TxMaxAttempts    int           `json:"tx_max_attempts" toml:"tx_max_attempts" env:"PG_TX_MAX_ATTEMPTS" env-default:"5"`
+		TxRetryBaseDelay time.Duration `json:"tx_retry_base_delay" toml:"tx_retry_base_delay" env:"PG_TX_RETRY_BASE_DELAY" env-default:"10ms"`
+		TxRetryMaxDelay  time.Duration `json:"tx_retry_max_delay"  toml:"tx_retry_max_delay"  env:"PG_TX_RETRY_MAX_DELAY"  env-default:"1s"`
+		// ReplicaHosts — реплики для чтения, "host" или "host:port" (порт по
+		// умолчанию — DB_PORT); пользователь, пароль и база — как у primary.
+		// Пусто — все запросы идут в primary.
+		ReplicaHosts []string `json:"replica_hosts" toml:"replica_hosts" env:"DB_REPLICA_HOSTS"`
+		// ReplicaMaxLag — реплика с отставанием больше выводится из ротации до
+		// следующей проверки; ReplicaCheckPeriod — период проверки.
+		ReplicaMaxLag      time.Duration `json:"replica_max_lag"      toml:"replica_max_lag"      env:"DB_REPLICA_MAX_LAG"      env-default:"5s"`
+		ReplicaCheckPeriod time.Duration `json:"replica_check_period" toml:"replica_check_period" env:"DB_REPLICA_CHECK_PERIOD" env-default:"5s"`
 	}
 
 	Log struct {
-		Level   slog.Level `json:"level"   toml:"level"   env:"LOG_LEVEL"`
+		Level   slog.Level `json:"level"   toml:"level"   env:"LOG_LEVEL" reload:"true"`
 		Backend string     `json:"backend" toml:"backend" env:"LOG_BACKEND" env-default:"slog"`
 	}
 
+	// Tracing — OpenTelemetry-трейсинг (pkg/tracing).
 	Tracing struct {
-		URL string ` json:"url" toml:"url" env:"TRACING_URL"`
+		// Exporter: otlp-grpc | otlp-http | stdout | none. none — спаны
+		// создаются (trace_id в логах), но никуда не отправляются: локальный
+		// запуск без коллектора.
+		Exporter string `json:"exporter" toml:"exporter" env:"TRACING_EXPORTER" env-default:"otlp-grpc"`
+		// URL — адрес коллектора: host:port или полный URL (http(s)://...).
+		URL string `json:"url" toml:"url" env:"TRACING_URL"`
+		// Insecure — без TLS; иначе TLS с системными CA или CAFile, mTLS при
+		// заданных CertFile и KeyFile.
+		Insecure bool   `json:"insecure"  toml:"insecure"  env:"TRACING_INSECURE"  env-default:"true"`
+		CAFile   string `json:"ca_file"   toml:"ca_file"   env:"TRACING_CA_FILE"`
+		CertFile string `json:"cert_file" toml:"cert_file" env:"TRACING_CERT_FILE"`
+		KeyFile  string `json:"key_file"  toml:"key_file"  env:"TRACING_KEY_FILE"`
+		// Headers — заголовки экспорта (например, токен коллектора).
+		// В env: "authorization:Bearer xxx,x-tenant:demo".
+		Headers map[string]string `json:"headers" toml:"headers" env:"TRACING_HEADERS" secret:"true"`
+		// Sampler — имена из спецификации OTEL_TRACES_SAMPLER: always_on,
+		// always_off, traceidratio, parentbased_always_on,
+		// parentbased_always_off, parentbased_traceidratio.
+		Sampler     string  `json:"sampler"      toml:"sampler"      env:"TRACING_SAMPLER"      env-default:"parentbased_traceidratio"`
+		SampleRatio float64 `json:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"0.6"`
+		// ResourceAttributes дополняют service.name/version,
+		// deployment.environment.name и host.name.
+		ResourceAttributes map[string]string `json:"resource_attributes" toml:"resource_attributes" env:"TRACING_RESOURCE_ATTRIBUTES"`
+		// DBSpans — спаны на SQL-запросы и транзакции, независимо от DEBUG.
+		DBSpans bool `json:"db_spans" toml:"db_spans" env:"TRACING_DB_SPANS" env-default:"true"`
+	}
+
+	// Metrics — OpenTelemetry-метрики (tracing.InitMeterProvider).
+	Metrics struct {
+		// Readers: prometheus — мост в /metrics (pull); otlp-grpc, otlp-http —
+		// push в коллектор. Можно несколько. В env: "prometheus,otlp-grpc".
+		Readers []string `json:"readers" toml:"readers" env:"METRICS_READERS" env-default:"prometheus"`
+		// URL — коллектор для otlp-*; пусто — TRACING_URL. TLS и заголовки
+		// общие с трейсингом.
+		URL string `json:"url" toml:"url" env:"METRICS_URL"`
+		// Interval — период push.
+		Interval time.Duration `json:"interval" toml:"interval" env:"METRICS_INTERVAL" env-default:"30s"`
+	}
+
+	// UserCache — кэш чтений пользователей (repository.CachedUserRepository):
+	// LRU в памяти каждой реплики, инвалидация между репликами через
+	// LISTEN/NOTIFY. Только с DB_BACKEND=postgres.
+	UserCache struct {
+		Enabled bool `json:"enabled" toml:"enabled" env:"USER_CACHE_ENABLED" env-default:"true"`
+		// Size — предел записей на каждый из кэшей (пользователи, страницы списка).
+		Size int `json:"size" toml:"size" env:"USER_CACHE_SIZE" env-default:"10000"`
+		// TTL ограничивает устаревание, если уведомление об инвалидации
+		// потерялось.
+		TTL time.Duration `json:"ttl" toml:"ttl" env:"USER_CACHE_TTL" env-default:"1m"`
+	}
+
+	// Outbox — relay доменных событий из таблицы outbox (internal/outbox).
+	Outbox struct {
+		Enabled      bool          `json:"enabled"       toml:"enabled"       env:"OUTBOX_ENABLED"       env-default:"true"`
+		Publisher    string        `json:"publisher"     toml:"publisher"     env:"OUTBOX_PUBLISHER"     env-default:"log"`
+		WebhookURL   string        `json:"webhook_url"   toml:"webhook_url"   env:"OUTBOX_WEBHOOK_URL"`
+		BatchSize    int           `json:"batch_size"    toml:"batch_size"    env:"OUTBOX_BATCH_SIZE"    env-default:"100"`
+		MaxAttempts  int           `json:"max_attempts"  toml:"max_attempts"  env:"OUTBOX_MAX_ATTEMPTS"  env-default:"10"`
+		PollInterval time.Duration `json:"poll_interval" toml:"poll_interval" env:"OUTBOX_POLL_INTERVAL" env-default:"1s"`
+		// PublishTimeout ограничивает одну публикацию; по нему relay не
+		// начинает публикацию, которая не успеет до конца lease пачки.
+		PublishTimeout time.Duration `json:"publish_timeout" toml:"publish_timeout" env:"OUTBOX_PUBLISH_TIMEOUT" env-default:"5s"`
+	}
+
+	// Webhooks — доставка событий счёта партнёрам (internal/webhook). Доставки
+	// создаются из outbox, поэтому без Outbox.Enabled не работают.
+	Webhooks struct {
+		Enabled     bool `json:"enabled"      toml:"enabled"      env:"WEBHOOKS_ENABLED"      env-default:"true"`
+		BatchSize   int  `json:"batch_size"   toml:"batch_size"   env:"WEBHOOKS_BATCH_SIZE"   env-default:"50"`
+		MaxAttempts int  `json:"max_attempts" toml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+		// DisableAfter — столько неудачных попыток подряд выключают подписку.
+		DisableAfter int           `json:"disable_after" toml:"disable_after" env:"WEBHOOKS_DISABLE_AFTER" env-default:"20"`
+		Timeout      time.Duration `json:"timeout"       toml:"timeout"       env:"WEBHOOKS_TIMEOUT"       env-default:"5s"`
+		PollInterval time.Duration `json:"poll_interval" toml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL" env-default:"1s"`
+	}
+
+	// SSE — потоки событий счёта (GET /user/{id}/events).
+	SSE struct {
+		// MaxStreams — предел одновременных потоков на инстанс: каждый держит
+		// соединение и горутину.
+		MaxStreams int `json:"max_streams" toml:"max_streams" env:"SSE_MAX_STREAMS" env-default:"1000" reload:"true"`
+		// Heartbeat — период комментария-пинга в простаивающем потоке.
+		Heartbeat time.Duration `json:"heartbeat" toml:"heartbeat" env:"SSE_HEARTBEAT" env-default:"15s"`
+	}
+
+	// RateLimit — token bucket на операцию и клиента (middleware.RateLimit).
+	// Квоты — строки "<запросов>/<период>", поэтому задаются и в файле.
+	RateLimit struct {
+		Enabled bool `json:"enabled" toml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"true" reload:"true"`
+		// Backend: memory — лимиты у каждой реплики свои; postgres — общие.
+		Backend string `json:"backend" toml:"backend" env:"RATE_LIMIT_BACKEND" env-default:"memory"`
+		// Default — квота операций без своей записи в Routes.
+		Default string `json:"default" toml:"default" env:"RATE_LIMIT_DEFAULT" env-default:"300/1m" reload:"true"`
+		// Routes: "<METHOD> <путь>" → квота; путь — префикс по сегментам.
+		// В env: "POST /transfer:10/1m,GET /users:120/1m".
+		Routes map[string]string `json:"routes" toml:"routes" env:"RATE_LIMIT_ROUTES" env-default:"POST /transfer:10/1m,GET /users:120/1m" reload:"true"`
 	}
 )
 
+// Environment — окружение развёртывания (ENV_NAME).
+type Environment string
+
+const (
+	// EnvDev — локальная разработка: текстовые логи, pprof, /monitor и
+	// gRPC reflection.
+	EnvDev Environment = "dev"
+	// EnvTest — прогоны тестов в CI.
+	EnvTest Environment = "test"
+	// EnvStage — предпродакшен: как prod по набору сервисов, логи текстовые.
+	EnvStage Environment = "stage"
+	// EnvProd — продакшен: JSON-логи и структурированный access-лог.
+	EnvProd Environment = "prod"
+)
+
+// Environments — допустимые значения ENV_NAME.
+var Environments = []Environment{EnvDev, EnvTest, EnvStage, EnvProd}
+
 // DSN возвращает строку подключения к Postgres; единая точка для пула и мигратора.
+// Части экранируются: пароль может содержать '@', '/' и ':'.
 func (db DB) DSN() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		db.DBUser, db.DBPassword, db.DBHost, db.DBPort, db.DBName, db.SSLMode)
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(db.DBUser, db.DBPassword),
+		Host:     net.JoinHostPort(db.DBHost, strconv.Itoa(db.DBPort)),
+		Path:     "/" + db.DBName,
+		RawQuery: url.Values{"sslmode": {db.SSLMode}}.Encode(),
+	}
+	return dsn.String()
+}
+
+// ReplicaDSN — строка подключения к реплике host[:port] с учётными данными primary.
+func (db DB) ReplicaDSN(host string) (string, error) {
+	port := db.DBPort
+	if strings.Contains(host, ":") {
+		h, p, err := net.SplitHostPort(host)
+		if err != nil {
+			return "", fmt.Errorf("replica host %q: %w", host, err)
+		}
+		if port, err = strconv.Atoi(p); err != nil {
+			return "", fmt.Errorf("replica host %q: %w", host, err)
+		}
+		host = h
+	}
+
+	db.DBHost, db.DBPort = host, port
+
+	return db.DSN(), nil
 }
 
 // LoadConfig ищет файл конфигурации в следующем порядке:
@@ -76,18 +285,57 @@ func (db DB) DSN() string {
 //     (так работают Docker-образ и запуск из корня репозитория);
 //  3. рядом с исходником пакета (запуск тестов и go run из произвольной директории).
 //
-// Значения из env перекрывают значения из файла.
+// Значения из env перекрывают значения из файла. В полях secret:"true"
+// вместо значения можно указать ссылку на секрет ("file:///run/secrets/db_password",
+// "env:PG_PASSWORD") — см. SecretProvider. Результат проверяется
+// (Validate): при ошибке проверки возвращается *ValidationError вместе с
+// конфигом — его печатает `config check`.
 func LoadConfig() (*Config, error) {
-	cfg := &Config{}
+	return load(configPath())
+}
+
+func load(path string) (*Config, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := cleanenv.ReadConfig(configPath(), cfg); err != nil {
+	return cfg, cfg.Validate()
+}
+
+// read читает файл и env и подставляет секреты по ссылкам (SecretProvider),
+// без проверки.
+func read(path string) (*Config, error) {
+	fromFile := &Config{}
+	set, err := readFile(path, fromFile)
+	if err != nil {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
 
+	if err := checkLegacyDurationEnv(); err != nil {
+		return nil, fmt.Errorf("env read error: %w", err)
+	}
+
+	cfg := &Config{}
+	*cfg = *fromFile
 	if err := cleanenv.ReadEnv(cfg); err != nil {
 		return nil, fmt.Errorf("env read error: %w", err)
 	}
 
+	// cleanenv подставляет env-default в любое нулевое поле, в том числе в
+	// явные false и 0 из файла; возвращаем их, если env поле не задаёт.
+	// Приоритет: env, затем файл, затем env-default.
+	cv, fv := reflect.ValueOf(cfg).Elem(), reflect.ValueOf(fromFile).Elem()
+	for _, f := range configFields {
+		if _, fromEnv := os.LookupEnv(f.env); set[f.path] && !fromEnv {
+			cv.FieldByIndex(f.index).Set(fv.FieldByIndex(f.index))
+		}
+	}
+
+	if err := cfg.resolveSecrets(context.Background()); err != nil {
+		return nil, fmt.Errorf("config secrets: %w", err)
+	}
+
 	if strings.Contains(cfg.DBHost, ":") {
 		host, port, err := net.SplitHostPort(cfg.DBHost)
 		if err != nil {
@@ -103,6 +351,59 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// HTTPPort читает только порт HTTP (env, затем файл, затем default) — без
+// проверки обязательных полей остальной конфигурации. Нужен healthcheck,
+// который Docker запускает каждые несколько секунд.
+func HTTPPort() (string, error) {
+	var cfg struct {
+		HTTP struct {
+			Port string `json:"port" toml:"port" env:"HTTP_PORT" env-default:"8000"`
+		} `json:"http" toml:"http"`
+	}
+
+	if err := cleanenv.ReadConfig(configPath(), &cfg); err != nil {
+		return "", fmt.Errorf("config error: %w", err)
+	}
+
+	return cfg.HTTP.Port, nil
+}
+
+// _legacyDurations — поля, которые до перехода на строки длительностей
+// задавались голым числом, и единица этого числа. Число в них теперь
+// ошибка; сообщение подсказывает значение с единицей.
+var _legacyDurations = []struct {
+	env, key, unitName, suffix string
+}{
+	{env: "PG_POOL_CONN_TIMEOUT", key: "db.connect_timeout", unitName: "seconds", suffix: "s"},
+	{env: "PG_POOL_HEALTHCHECK", key: "db.health_check_period", unitName: "minutes", suffix: "m"},
+}
+
+func checkLegacyDurationEnv() error {
+	for _, legacy := range _legacyDurations {
+		value := os.Getenv(legacy.env)
+		if _, err := strconv.Atoi(value); err == nil {
+			return fmt.Errorf("%s=%s: durations need a unit, use %s=%q (bare numbers meant %s before)",
+				legacy.env, value, legacy.env, value+legacy.suffix, legacy.unitName)
+		}
+	}
+
+	return nil
+}
+
+// legacyDurationHint — подсказка для числа в файле на месте длительности.
+func legacyDurationHint(key string, value any) string {
+	for _, legacy := range _legacyDurations {
+		if legacy.key != key {
+			continue
+		}
+		if _, err := strconv.Atoi(fmt.Sprint(value)); err == nil {
+			return fmt.Sprintf(" (bare numbers meant %s before, use %q)", legacy.unitName, fmt.Sprint(value)+legacy.suffix)
+		}
+	}
+
+	return ""
+}
+
 func configPath() string {
 	if path := os.Getenv("CONFIG_PATH"); path != "" {
 		return path
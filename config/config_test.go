@@ -3,9 +3,12 @@ package config
 import (
 	"log/slog"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -32,6 +35,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.NotNil(t, cfg)
 	assert.Equal(t, "test-app", cfg.App.Name)
 	assert.Equal(t, "8080", cfg.HTTP.Port)
+	assert.Equal(t, "8001", cfg.GRPC.Port)
 	assert.Equal(t, "localhost", cfg.DB.DBHost)
 	assert.Equal(t, 5432, cfg.DB.DBPort)
 	assert.Equal(t, "testuser", cfg.DB.DBUser)
@@ -40,8 +44,44 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, int32(10), cfg.DB.PoolMax)
 	assert.Equal(t, slog.LevelInfo, cfg.Log.Level)
 	assert.Equal(t, "http://localhost:14268/api/traces", cfg.Tracing.URL)
-	assert.Equal(t, "migrations", cfg.DB.MigrationsDir)
+	assert.Equal(t, "otlp-grpc", cfg.Tracing.Exporter)
+	assert.Equal(t, "parentbased_traceidratio", cfg.Tracing.Sampler)
+	assert.InDelta(t, 0.6, cfg.Tracing.SampleRatio, 1e-9)
+	assert.True(t, cfg.Tracing.DBSpans)
+	assert.Equal(t, []string{"prometheus"}, cfg.Metrics.Readers)
+	assert.Equal(t, 30*time.Second, cfg.Metrics.Interval)
+	assert.Empty(t, cfg.DB.MigrationsDir, "по умолчанию — миграции, встроенные в бинарь")
+	assert.Equal(t, "postgres", cfg.DB.Backend)
 	assert.Equal(t, "slog", cfg.Log.Backend)
+	assert.True(t, cfg.UserCache.Enabled)
+	assert.Equal(t, 10000, cfg.UserCache.Size)
+	assert.Equal(t, time.Minute, cfg.UserCache.TTL)
+	assert.True(t, cfg.Outbox.Enabled)
+	assert.Equal(t, "log", cfg.Outbox.Publisher)
+	assert.Equal(t, time.Second, cfg.Outbox.PollInterval)
+	assert.True(t, cfg.Webhooks.Enabled)
+	assert.Equal(t, 20, cfg.Webhooks.DisableAfter)
+	assert.Equal(t, 5*time.Second, cfg.Webhooks.Timeout)
+	assert.Equal(t, 1000, cfg.SSE.MaxStreams)
+	assert.Equal(t, 15*time.Second, cfg.SSE.Heartbeat)
+	assert.Equal(t, "10/1m", cfg.RateLimit.Routes["POST /transfer"])
+}
+
+func TestReplicaDSN(t *testing.T) {
+	t.Parallel()
+
+	db := DB{DBHost: "primary", DBPort: 5432, DBUser: "u", DBPassword: "p", DBName: "demo", SSLMode: "disable"}
+
+	dsn, err := db.ReplicaDSN("replica-1")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://u:p@replica-1:5432/demo?sslmode=disable", dsn)
+
+	dsn, err = db.ReplicaDSN("replica-2:6432")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://u:p@replica-2:6432/demo?sslmode=disable", dsn)
+
+	_, err = db.ReplicaDSN("replica:port")
+	require.Error(t, err)
 }
 
 func TestLoadConfigMissingRequiredField(t *testing.T) {
@@ -58,3 +98,20 @@ func TestLoadConfigMissingRequiredField(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "DBPassword")
 }
+
+func TestHTTPPortSkipsRequiredFields(t *testing.T) {
+	os.Clearenv()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[http]\nport = \"8080\"\n"), 0o600))
+	t.Setenv("CONFIG_PATH", path)
+
+	port, err := HTTPPort()
+	require.NoError(t, err)
+	assert.Equal(t, "8080", port)
+
+	t.Setenv("HTTP_PORT", "9000")
+	port, err = HTTPPort()
+	require.NoError(t, err)
+	assert.Equal(t, "9000", port)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// readFile разбирает файл конфигурации (.toml или .json) в cfg и
+// возвращает пути (configField.path) полей, заданных в файле: по ним read
+// отличает явные false и 0 от незаданных полей, которым положен env-default.
+//
+// Длительности в обоих форматах — строки time.ParseDuration ("500ms", "2m"),
+// как и в env: encoding/json их не понимает, а toml молча читает число как
+// наносекунды. Поэтому файл сначала разбирается в дерево, длительности в нём
+// по типам полей Config переводятся в наносекунды (голое число — ошибка), и
+// дерево декодируется в cfg по json-тегам: toml-теги совпадают с ними
+// (TestConfigFieldsHaveAllSources).
+func readFile(path string, cfg *Config) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		if _, err := toml.Decode(string(data), &tree); err != nil {
+			return nil, fmt.Errorf("config file parsing error: %w", err)
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&tree); err != nil {
+			return nil, fmt.Errorf("config file parsing error: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q (want .toml or .json)", ext)
+	}
+
+	if err := normalizeDurations(reflect.TypeFor[Config](), tree, ""); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	normalized, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(normalized, cfg); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	set := map[string]bool{}
+	for _, f := range configFields {
+		if hasKey(tree, f.key) {
+			set[f.path] = true
+		}
+	}
+
+	return set, nil
+}
+
+// hasKey — задан ли в дереве файла ключ вида "section.name".
+func hasKey(tree map[string]any, key string) bool {
+	node := tree
+	for {
+		head, rest, nested := strings.Cut(key, ".")
+		value, ok := node[head]
+		if !ok || !nested {
+			return ok
+		}
+		if node, ok = value.(map[string]any); !ok {
+			return false
+		}
+		key = rest
+	}
+}
+
+// normalizeDurations заменяет в node строки длительностей на наносекунды;
+// prefix — путь секции в файле для сообщений об ошибках.
+func normalizeDurations(t reflect.Type, node map[string]any, prefix string) error {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		key, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		value, ok := node[key]
+		if key == "" || key == "-" || !ok {
+			continue
+		}
+
+		switch {
+		case sf.Type == durationType:
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s%s: want a duration string such as \"500ms\" or \"2m\", got %v%s",
+					prefix, key, value, legacyDurationHint(prefix+key, value))
+			}
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", prefix, key, err)
+			}
+			node[key] = int64(d)
+		case sf.Type.Kind() == reflect.Struct:
+			section, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s%s: want a section, got %v", prefix, key, value)
+			}
+			if err := normalizeDurations(sf.Type, section, prefix+key+"."); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShippedConfigFilesMatch — config.toml и config.json из репозитория
+// описывают один и тот же конфиг: Docker-образ берёт json, локальный запуск — toml.
+func TestShippedConfigFilesMatch(t *testing.T) {
+	t.Parallel()
+
+	var fromTOML, fromJSON Config
+	_, err := readFile("config.toml", &fromTOML)
+	require.NoError(t, err)
+	_, err = readFile("config.json", &fromJSON)
+	require.NoError(t, err)
+
+	assert.Equal(t, fromTOML, fromJSON)
+	assert.Equal(t, 2*time.Second, fromTOML.ConnectTimeout)
+	assert.Equal(t, 2*time.Minute, fromTOML.HealthCheckPeriod)
+	assert.Equal(t, 10*time.Millisecond, fromTOML.TxRetryBaseDelay)
+}
+
+// TestConfigFieldsHaveAllSources — каждое поле задаётся и в файле (с
+// одинаковым ключом в toml и json), и в env.
+func TestConfigFieldsHaveAllSources(t *testing.T) {
+	t.Parallel()
+
+	var check func(typ reflect.Type, prefix string)
+	check = func(typ reflect.Type, prefix string) {
+		for i := range typ.NumField() {
+			sf := typ.Field(i)
+			path := prefix + sf.Name
+
+			jsonKey, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			assert.NotEmpty(t, jsonKey, "%s: no json tag", path)
+			assert.Equal(t, jsonKey, sf.Tag.Get("toml"), "%s: toml and json keys differ", path)
+
+			if sf.Type.Kind() == reflect.Struct {
+				check(sf.Type, path+".")
+				continue
+			}
+			assert.NotEmpty(t, sf.Tag.Get("env"), "%s: no env tag", path)
+		}
+	}
+	check(reflect.TypeFor[Config](), "")
+}
+
+func TestReadFileDurations(t *testing.T) {
+	t.Parallel()
+
+	for name, data := range map[string]string{
+		"config.toml": "[db]\nconnect_timeout = \"500ms\"\n\n[sse]\nheartbeat = \"2m\"\n",
+		"config.json": `{"db": {"connect_timeout": "500ms"}, "sse": {"heartbeat": "2m"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+			var cfg Config
+			_, err := readFile(path, &cfg)
+			require.NoError(t, err)
+			assert.Equal(t, 500*time.Millisecond, cfg.ConnectTimeout)
+			assert.Equal(t, 2*time.Minute, cfg.Heartbeat)
+		})
+	}
+}
+
+func TestReadFileRejectsBareNumbers(t *testing.T) {
+	t.Parallel()
+
+	for name, data := range map[string]string{
+		"config.toml": "[db]\nconnect_timeout = 5\n",
+		"config.json": `{"db": {"connect_timeout": 5}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+			_, err := readFile(path, &Config{})
+			require.ErrorContains(t, err, `db.connect_timeout: want a duration string such as "500ms" or "2m", got 5`)
+			require.ErrorContains(t, err, `bare numbers meant seconds before, use "5s"`)
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[db]\nconnect_timeout = \"5 sec\"\n"), 0o600))
+	_, err := readFile(path, &Config{})
+	require.ErrorContains(t, err, "db.connect_timeout: time: unknown unit")
+}
+
+// TestReadRejectsLegacyBareNumberEnv — PG_POOL_CONN_TIMEOUT и
+// PG_POOL_HEALTHCHECK раньше были числами секунд и минут; ошибка называет
+// значение с единицей.
+func TestReadRejectsLegacyBareNumberEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[db]\n"), 0o600))
+
+	t.Setenv("PG_POOL_CONN_TIMEOUT", "2")
+	_, err := read(path)
+	require.ErrorContains(t, err, `PG_POOL_CONN_TIMEOUT=2: durations need a unit, use PG_POOL_CONN_TIMEOUT="2s"`)
+
+	t.Setenv("PG_POOL_CONN_TIMEOUT", "2s")
+	t.Setenv("PG_POOL_HEALTHCHECK", "2")
+	_, err = read(path)
+	require.ErrorContains(t, err, `PG_POOL_HEALTHCHECK="2m" (bare numbers meant minutes before)`)
+}
+
+// TestReadKeepsExplicitZeroValues — false и 0 из файла не заменяются
+// env-default; env по-прежнему перекрывает файл.
+func TestReadKeepsExplicitZeroValues(t *testing.T) {
+	unsetEnv(t, "RATE_LIMIT_ENABLED", "USER_CACHE_ENABLED", "OUTBOX_ENABLED", "WEBHOOKS_ENABLED",
+		"TRACING_INSECURE", "TRACING_DB_SPANS", "TRACING_SAMPLE_RATIO", "PG_STATEMENT_TIMEOUT",
+		"DB_PASSWORD", "PG_POOL_MAX", "SSE_MAX_STREAMS")
+	t.Setenv("WEBHOOKS_ENABLED", "true")
+
+	for name, data := range map[string]string{
+		"config.toml": `
+[db]
+password = "secret"
+pool_max = 2
+statement_timeout = "0s"
+
+[tracing]
+insecure = false
+db_spans = false
+sample_ratio = 0
+
+[user_cache]
+enabled = false
+
+[outbox]
+enabled = false
+
+[webhooks]
+enabled = false
+
+[rate_limit]
+enabled = false
+`,
+		"config.json": `{
+  "db": {"password": "secret", "pool_max": 2, "statement_timeout": "0s"},
+  "tracing": {"insecure": false, "db_spans": false, "sample_ratio": 0},
+  "user_cache": {"enabled": false},
+  "outbox": {"enabled": false},
+  "webhooks": {"enabled": false},
+  "rate_limit": {"enabled": false}
+}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+			cfg, err := read(path)
+			require.NoError(t, err)
+
+			assert.False(t, cfg.RateLimit.Enabled)
+			assert.False(t, cfg.UserCache.Enabled)
+			assert.False(t, cfg.Outbox.Enabled)
+			assert.False(t, cfg.Tracing.Insecure)
+			assert.False(t, cfg.Tracing.DBSpans)
+			assert.Zero(t, cfg.Tracing.SampleRatio)
+			assert.Zero(t, cfg.StatementTimeout)
+
+			assert.True(t, cfg.Webhooks.Enabled, "env перекрывает файл")
+			assert.Equal(t, 1000, cfg.MaxStreams, "незаданное в файле поле получает env-default")
+			assert.Equal(t, 5*time.Second, cfg.LockTimeout)
+		})
+	}
+}
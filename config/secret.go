@@ -0,0 +1,152 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SecretProvider достаёт секрет по ссылке вида "<схема>:...": значение поля
+// с тегом secret:"true" (DB_PASSWORD, значения TRACING_HEADERS) может быть
+// не самим секретом, а ссылкой на него. Встроены схемы file и env; другие
+// (например, vault) подключаются через RegisterSecretProvider.
+type SecretProvider interface {
+	Secret(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// FileSecrets — "file:///run/secrets/db_password": содержимое файла без
+// завершающего перевода строки (так монтируют секреты Kubernetes и Docker).
+type FileSecrets struct{}
+
+func (FileSecrets) Secret(_ context.Context, ref *url.URL) (string, error) {
+	path := ref.Path
+	if ref.Opaque != "" {
+		// "file:secrets/db_password" — путь относительно рабочей директории.
+		path = ref.Opaque
+	}
+	if path == "" || ref.Host != "" {
+		return "", fmt.Errorf("want file:///absolute/path or file:relative/path, got %q", ref.Redacted())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// EnvSecrets — "env:NAME": значение переменной окружения NAME.
+type EnvSecrets struct{}
+
+func (EnvSecrets) Secret(_ context.Context, ref *url.URL) (string, error) {
+	name := ref.Opaque
+	if name == "" {
+		return "", fmt.Errorf("want env:NAME, got %q", ref.Redacted())
+	}
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return value, nil
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"file": FileSecrets{},
+		"env":  EnvSecrets{},
+	}
+)
+
+// RegisterSecretProvider подключает provider для ссылок со схемой scheme
+// (заменяет прежний; nil отключает схему). Вызывается до LoadConfig —
+// обычно из init пакета провайдера, как sql.Register.
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+
+	scheme = strings.ToLower(scheme)
+	if provider == nil {
+		delete(secretProviders, scheme)
+		return
+	}
+	secretProviders[scheme] = provider
+}
+
+// secretProvider — провайдер ссылки value; nil, если value — не ссылка, а сам
+// секрет (схема не зарегистрирована или её нет).
+func secretProvider(value string) (SecretProvider, *url.URL) {
+	scheme, _, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, nil
+	}
+
+	secretProvidersMu.RLock()
+	provider := secretProviders[strings.ToLower(scheme)]
+	secretProvidersMu.RUnlock()
+	if provider == nil {
+		return nil, nil
+	}
+
+	ref, err := url.Parse(value)
+	if err != nil {
+		return nil, nil
+	}
+
+	return provider, ref
+}
+
+// resolveSecrets заменяет ссылки в полях secret:"true" значениями секретов.
+// Ошибки собираются по всем полям; сами значения в них не попадают.
+func (c *Config) resolveSecrets(ctx context.Context) error {
+	resolve := func(f configField, value string) (string, error) {
+		provider, ref := secretProvider(value)
+		if provider == nil {
+			return value, nil
+		}
+		secret, err := provider.Secret(ctx, ref)
+		if err != nil {
+			return "", fmt.Errorf("%s (%s): resolve %s secret: %w", f.path, f.env, ref.Scheme, err)
+		}
+		return secret, nil
+	}
+
+	cv := reflect.ValueOf(c).Elem()
+
+	var errs []error
+	for _, f := range configFields {
+		if !f.secret {
+			continue
+		}
+
+		v := cv.FieldByIndex(f.index)
+		switch v.Kind() {
+		case reflect.String:
+			secret, err := resolve(f, v.String())
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			v.SetString(secret)
+		case reflect.Map:
+			for _, key := range v.MapKeys() {
+				secret, err := resolve(f, v.MapIndex(key).String())
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				v.SetMapIndex(key, reflect.ValueOf(secret))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSecrets map[string]string
+
+func (s staticSecrets) Secret(_ context.Context, ref *url.URL) (string, error) {
+	secret, ok := s[ref.Host+ref.Path]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
+
+func TestResolveSecrets(t *testing.T) {
+	RegisterSecretProvider("test-vault", staticSecrets{"kv/tracing": "Bearer vault-token"})
+	t.Cleanup(func() { RegisterSecretProvider("test-vault", nil) })
+
+	path := filepath.Join(t.TempDir(), "db_password")
+	require.NoError(t, os.WriteFile(path, []byte("p@ss/word:1\n"), 0o600))
+	t.Setenv("TEST_TENANT", "demo")
+
+	cfg := &Config{
+		DB: DB{DBPassword: "file://" + path},
+		Tracing: Tracing{Headers: map[string]string{
+			"authorization": "test-vault://kv/tracing",
+			"x-tenant":      "env:TEST_TENANT",
+			"x-plain":       "jaeger:4317",
+		}},
+	}
+	require.NoError(t, cfg.resolveSecrets(context.Background()))
+
+	assert.Equal(t, "p@ss/word:1", cfg.DBPassword)
+	assert.Equal(t, map[string]string{
+		"authorization": "Bearer vault-token",
+		"x-tenant":      "demo",
+		"x-plain":       "jaeger:4317",
+	}, cfg.Tracing.Headers, "незарегистрированная схема — не ссылка")
+}
+
+func TestResolveSecretsCollectsErrors(t *testing.T) {
+	unsetEnv(t, "TEST_MISSING")
+
+	cfg := &Config{
+		DB:      DB{DBPassword: "file:///nonexistent/db_password"},
+		Tracing: Tracing{Headers: map[string]string{"authorization": "env:TEST_MISSING"}},
+	}
+	err := cfg.resolveSecrets(context.Background())
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.ErrorContains(t, err, "DB.DBPassword (DB_PASSWORD): resolve file secret")
+	assert.ErrorContains(t, err, "Tracing.Headers (TRACING_HEADERS): resolve env secret: environment variable TEST_MISSING is not set")
+}
+
+func TestLoadResolvesPasswordFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_password")
+	require.NoError(t, os.WriteFile(path, []byte("s3cr@t/pass\n"), 0o600))
+
+	shippedConfig(t)
+	t.Setenv("DB_PASSWORD", "file://"+path)
+
+	cfg, err := load("config.toml")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr@t/pass", cfg.DBPassword)
+}
+
+func TestDSNEscapesCredentials(t *testing.T) {
+	t.Parallel()
+
+	db := DB{DBHost: "db.internal", DBPort: 5432, DBUser: "app@corp", DBPassword: "p@ss/w:rd?#%", DBName: "demo", SSLMode: "require"}
+
+	conn, err := pgx.ParseConfig(db.DSN())
+	require.NoError(t, err)
+	assert.Equal(t, "app@corp", conn.User)
+	assert.Equal(t, "p@ss/w:rd?#%", conn.Password)
+	assert.Equal(t, "db.internal", conn.Host)
+	assert.Equal(t, uint16(5432), conn.Port)
+	assert.Equal(t, "demo", conn.Database)
+}
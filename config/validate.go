@@ -0,0 +1,227 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError — проблема в одном поле конфига.
+type FieldError struct {
+	// Path — путь поля в Config, например "DB.PoolMin".
+	Path string
+	// Env — переменная окружения поля; пусто, если её нет.
+	Env string
+	Msg string
+}
+
+func (e FieldError) Error() string {
+	if e.Env == "" {
+		return e.Path + ": " + e.Msg
+	}
+	return e.Path + " (" + e.Env + "): " + e.Msg
+}
+
+// ValidationError — все проблемы конфига разом: исправить их можно за один
+// заход, а не по одной на перезапуск.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config (%d problems):", len(e.Fields))
+	for _, f := range e.Fields {
+		b.WriteString("\n  - ")
+		b.WriteString(f.Error())
+	}
+	return b.String()
+}
+
+// Допустимые значения строковых перечислений; те же имена разбирают
+// pkg/logger, pkg/tracing и internal/outbox (импортировать их отсюда
+// нельзя — они сами импортируют config).
+var (
+	dbBackends        = []string{"postgres", "memory"}
+	sslModes          = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logBackends       = []string{"slog", "zerolog"}
+	tracingExporters  = []string{"otlp-grpc", "otlp-http", "stdout", "none"}
+	tracingSamplers   = []string{"always_on", "always_off", "traceidratio", "parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio"}
+	metricsReaders    = []string{"prometheus", "otlp-grpc", "otlp-http"}
+	outboxPublishers  = []string{"log", "webhook"}
+	rateLimitBackends = []string{"memory", "postgres"}
+)
+
+// Validate проверяет конфиг целиком и возвращает *ValidationError со всеми
+// найденными проблемами или nil. Квоты RATE_LIMIT_* разбирает приложение
+// (pkg/ratelimit импортирует config).
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	oneOf(v, "App.Environment", c.Environment, Environments...)
+	v.check(c.ReloadInterval >= 0, "App.ReloadInterval", "must not be negative, got %s", c.ReloadInterval)
+
+	v.port("HTTP.Port", c.HTTP.Port)
+	v.port("GRPC.Port", c.GRPC.Port)
+	v.check(c.HTTP.Port == "" || c.HTTP.Port != c.GRPC.Port, "GRPC.Port", "must differ from HTTP.Port, both are %s", c.GRPC.Port)
+	v.positive("HTTP.ReadTimeout", c.ReadTimeout)
+	v.positive("HTTP.WriteTimeout", c.WriteTimeout)
+	v.positive("HTTP.IdleTimeout", c.IdleTimeout)
+	v.positive("HTTP.RequestTimeout", c.RequestTimeout)
+	v.positive("HTTP.ShutdownTimeout", c.ShutdownTimeout)
+
+	c.validateDB(v)
+
+	oneOf(v, "Log.Backend", c.Log.Backend, logBackends...)
+
+	c.validateTelemetry(v)
+
+	if c.UserCache.Enabled {
+		v.check(c.UserCache.Size > 0, "UserCache.Size", "must be positive, got %d", c.UserCache.Size)
+		v.positive("UserCache.TTL", c.UserCache.TTL)
+	}
+
+	if c.Outbox.Enabled {
+		oneOf(v, "Outbox.Publisher", c.Outbox.Publisher, outboxPublishers...)
+		if c.Outbox.Publisher == "webhook" {
+			v.httpURL("Outbox.WebhookURL", c.Outbox.WebhookURL)
+		}
+		v.check(c.Outbox.BatchSize > 0, "Outbox.BatchSize", "must be positive, got %d", c.Outbox.BatchSize)
+		v.check(c.Outbox.MaxAttempts > 0, "Outbox.MaxAttempts", "must be positive, got %d", c.Outbox.MaxAttempts)
+		v.positive("Outbox.PollInterval", c.Outbox.PollInterval)
+	}
+
+	if c.Webhooks.Enabled {
+		v.check(c.Webhooks.BatchSize > 0, "Webhooks.BatchSize", "must be positive, got %d", c.Webhooks.BatchSize)
+		v.check(c.Webhooks.MaxAttempts > 0, "Webhooks.MaxAttempts", "must be positive, got %d", c.Webhooks.MaxAttempts)
+		v.check(c.Webhooks.DisableAfter > 0, "Webhooks.DisableAfter", "must be positive, got %d", c.Webhooks.DisableAfter)
+		v.positive("Webhooks.Timeout", c.Webhooks.Timeout)
+		v.positive("Webhooks.PollInterval", c.Webhooks.PollInterval)
+	}
+
+	v.check(c.SSE.MaxStreams > 0, "SSE.MaxStreams", "must be positive, got %d", c.SSE.MaxStreams)
+	v.positive("SSE.Heartbeat", c.SSE.Heartbeat)
+
+	oneOf(v, "RateLimit.Backend", c.RateLimit.Backend, rateLimitBackends...)
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.errs}
+}
+
+func (c *Config) validateDB(v *validator) {
+	oneOf(v, "DB.Backend", c.DB.Backend, dbBackends...)
+	if c.DB.Backend != "postgres" {
+		return
+	}
+
+	v.check(c.DBHost != "", "DB.DBHost", "is required")
+	v.check(c.DBPort > 0 && c.DBPort <= 65535, "DB.DBPort", "must be a port number (1-65535), got %d", c.DBPort)
+	v.check(c.DBUser != "", "DB.DBUser", "is required")
+	v.check(c.DBPassword != "", "DB.DBPassword", "is required")
+	v.check(c.DBName != "", "DB.DBName", "is required")
+	oneOf(v, "DB.SSLMode", c.SSLMode, sslModes...)
+
+	v.check(c.PoolMax > 0, "DB.PoolMax", "is required and must be positive, got %d", c.PoolMax)
+	v.check(c.PoolMin >= 0, "DB.PoolMin", "must not be negative, got %d", c.PoolMin)
+	v.check(c.PoolMin <= c.PoolMax, "DB.PoolMin", "must not exceed DB.PoolMax (%d), got %d", c.PoolMax, c.PoolMin)
+	v.positive("DB.ConnectTimeout", c.ConnectTimeout)
+	v.positive("DB.HealthCheckPeriod", c.HealthCheckPeriod)
+
+	v.nonNegative("DB.StatementTimeout", c.StatementTimeout)
+	v.nonNegative("DB.LockTimeout", c.LockTimeout)
+	v.nonNegative("DB.IdleInTxTimeout", c.IdleInTxTimeout)
+
+	v.check(c.TxMaxAttempts >= 1, "DB.TxMaxAttempts", "must be at least 1, got %d", c.TxMaxAttempts)
+	v.positive("DB.TxRetryBaseDelay", c.TxRetryBaseDelay)
+	v.check(c.TxRetryMaxDelay >= c.TxRetryBaseDelay, "DB.TxRetryMaxDelay", "must not be less than DB.TxRetryBaseDelay (%s), got %s", c.TxRetryBaseDelay, c.TxRetryMaxDelay)
+
+	for _, host := range c.ReplicaHosts {
+		_, err := c.ReplicaDSN(host)
+		v.check(err == nil && host != "", "DB.ReplicaHosts", "want host or host:port, got %q", host)
+	}
+	if len(c.ReplicaHosts) > 0 {
+		v.positive("DB.ReplicaMaxLag", c.ReplicaMaxLag)
+		v.positive("DB.ReplicaCheckPeriod", c.ReplicaCheckPeriod)
+	}
+}
+
+func (c *Config) validateTelemetry(v *validator) {
+	oneOf(v, "Tracing.Exporter", c.Tracing.Exporter, tracingExporters...)
+	if strings.HasPrefix(c.Tracing.Exporter, "otlp-") {
+		v.endpoint("Tracing.URL", c.Tracing.URL)
+	}
+	oneOf(v, "Tracing.Sampler", c.Tracing.Sampler, tracingSamplers...)
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "Tracing.SampleRatio", "must be within [0, 1], got %v", c.Tracing.SampleRatio)
+	v.check((c.Tracing.CertFile == "") == (c.Tracing.KeyFile == ""), "Tracing.KeyFile", "TRACING_CERT_FILE and TRACING_KEY_FILE must be set together")
+
+	pushes := false
+	for _, reader := range c.Metrics.Readers {
+		reader = strings.TrimSpace(reader)
+		oneOf(v, "Metrics.Readers", reader, metricsReaders...)
+		pushes = pushes || strings.HasPrefix(reader, "otlp-")
+	}
+	if pushes {
+		if c.Metrics.URL != "" {
+			v.endpoint("Metrics.URL", c.Metrics.URL)
+		} else if !strings.HasPrefix(c.Tracing.Exporter, "otlp-") {
+			// Иначе адрес уже проверен выше как Tracing.URL.
+			v.endpoint("Tracing.URL", c.Tracing.URL)
+		}
+		v.positive("Metrics.Interval", c.Metrics.Interval)
+	}
+}
+
+// validator копит FieldError; env поля берётся из его тега.
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) check(ok bool, path, format string, args ...any) {
+	if ok {
+		return
+	}
+	v.errs = append(v.errs, FieldError{Path: path, Env: fieldByPath[path].env, Msg: fmt.Sprintf(format, args...)})
+}
+
+func oneOf[T ~string](v *validator, path string, value T, allowed ...T) {
+	names := make([]string, 0, len(allowed))
+	for _, a := range allowed {
+		names = append(names, string(a))
+	}
+	v.check(slices.Contains(allowed, value), path, "unknown value %q, want one of: %s", value, strings.Join(names, ", "))
+}
+
+func (v *validator) positive(path string, d time.Duration) {
+	v.check(d > 0, path, "must be positive, got %s", d)
+}
+
+func (v *validator) nonNegative(path string, d time.Duration) {
+	v.check(d >= 0, path, "must not be negative (0 — server default), got %s", d)
+}
+
+func (v *validator) port(path, port string) {
+	n, err := strconv.Atoi(port)
+	v.check(err == nil && n > 0 && n <= 65535, path, "must be a port number (1-65535), got %q", port)
+}
+
+// endpoint — адрес коллектора: host:port или http(s)://host[:port][/path].
+func (v *validator) endpoint(path, endpoint string) {
+	if strings.Contains(endpoint, "://") {
+		v.httpURL(path, endpoint)
+		return
+	}
+	host, port, err := net.SplitHostPort(endpoint)
+	n, _ := strconv.Atoi(port)
+	v.check(err == nil && host != "" && n > 0 && n <= 65535, path, "want host:port or http(s)://host[:port], got %q", endpoint)
+}
+
+func (v *validator) httpURL(path, raw string) {
+	u, err := url.Parse(raw)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", path, "want an http(s):// URL, got %q", raw)
+}
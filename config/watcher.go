@@ -0,0 +1,312 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// WatchLogger — то, что Watcher пишет в лог. pkg/logger.Logger ему
+// удовлетворяет; свой интерфейс — потому что pkg/logger импортирует config.
+type WatchLogger interface {
+	Info(ctx context.Context, msg string, args ...any)
+	Warn(ctx context.Context, msg string, args ...any)
+	Error(ctx context.Context, msg string, args ...any)
+}
+
+// Change — поле, отличающееся в перечитанном конфиге.
+type Change struct {
+	// Path — путь поля в Config, например "RateLimit.Default".
+	Path string
+	// Env — переменная окружения поля; пусто, если её нет.
+	Env string
+	// Reloadable — поле с тегом reload:"true": меняется на лету.
+	Reloadable bool
+}
+
+func (c Change) String() string {
+	if c.Env == "" {
+		return c.Path
+	}
+	return c.Path + " (" + c.Env + ")"
+}
+
+// Watcher держит текущий конфиг и перечитывает файл по SIGHUP и при его
+// изменении (см. Run). Новый конфиг проверяется целиком (Validate и
+// валидаторы WithValidator); из него
+// применяются только поля с тегом reload:"true", изменения остальных (DSN,
+// порты, бэкенды) отклоняются с предупреждением в логе — для них нужен
+// перезапуск. Конфиг подменяется атомарно, после чего вызываются подписчики.
+// Значения из env, как и при старте, перекрывают файл.
+type Watcher struct {
+	path       string
+	interval   time.Duration
+	log        WatchLogger
+	validators []func(*Config) error
+
+	current atomic.Pointer[Config]
+
+	// mu сериализует перечитывания и вызовы подписчиков.
+	mu   sync.Mutex
+	subs []func(old, new *Config)
+	// sum — хэш файла при последнем перечитывании: опрос по таймеру
+	// перечитывает файл, только когда он изменился.
+	sum [sha256.Size]byte
+}
+
+// WatcherOption настраивает Watcher.
+type WatcherOption func(*Watcher)
+
+// WithWatchPath — файл конфигурации; по умолчанию тот же, что у LoadConfig.
+func WithWatchPath(path string) WatcherOption {
+	return func(w *Watcher) { w.path = path }
+}
+
+// WithWatchInterval — период проверки файла; 0 — только по SIGHUP. По
+// умолчанию — App.ReloadInterval.
+func WithWatchInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) { w.interval = d }
+}
+
+// WithValidator добавляет проверку нового конфига: ошибка отклоняет
+// перечитывание целиком, текущий конфиг остаётся в силе.
+func WithValidator(validate func(*Config) error) WatcherOption {
+	return func(w *Watcher) { w.validators = append(w.validators, validate) }
+}
+
+// WithWatchLogger — лог перечитываний; по умолчанию не пишется.
+func WithWatchLogger(log WatchLogger) WatcherOption {
+	return func(w *Watcher) { w.log = log }
+}
+
+// NewWatcher — Watcher с начальным конфигом cfg (его не меняет: каждое
+// перечитывание создаёт новый Config).
+func NewWatcher(cfg *Config, opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		path:     configPath(),
+		interval: cfg.ReloadInterval,
+		log:      nopWatchLogger{},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.current.Store(cfg)
+	if data, err := os.ReadFile(w.path); err == nil {
+		w.sum = sha256.Sum256(data)
+	}
+
+	return w
+}
+
+// Current — действующий конфиг. Возвращённое значение не меняется: после
+// перечитывания Current отдаёт новый указатель.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe регистрирует fn, вызываемую после каждого применённого
+// перечитывания со старым и новым конфигом. Вызовы последовательны, из
+// горутины Run (или вызывающего Reload).
+func (w *Watcher) Subscribe(fn func(old, new *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subs = append(w.subs, fn)
+}
+
+// Run перечитывает конфиг по SIGHUP и при изменении файла (проверка раз в
+// interval) до отмены ctx. Ошибки перечитывания пишутся в лог: сервис
+// продолжает работать на текущем конфиге.
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			w.log.Info(ctx, "config: SIGHUP received, reloading", "path", w.path)
+			_, _ = w.Reload(ctx)
+		case <-tick:
+			if w.fileChanged() {
+				w.log.Info(ctx, "config: file changed, reloading", "path", w.path)
+				_, _ = w.Reload(ctx)
+			}
+		}
+	}
+}
+
+// Reload перечитывает файл и env, проверяет результат (Validate и
+// валидаторы WithValidator) и применяет
+// изменения полей reload:"true". Возвращает применённые изменения;
+// отклонённые (поля без тега) только пишутся в лог. Ошибка чтения или
+// проверки оставляет текущий конфиг.
+func (w *Watcher) Reload(ctx context.Context) ([]Change, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if data, err := os.ReadFile(w.path); err == nil {
+		w.sum = sha256.Sum256(data)
+	}
+
+	next, err := load(w.path)
+	if err == nil {
+		err = w.validate(next)
+	}
+	if err != nil {
+		w.log.Error(ctx, "config: reload rejected, keeping current config", "path", w.path, "error", err.Error())
+		return nil, err
+	}
+
+	old := w.current.Load()
+	applied, rejected := partition(diff(old, next))
+	if len(rejected) > 0 {
+		w.log.Warn(ctx, "config: ignoring changes that require a restart", "fields", joinChanges(rejected))
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+
+	merged := *old
+	for _, ch := range applied {
+		f := fieldByPath[ch.Path]
+		reflect.ValueOf(&merged).Elem().FieldByIndex(f.index).
+			Set(reflect.ValueOf(next).Elem().FieldByIndex(f.index))
+	}
+	w.current.Store(&merged)
+	w.log.Info(ctx, "config: reloaded", "fields", joinChanges(applied))
+
+	for _, fn := range w.subs {
+		fn(old, &merged)
+	}
+
+	return applied, nil
+}
+
+func (w *Watcher) validate(cfg *Config) error {
+	var errs []error
+	for _, validate := range w.validators {
+		if err := validate(cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) fileChanged() bool {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		// Файл мог пропасть на время атомарной замены — проверим в следующий раз.
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return sha256.Sum256(data) != w.sum
+}
+
+// configField — лист Config: путь, ключ в файле ("rate_limit.enabled"), env,
+// признаки reload и secret и индекс для reflect.Value.FieldByIndex.
+type configField struct {
+	path       string
+	key        string
+	env        string
+	reloadable bool
+	secret     bool
+	index      []int
+}
+
+var (
+	configFields = collectFields(reflect.TypeFor[Config](), "", "", nil)
+	fieldByPath  = func() map[string]configField {
+		m := make(map[string]configField, len(configFields))
+		for _, f := range configFields {
+			m[f.path] = f
+		}
+		return m
+	}()
+)
+
+// collectFields обходит секции Config; листья — всё, кроме вложенных структур.
+func collectFields(t reflect.Type, prefix, keyPrefix string, index []int) []configField {
+	var fields []configField
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		idx := append(append([]int(nil), index...), i)
+		path := prefix + sf.Name
+		key, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		key = keyPrefix + key
+
+		if sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(sf.Type, path+".", key+".", idx)...)
+			continue
+		}
+		fields = append(fields, configField{
+			path:       path,
+			key:        key,
+			env:        sf.Tag.Get("env"),
+			reloadable: sf.Tag.Get("reload") == "true",
+			secret:     sf.Tag.Get("secret") == "true",
+			index:      idx,
+		})
+	}
+	return fields
+}
+
+// diff — поля, которыми различаются a и b, в порядке объявления.
+func diff(a, b *Config) []Change {
+	av, bv := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+
+	var changes []Change
+	for _, f := range configFields {
+		if reflect.DeepEqual(av.FieldByIndex(f.index).Interface(), bv.FieldByIndex(f.index).Interface()) {
+			continue
+		}
+		changes = append(changes, Change{Path: f.path, Env: f.env, Reloadable: f.reloadable})
+	}
+	return changes
+}
+
+func partition(changes []Change) (reloadable, rejected []Change) {
+	for _, ch := range changes {
+		if ch.Reloadable {
+			reloadable = append(reloadable, ch)
+		} else {
+			rejected = append(rejected, ch)
+		}
+	}
+	return reloadable, rejected
+}
+
+func joinChanges(changes []Change) string {
+	names := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		names = append(names, ch.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+type nopWatchLogger struct{}
+
+func (nopWatchLogger) Info(context.Context, string, ...any)  {}
+func (nopWatchLogger) Warn(context.Context, string, ...any)  {}
+func (nopWatchLogger) Error(context.Context, string, ...any) {}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger — WatchLogger для тестов: loggertest импортирует config.
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *recordingLogger) record(level, msg string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, fmt.Sprint(level, " ", msg, " ", args))
+}
+
+func (l *recordingLogger) Info(_ context.Context, msg string, args ...any) {
+	l.record("INFO", msg, args)
+}
+
+func (l *recordingLogger) Warn(_ context.Context, msg string, args ...any) {
+	l.record("WARN", msg, args)
+}
+
+func (l *recordingLogger) Error(_ context.Context, msg string, args ...any) {
+	l.record("ERROR", msg, args)
+}
+
+func (l *recordingLogger) find(level string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var found []string
+	for _, e := range l.entries {
+		if strings.HasPrefix(e, level+" ") {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// unsetEnv убирает переменные на время теста: иначе env перекрыл бы файл.
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+}
+
+// writeWatchedConfig пишет минимальный config.toml с изменяемыми значениями.
+func writeWatchedConfig(t *testing.T, path, level, dbHost, rateDefault string, maxStreams int, rateEnabled bool) {
+	t.Helper()
+
+	data := fmt.Sprintf(`[db]
+host = %q
+port = 5432
+user = "postgres"
+name = "demo"
+password = "secret"
+pool_max = 2
+
+[tracing]
+exporter = "none"
+
+[sse]
+max_streams = %d
+
+[rate_limit]
+enabled = %t
+default = %q
+
+[logger]
+level = %q
+`, dbHost, maxStreams, rateEnabled, rateDefault, level)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func newTestWatcher(t *testing.T, opts ...WatcherOption) (*Watcher, string, *recordingLogger) {
+	t.Helper()
+	unsetEnv(t, "LOG_LEVEL", "DB_HOST", "DB_PORT", "DB_USER", "DB_NAME", "DB_PASSWORD", "PG_POOL_MAX",
+		"TRACING_EXPORTER", "SSE_MAX_STREAMS", "RATE_LIMIT_ENABLED", "RATE_LIMIT_DEFAULT",
+		"OUTBOX_ENABLED", "WEBHOOKS_ENABLED", "USER_CACHE_ENABLED")
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeWatchedConfig(t, path, "DEBUG", "postgres", "300/1m", 1000, true)
+	cfg, err := load(path)
+	require.NoError(t, err)
+
+	log := &recordingLogger{}
+	opts = append([]WatcherOption{WithWatchPath(path), WithWatchInterval(0), WithWatchLogger(log)}, opts...)
+
+	return NewWatcher(cfg, opts...), path, log
+}
+
+func TestWatcherReloadAppliesOnlyReloadableFields(t *testing.T) {
+	w, path, log := newTestWatcher(t)
+	initial := w.Current()
+
+	var calls [][2]*Config
+	w.Subscribe(func(old, new *Config) { calls = append(calls, [2]*Config{old, new}) })
+
+	writeWatchedConfig(t, path, "WARN", "db.internal", "10/1s", 5, true)
+	applied, err := w.Reload(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Path: "Log.Level", Env: "LOG_LEVEL", Reloadable: true},
+		{Path: "SSE.MaxStreams", Env: "SSE_MAX_STREAMS", Reloadable: true},
+		{Path: "RateLimit.Default", Env: "RATE_LIMIT_DEFAULT", Reloadable: true},
+	}, applied)
+
+	cur := w.Current()
+	assert.Equal(t, slog.LevelWarn, cur.Level)
+	assert.Equal(t, 5, cur.MaxStreams)
+	assert.Equal(t, "10/1s", cur.RateLimit.Default)
+	assert.Equal(t, "postgres", cur.DBHost, "DSN меняется только перезапуском")
+
+	assert.Equal(t, slog.LevelDebug, initial.Level, "исходный конфиг не меняется")
+	require.Len(t, calls, 1)
+	assert.Same(t, initial, calls[0][0])
+	assert.Same(t, cur, calls[0][1])
+
+	warns := log.find("WARN")
+	require.Len(t, warns, 1)
+	assert.Contains(t, warns[0], "DB.DBHost (DB_HOST)")
+
+	// Повторное чтение того же файла — без изменений и без подписчиков.
+	applied, err = w.Reload(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Len(t, calls, 1)
+}
+
+// TestWatcherReloadDisablesDefaultTrueFlag — false из файла выключает флаг
+// с env-default:"true" в обе стороны, без перезапуска.
+func TestWatcherReloadDisablesDefaultTrueFlag(t *testing.T) {
+	w, path, _ := newTestWatcher(t)
+	require.True(t, w.Current().RateLimit.Enabled)
+
+	writeWatchedConfig(t, path, "DEBUG", "postgres", "300/1m", 1000, false)
+	applied, err := w.Reload(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: "RateLimit.Enabled", Env: "RATE_LIMIT_ENABLED", Reloadable: true}}, applied)
+	assert.False(t, w.Current().RateLimit.Enabled)
+
+	writeWatchedConfig(t, path, "DEBUG", "postgres", "300/1m", 1000, true)
+	_, err = w.Reload(context.Background())
+	require.NoError(t, err)
+	assert.True(t, w.Current().RateLimit.Enabled)
+}
+
+// TestWatcherReloadKeepsSubsystemSwitches — выключатели воркеров и кэша
+// меняются только перезапуском; смена в файле попадает в предупреждение.
+func TestWatcherReloadKeepsSubsystemSwitches(t *testing.T) {
+	w, path, log := newTestWatcher(t)
+
+	writeWatchedConfig(t, path, "DEBUG", "postgres", "300/1m", 1000, true)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString("\n[outbox]\nenabled = false\n\n[webhooks]\nenabled = false\n\n[user_cache]\nenabled = false\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	applied, err := w.Reload(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	cur := w.Current()
+	assert.True(t, cur.Outbox.Enabled)
+	assert.True(t, cur.Webhooks.Enabled)
+	assert.True(t, cur.UserCache.Enabled)
+
+	warns := log.find("WARN")
+	require.Len(t, warns, 1)
+	for _, field := range []string{"OUTBOX_ENABLED", "WEBHOOKS_ENABLED", "USER_CACHE_ENABLED"} {
+		assert.Contains(t, warns[0], field)
+	}
+}
+
+func TestWatcherReloadRejectsInvalidConfig(t *testing.T) {
+	w, path, log := newTestWatcher(t, WithValidator(func(cfg *Config) error {
+		if cfg.RateLimit.Default == "bad" {
+			return errors.New("rate limit default: bad")
+		}
+		return nil
+	}))
+	initial := w.Current()
+	w.Subscribe(func(_, _ *Config) { t.Error("subscriber called for rejected reload") })
+
+	writeWatchedConfig(t, path, "WARN", "postgres", "bad", 5, true)
+	_, err := w.Reload(context.Background())
+	require.ErrorContains(t, err, "rate limit default")
+
+	writeWatchedConfig(t, path, "WARN", "postgres", "300/1m", -1, true)
+	_, err = w.Reload(context.Background())
+	require.ErrorContains(t, err, "SSE_MAX_STREAMS")
+
+	require.NoError(t, os.WriteFile(path, []byte("[logger\nlevel = "), 0o600))
+	_, err = w.Reload(context.Background())
+	require.Error(t, err)
+
+	assert.Same(t, initial, w.Current(), "отклонённое перечитывание оставляет текущий конфиг")
+	assert.Len(t, log.find("ERROR"), 3)
+}
+
+func TestWatcherRunReloadsOnFileChange(t *testing.T) {
+	w, path, _ := newTestWatcher(t, WithWatchInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Go(func() { w.Run(ctx) })
+	t.Cleanup(func() { cancel(); wg.Wait() })
+
+	writeWatchedConfig(t, path, "DEBUG", "postgres", "300/1m", 5, true)
+
+	assert.Eventually(t, func() bool { return w.Current().MaxStreams == 5 }, 5*time.Second, 10*time.Millisecond)
+}
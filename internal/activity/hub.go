@@ -0,0 +1,237 @@
+// Package activity — лента событий счетов для SSE: один выделенный
+// соединением LISTEN на инстанс и раздача уведомлений подписчикам по счетам.
+package activity
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/backoff"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_defaultMaxStreams = 1000
+	// subscriberBuffer — сколько событий ждёт медленного клиента; при
+	// переполнении новые отбрасываются, а поток дочитывает их из БД.
+	subscriberBuffer = 64
+
+	baseReconnectDelay = time.Second
+	maxReconnectDelay  = 30 * time.Second
+)
+
+// ConnectFunc открывает выделенное соединение под LISTEN: в пуле его держать
+// нельзя — оно занято всё время жизни Hub.
+type ConnectFunc func(ctx context.Context) (*pgx.Conn, error)
+
+// Hub слушает канал NOTIFY и раздаёт переводы подписчикам обоих счетов.
+// Реализует usecase.ActivityFeed.
+type Hub struct {
+	connect ConnectFunc
+	channel string
+
+	maxStreams int
+	log        logger.Logger
+
+	mu     sync.Mutex
+	subs   map[int64]map[*subscription]struct{}
+	count  int
+	closed bool
+	done   chan struct{}
+}
+
+var _ usecase.ActivityFeed = (*Hub)(nil)
+
+func NewHub(connect ConnectFunc, channel string, opts ...Option) *Hub {
+	h := &Hub{
+		connect:    connect,
+		channel:    channel,
+		maxStreams: _defaultMaxStreams,
+		log:        logger.Nop(),
+		subs:       make(map[int64]map[*subscription]struct{}),
+		done:       make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *Hub) Subscribe(accountID int64) (usecase.Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, entity.ErrStreamClosed
+	}
+	if h.count >= h.maxStreams {
+		return nil, entity.ErrTooManyStreams
+	}
+
+	sub := &subscription{
+		hub:       h,
+		accountID: accountID,
+		events:    make(chan entity.AccountEvent, subscriberBuffer),
+		resync:    make(chan struct{}, 1),
+	}
+	if h.subs[accountID] == nil {
+		h.subs[accountID] = make(map[*subscription]struct{})
+	}
+	h.subs[accountID][sub] = struct{}{}
+	h.count++
+
+	return sub, nil
+}
+
+// SetMaxStreams меняет предел одновременных потоков на лету (горячая
+// перезагрузка конфига). Уже открытые потоки сверх нового предела не
+// закрываются: новые подписки получат отказ, пока их не станет меньше.
+func (h *Hub) SetMaxStreams(limit int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.maxStreams = limit
+}
+
+// Close завершает все потоки и запрещает новые подписки. Вызывается в начале
+// graceful shutdown: открытые SSE-соединения иначе не дали бы HTTP-серверу
+// дождаться простоя.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.closed {
+		h.closed = true
+		close(h.done)
+	}
+}
+
+// Run держит LISTEN до отмены ctx, переподключаясь с backoff. После каждого
+// переподключения подписчики получают resync: уведомления, пришедшие без
+// слушателя, потеряны, и потоки дочитывают их из БД.
+func (h *Hub) Run(ctx context.Context) {
+	var failures int
+	for reconnect := false; ; reconnect = true {
+		listened, err := h.listen(ctx, reconnect)
+		if ctx.Err() != nil {
+			return
+		}
+		if listened {
+			failures = 0
+		}
+		failures++
+		h.log.Error(ctx, "activity: listen failed, reconnecting", "error", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Exponential(failures, baseReconnectDelay, maxReconnectDelay)):
+		}
+	}
+}
+
+// listen возвращает listened=true, если LISTEN успел установиться.
+func (h *Hub) listen(ctx context.Context, reconnect bool) (bool, error) {
+	conn, err := h.connect(ctx)
+	if err != nil {
+		return false, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = conn.Close(context.WithoutCancel(ctx)) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{h.channel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("listen %s: %w", h.channel, err)
+	}
+
+	// Resync — только когда LISTEN снова активен: всё, что закоммитится после,
+	// придёт живым, а раньше — найдётся догрузкой из БД.
+	if reconnect {
+		h.broadcastResync()
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("wait for notification: %w", err)
+		}
+		h.dispatch(ctx, notification.Payload)
+	}
+}
+
+// dispatch раздаёт перевод подписчикам отправителя (debited) и получателя (credited).
+func (h *Hub) dispatch(ctx context.Context, payload string) {
+	var transaction entity.Transaction
+	if err := json.Unmarshal([]byte(payload), &transaction); err != nil {
+		h.log.Error(ctx, "activity: malformed notification", "error", err.Error())
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, accountID := range []int64{transaction.FromUserID, transaction.ToUserID} {
+		event := transaction.EventFor(accountID)
+		for sub := range h.subs[accountID] {
+			select {
+			case sub.events <- event:
+			default:
+				// Клиент не успевает читать: не блокируем остальных, поток
+				// дочитает пропущенное из БД.
+				sub.signalResync()
+			}
+		}
+	}
+}
+
+func (h *Hub) broadcastResync() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.subs {
+		for sub := range subs {
+			sub.signalResync()
+		}
+	}
+}
+
+func (h *Hub) unsubscribe(sub *subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[sub.accountID][sub]; !ok {
+		return
+	}
+
+	delete(h.subs[sub.accountID], sub)
+	if len(h.subs[sub.accountID]) == 0 {
+		delete(h.subs, sub.accountID)
+	}
+	h.count--
+}
+
+type subscription struct {
+	hub       *Hub
+	accountID int64
+	events    chan entity.AccountEvent
+	resync    chan struct{}
+}
+
+func (s *subscription) Events() <-chan entity.AccountEvent { return s.events }
+func (s *subscription) Resync() <-chan struct{}            { return s.resync }
+func (s *subscription) Done() <-chan struct{}              { return s.hub.done }
+func (s *subscription) Close()                             { s.hub.unsubscribe(s) }
+
+// signalResync не блокируется: один невычитанный сигнал покрывает все потери.
+func (s *subscription) signalResync() {
+	select {
+	case s.resync <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,112 @@
+package activity
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const transferPayload = `{"id":42,"from_user_id":1,"to_user_id":2,"amount":300,"created_at":"2026-10-18T12:00:00.123456+00:00"}`
+
+func TestHubDispatchesBothSidesOfTransfer(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil, "account_activity")
+	from, err := hub.Subscribe(1)
+	require.NoError(t, err)
+	to, err := hub.Subscribe(2)
+	require.NoError(t, err)
+	other, err := hub.Subscribe(3)
+	require.NoError(t, err)
+
+	hub.dispatch(context.Background(), transferPayload)
+
+	debited := <-from.Events()
+	assert.Equal(t, entity.EventTransferDebited, debited.Type)
+	assert.Equal(t, int64(42), debited.ID)
+	assert.Equal(t, int64(2), debited.CounterpartyID)
+
+	credited := <-to.Events()
+	assert.Equal(t, entity.EventTransferCredited, credited.Type)
+	assert.Equal(t, int64(1), credited.CounterpartyID)
+	assert.Equal(t, int64(300), credited.Amount)
+
+	assert.Empty(t, other.Events())
+}
+
+func TestHubSlowSubscriberGetsResyncInsteadOfBlocking(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil, "account_activity")
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	for range subscriberBuffer + 1 {
+		hub.dispatch(context.Background(), transferPayload)
+	}
+
+	assert.Len(t, sub.Events(), subscriberBuffer)
+	select {
+	case <-sub.Resync():
+	default:
+		t.Fatal("переполненный подписчик должен получить resync")
+	}
+}
+
+func TestHubLimitsStreamsAndFreesSlotOnClose(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil, "account_activity", MaxStreams(1))
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	_, err = hub.Subscribe(2)
+	require.ErrorIs(t, err, entity.ErrTooManyStreams)
+
+	sub.Close()
+	sub.Close() // повторный Close не должен уводить счётчик в минус
+
+	_, err = hub.Subscribe(2)
+	require.NoError(t, err)
+}
+
+func TestHubSetMaxStreams(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil, "account_activity", MaxStreams(2))
+	for id := range int64(2) {
+		_, err := hub.Subscribe(id)
+		require.NoError(t, err)
+	}
+
+	hub.SetMaxStreams(1)
+	_, err := hub.Subscribe(3)
+	require.ErrorIs(t, err, entity.ErrTooManyStreams, "открытые потоки не закрываются, но новые не принимаются")
+
+	hub.SetMaxStreams(3)
+	_, err = hub.Subscribe(3)
+	require.NoError(t, err)
+}
+
+func TestHubCloseEndsStreams(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil, "account_activity")
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	hub.Close()
+	hub.Close()
+
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("Done должен закрываться при остановке ленты")
+	}
+
+	_, err = hub.Subscribe(1)
+	require.ErrorIs(t, err, entity.ErrStreamClosed)
+}
@@ -0,0 +1,20 @@
+package activity
+
+import "clean-arch-template/pkg/logger"
+
+// Option -.
+type Option func(*Hub)
+
+// MaxStreams — предел одновременных подписок на инстанс.
+func MaxStreams(limit int) Option {
+	return func(h *Hub) {
+		h.maxStreams = limit
+	}
+}
+
+// WithLogger -.
+func WithLogger(l logger.Logger) Option {
+	return func(h *Hub) {
+		h.log = l
+	}
+}
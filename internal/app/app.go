@@ -2,20 +2,34 @@ package app
 
 import (
 	"clean-arch-template/config"
+	"clean-arch-template/internal/activity"
+	"clean-arch-template/internal/outbox"
 	"clean-arch-template/internal/usecase"
 	"clean-arch-template/internal/usecase/repository"
+	"clean-arch-template/internal/webhook"
 	"clean-arch-template/pkg/database"
 	"clean-arch-template/pkg/logger"
+	"clean-arch-template/pkg/metrics"
+	"clean-arch-template/pkg/netguard"
+	"clean-arch-template/pkg/ratelimit"
+	"clean-arch-template/pkg/requestid"
 	"clean-arch-template/version"
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"sync"
+	"time"
 
+	grpcv1 "clean-arch-template/internal/handler/grpc/v1"
+	"clean-arch-template/internal/handler/rest/middleware"
 	v1 "clean-arch-template/internal/handler/rest/v1"
 
 	"github.com/ansrivas/fiberprometheus/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humafiber"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/gofiber/contrib/otelfiber/v2"
@@ -25,77 +39,240 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/monitor"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 type App struct {
-	server *fiber.App
-	pg     *database.Postgres
-	cfg    *config.Config
-	log    logger.Logger
+	server     *fiber.App
+	grpcServer *grpc.Server
+	grpcHealth *health.Server
+	activity   *activity.Hub
+	pg         *database.Postgres
+	cfg        *config.Config
+	log        logger.Logger
+
+	// workers — фоновые циклы (outbox relay и т.п.): живут, пока работают
+	// серверы, и останавливаются до закрытия пула БД.
+	workers     []func(ctx context.Context)
+	workersWG   sync.WaitGroup
+	stopWorkers context.CancelFunc
+}
+
+// Option настраивает старт приложения (New).
+type Option func(*options)
+
+type options struct {
+	skipMigrations bool
+}
+
+// SkipMigrations — не применять миграции при старте: схему накатывает
+// отдельный Job/initContainer (`migrate up`).
+func SkipMigrations() Option {
+	return func(o *options) { o.skipMigrations = true }
 }
 
 // New подключает БД, применяет миграции, собирает middleware и DI.
 // Любая ошибка старта возвращается наверх — приложение не должно жить
 // с недоступной БД или битой схемой.
-func New(ctx context.Context, cfg *config.Config, log logger.Logger) (*App, error) {
+func New(ctx context.Context, cfg *config.Config, log logger.Logger, opts ...Option) (*App, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	//nolint:contextcheck // стартовый лог до появления запроса: сигнатура фиксирована без ctx
 	version.PrintVersion(cfg, log)
 
-	//nolint:contextcheck // database.New не принимает ctx: пул создаётся один раз при старте
-	pg, err := database.New(cfg,
-		database.MaxPoolSize(cfg.PoolMax),
-		database.MinPoolSize(cfg.PoolMin),
-		database.ConnTimeout(cfg.ConnectTimeout),
-		database.HealthCheckPeriod(cfg.HealthCheckPeriod),
-		database.WithLogger(log),
-	)
+	// Метрики — в глобальный MeterProvider (tracing.InitMeterProvider): он
+	// решает, отдавать их через /metrics или отправлять по OTLP.
+	meterProvider := otel.GetMeterProvider()
+	appMetrics, err := metrics.New(meterProvider)
 	if err != nil {
-		return nil, fmt.Errorf("postgres connection failed: %w", err)
+		return nil, fmt.Errorf("metrics: %w", err)
 	}
-
-	if err := applyMigrations(ctx, cfg.DB, log); err != nil {
-		pg.Close()
-		return nil, fmt.Errorf("apply migrations failed: %w", err)
+	// /metrics и пробы не считаем: это фон скрейпера и kubelet.
+	httpMetrics, err := middleware.HTTPMetrics(meterProvider, "/metrics", "/ping", "/livez", "/readyz")
+	if err != nil {
+		return nil, fmt.Errorf("http metrics: %w", err)
 	}
 
+	// pg == nil — демо-режим (DB_BACKEND=memory): всё, что требует Postgres,
+	// ниже не собирается.
+	var (
+		pg         *database.Postgres
+		userRepo   usecase.UserRepository
+		cachedRepo *repository.CachedUserRepository
+	)
+	switch cfg.DB.Backend {
+	case backendPostgres:
+		if pg, err = openPostgres(ctx, cfg, meterProvider, appMetrics, log, o); err != nil {
+			return nil, err
+		}
+		// Чтения пользователей идут на реплики (если заданы DB_REPLICA_HOSTS),
+		// записи и транзакции — в primary.
+		userRepo = repository.NewUserRepository(pg.DBGetter, pg.ReadDBGetter, pg.Transactor)
+		if cfg.UserCache.Enabled {
+			if cachedRepo, err = newCachedUserRepository(cfg, userRepo, pg, log); err != nil {
+				pg.Close()
+				return nil, err
+			}
+			userRepo = cachedRepo
+		}
+	case backendMemory:
+		if userRepo, err = newDemoUserRepository(ctx); err != nil {
+			return nil, err
+		}
+		log.Warn(ctx, "DB_BACKEND=memory: demo mode, data is lost on restart; outbox, webhooks and SSE are disabled")
+	default:
+		return nil, fmt.Errorf("unknown db backend %q (want postgres | memory)", cfg.DB.Backend)
+	}
 	// ReadTimeout обязателен: без него fiber не закрывает keepalive-соединения
 	// при Shutdown и drain никогда не завершается.
 	server := fiber.New(fiber.Config{
 		ReadTimeout:  cfg.ReadTimeout,
 		WriteTimeout: cfg.WriteTimeout,
 		IdleTimeout:  cfg.IdleTimeout,
+		// c.IP() берёт ProxyHeader только от доверенных прокси; от прочих —
+		// адрес соединения, иначе клиент подменял бы свой IP заголовком.
+		ProxyHeader:             cfg.HTTP.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.HTTP.TrustedProxies,
+		EnableIPValidation:      true,
+	})
+
+	// Use case один на оба транспорта: REST и gRPC — лишь адаптеры над ним.
+	uc := routeUseCases{user: usecase.NewUserUseCase(userRepo, appMetrics)}
+
+	var activityHub *activity.Hub
+	if pg != nil {
+		uc.webhook = usecase.NewWebhookUseCase(repository.NewWebhookRepository(pg.DBGetter))
+
+		// Лента SSE слушает NOTIFY на выделенном соединении с настройками пула.
+		activityHub = activity.NewHub(
+			pgConnect(pg),
+			repository.ActivityChannel,
+			activity.MaxStreams(cfg.SSE.MaxStreams),
+			activity.WithLogger(log),
+		)
+		uc.activity = usecase.NewActivityUseCase(
+			userRepo,
+			repository.NewActivityRepository(pg.DBGetter),
+			activityHub,
+		)
+	}
+
+	rateLimiter, err := setupRateLimit(cfg, pg, log)
+	if err != nil {
+		if pg != nil {
+			pg.Close()
+		}
+		return nil, err
+	}
+
+	// Перезагрузка конфига (SIGHUP, изменение файла) меняет на лету уровень
+	// лога, таймаут запроса, квоты и предел SSE-потоков; остальное — только
+	// перезапуском.
+	watcher := config.NewWatcher(cfg,
+		config.WithWatchLogger(log),
+		config.WithValidator(rateLimiter.validate),
+	)
+	watcher.Subscribe(func(_, cur *config.Config) {
+		logger.SetLevel(log, cur)
+		rateLimiter.apply(cur)
+		if activityHub != nil {
+			activityHub.SetMaxStreams(cur.SSE.MaxStreams)
+		}
 	})
 
 	//nolint:contextcheck // per-request ctx внутри middleware намеренно независим от ctx старта приложения
-	setupMiddlewares(server, cfg, pg)
-	setupRoutes(server, pg, log)
+	setupMiddlewares(server, watcher, pg, httpMetrics, rateLimiter.limiter)
+	setupRoutes(server, cfg, uc, log)
+
+	grpcServer, grpcHealth := setupGRPC(cfg, uc.user, log)
+
+	var workers []func(ctx context.Context)
+	if pg != nil {
+		if workers, err = setupWorkers(cfg, pg, log); err != nil {
+			pg.Close()
+			return nil, err
+		}
+		workers = append(workers, activityHub.Run)
+	}
+	if cachedRepo != nil {
+		workers = append(workers, func(ctx context.Context) { cachedRepo.Listen(ctx, pgConnect(pg)) })
+	}
+	if rateLimiter.worker != nil {
+		workers = append(workers, rateLimiter.worker)
+	}
+	workers = append(workers, watcher.Run)
 
 	//nolint:contextcheck // стартовые диагностические логи: сигнатура фиксирована без ctx
 	PrintSystemData(log)
 	//nolint:contextcheck // стартовые диагностические логи: сигнатура фиксирована без ctx
 	PrintMemoryInfo(log)
 
-	return &App{server: server, pg: pg, cfg: cfg, log: log}, nil
+	return &App{
+		server:     server,
+		grpcServer: grpcServer,
+		grpcHealth: grpcHealth,
+		activity:   activityHub,
+		pg:         pg,
+		cfg:        cfg,
+		log:        log,
+		workers:    workers,
+	}, nil
 }
 
-// Run блокируется до отмены контекста (сигнал) или ошибки сервера.
-// При отмене выполняет graceful shutdown с таймаутом и закрывает пул БД.
+// Run блокируется до отмены контекста (сигнал) или ошибки одного из серверов.
+// И при отмене, и при падении одного сервера выполняет graceful shutdown
+// обоих с общим таймаутом и закрывает пул БД.
 func (a *App) Run(ctx context.Context) error {
-	errCh := make(chan error, 1)
+	// Порт gRPC занимаем синхронно: занятый порт — ошибка старта, а не
+	// тихо умершая горутина.
+	grpcListener, err := net.Listen("tcp", ":"+a.cfg.GRPC.Port)
+	if err != nil {
+		a.closeDB()
+		return fmt.Errorf("grpc server: listen: %w", err)
+	}
+
+	errCh := make(chan error, 2)
+
+	go func() {
+		if err := a.server.Listen(":" + a.cfg.HTTP.Port); err != nil {
+			errCh <- fmt.Errorf("http server: %w", err)
+			return
+		}
+		errCh <- nil
+	}()
 
 	go func() {
-		errCh <- a.server.Listen(":" + a.cfg.Port)
+		if err := a.grpcServer.Serve(grpcListener); err != nil {
+			errCh <- fmt.Errorf("grpc server: %w", err)
+			return
+		}
+		errCh <- nil
 	}()
 
-	a.log.Info(ctx, "Starting server on port: "+a.cfg.Port)
+	a.grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	// Воркеры наследуют ctx сигнала: при остановке они прекращают брать новую
+	// работу сразу, не дожидаясь drain серверов.
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	a.stopWorkers = stopWorkers
+	for _, worker := range a.workers {
+		a.workersWG.Go(func() { worker(workersCtx) })
+	}
+
+	a.log.Info(ctx, "Starting server on port: "+a.cfg.HTTP.Port)
+	a.log.Info(ctx, "Starting gRPC server on port: "+a.cfg.GRPC.Port)
 
+	var runErr error
 	select {
-	case err := <-errCh:
-		a.pg.Close()
-		if err != nil {
-			return fmt.Errorf("http server: %w", err)
-		}
-		return nil
+	case runErr = <-errCh:
 	case <-ctx.Done():
 	}
 
@@ -105,10 +282,9 @@ func (a *App) Run(ctx context.Context) error {
 	defer cancel()
 
 	//nolint:contextcheck // shutdown-контекст сознательно не наследует отменённый родительский
-	err := a.server.ShutdownWithContext(shutdownCtx)
-	a.pg.Close()
-	if err != nil {
-		return fmt.Errorf("http server shutdown: %w", err)
+	shutdownErr := a.shutdown(shutdownCtx)
+	if runErr != nil || shutdownErr != nil {
+		return errors.Join(runErr, shutdownErr)
 	}
 
 	a.log.Info(ctx, "Server stopped")
@@ -116,23 +292,75 @@ func (a *App) Run(ctx context.Context) error {
 	return nil
 }
 
-func setupMiddlewares(server *fiber.App, cfg *config.Config, pg *database.Postgres) {
-	if cfg.Environment == "prod" {
+// shutdown останавливает оба сервера параллельно в пределах ctx, затем
+// фоновые воркеры, и только после этого закрывает пул: дренируемые запросы
+// и воркеры ещё ходят в БД.
+func (a *App) shutdown(ctx context.Context) error {
+	// NOT_SERVING до остановки: балансировщики перестают слать новые вызовы,
+	// пока текущие дренируются.
+	a.grpcHealth.Shutdown()
+
+	// SSE-потоки завершаются первыми: пока они открыты, fasthttp не считает
+	// соединения простаивающими и drain дождался бы только таймаута.
+	if a.activity != nil {
+		a.activity.Close()
+	}
+
+	grpcDone := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(grpcDone)
+	}()
+
+	httpErr := a.server.ShutdownWithContext(ctx)
+	if httpErr != nil {
+		httpErr = fmt.Errorf("http server shutdown: %w", httpErr)
+	}
+
+	var grpcErr error
+	select {
+	case <-grpcDone:
+	case <-ctx.Done():
+		// Долгие вызовы не уложились в таймаут — рвём их принудительно.
+		a.grpcServer.Stop()
+		<-grpcDone
+		grpcErr = fmt.Errorf("grpc server shutdown: %w", ctx.Err())
+	}
+
+	a.stopWorkers()
+	a.workersWG.Wait()
+
+	a.closeDB()
+
+	return errors.Join(httpErr, grpcErr)
+}
+
+func setupMiddlewares(server *fiber.App, watcher *config.Watcher, pg *database.Postgres, httpMetrics fiber.Handler, rateLimiter *middleware.RateLimiter) {
+	cfg := watcher.Current()
+
+	// Первым: ID нужен access-логу и всем, кто логирует дальше по цепочке.
+	server.Use(middleware.RequestID())
+
+	if cfg.Environment == config.EnvProd {
 		// Структурированный access-лог, чтобы не ломать JSON-пайплайн логов.
+		// request_id безопасно подставлять в JSON: middleware.RequestID
+		// пропускает только ID без кавычек и управляющих символов.
 		server.Use(fiberlogger.New(fiberlogger.Config{
-			Format: `{"time":"${time}","message":"access","method":"${method}","path":"${path}","status":${status},"latency":"${latency}","ip":"${ip}"}` + "\n",
+			Format: `{"time":"${time}","message":"access","method":"${method}","path":"${path}","status":${status},"latency":"${latency}","ip":"${ip}","request_id":"${respHeader:` + requestid.Header + `}"}` + "\n",
 		}))
 	} else {
 		server.Use(fiberlogger.New())
 	}
 
-	// open telemetry
-	server.Use(otelfiber.Middleware())
+	// open telemetry; HTTP-метрики — свои (middleware.HTTPMetrics), с exemplars.
+	server.Use(otelfiber.Middleware(otelfiber.WithoutMetrics(true)))
+	server.Use(httpMetrics)
 
 	// readiness отражает реальную готовность: умерла БД — /readyz отдаёт 503.
+	// В демо-режиме БД нет — готовность определяет сам процесс.
 	server.Use(healthcheck.New(healthcheck.Config{
 		ReadinessProbe: func(c *fiber.Ctx) bool {
-			return pg.Pool.Ping(c.UserContext()) == nil
+			return pg == nil || pg.Pool.Ping(c.UserContext()) == nil
 		},
 	}))
 
@@ -141,22 +369,33 @@ func setupMiddlewares(server *fiber.App, cfg *config.Config, pg *database.Postgr
 	}))
 
 	// Таймаут на запрос: зависший запрос к БД не держит соединение пула вечно.
+	// Берётся из текущего конфига: HTTP_REQUEST_TIMEOUT перезагружается на лету.
 	server.Use(func(c *fiber.Ctx) error {
-		reqCtx, cancel := context.WithTimeout(c.UserContext(), cfg.RequestTimeout)
+		reqCtx, cancel := context.WithTimeout(c.UserContext(), watcher.Current().RequestTimeout)
 		defer cancel()
 		c.SetUserContext(reqCtx)
 		return c.Next()
 	})
 
-	// go metrics
-	server.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
-	// fiber metrics
-	prometheus := fiberprometheus.New("clean-arch-template")
-	prometheus.RegisterAt(server, "/fiber")
-	prometheus.SetSkipPaths([]string{"/ping"}) // Optional: Remove some paths from metrics
-	server.Use(prometheus.Middleware)
+	// go runtime и метрики приложения (мост OTel → Prometheus). OpenMetrics
+	// нужен для exemplars: в текстовом формате 0.0.4 их нет.
+	server.Get("/metrics", adaptor.HTTPHandler(promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})))
+	// fiber metrics — прежний формат (http_requests_total и др.) в своём
+	// реестре: на него завязаны существующие дашборды и скрейп-джоб /fiber.
+	fiberMetrics := fiberprometheus.New("clean-arch-template")
+	fiberMetrics.RegisterAt(server, "/fiber")
+	fiberMetrics.SetSkipPaths([]string{"/ping"}) // Optional: Remove some paths from metrics
+	server.Use(fiberMetrics.Middleware)
+
+	// Лимитер — после метрик (429 видны в Prometheus) и после /metrics и
+	// health-проб: их скрейпер и kubelet не должны упираться в квоты.
+	if rateLimiter != nil {
+		server.Use(rateLimiter.Handler())
+	}
 
-	if cfg.Environment == "dev" {
+	if cfg.Environment == config.EnvDev {
 		server.Use(pprof.New())
 		server.Get("/monitor", monitor.New())
 	}
@@ -166,14 +405,202 @@ func setupMiddlewares(server *fiber.App, cfg *config.Config, pg *database.Postgr
 	})
 }
 
-func setupRoutes(server *fiber.App, pg *database.Postgres, log logger.Logger) {
+// routeUseCases — use case-ы, которые REST-слой получает из New.
+type routeUseCases struct {
+	user     *usecase.UserUseCase
+	webhook  *usecase.WebhookUseCase
+	activity *usecase.ActivityUseCase
+}
+
+func setupRoutes(server *fiber.App, cfg *config.Config, uc routeUseCases, log logger.Logger) {
 	humaConfig := v1.SetupHumaConfig()
+	humaConfig.Transformers = append(humaConfig.Transformers, middleware.ProblemTransformer)
 	api := humafiber.New(server, humaConfig)
 
-	// Initialize use cases
-	userUseCase := usecase.NewUserUseCase(repository.NewUserRepository(pg.DBGetter, pg.Transactor))
-
 	// Initialize handlers
-	userHandler := v1.NewUserHandler(userUseCase, log)
+	userHandler := v1.NewUserHandler(uc.user, log)
 	v1.SetupRoutes(api, userHandler)
+	// Без Postgres (демо-режим) webhooks и SSE-лента не регистрируются.
+	if uc.webhook != nil {
+		v1.SetupWebhookRoutes(api, v1.NewWebhookHandler(uc.webhook, log))
+	}
+	if uc.activity != nil {
+		v1.SetupActivityRoutes(api, v1.NewActivityHandler(uc.activity, cfg.SSE.Heartbeat, cfg.WriteTimeout, log))
+	}
+}
+
+func setupGRPC(cfg *config.Config, userUseCase *usecase.UserUseCase, log logger.Logger) (*grpc.Server, *health.Server) {
+	server, healthServer := grpcv1.NewServer(grpcv1.NewUserHandler(userUseCase, log))
+
+	// До Serve сервис считается неготовым; SERVING выставляет Run.
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	// Reflection для grpcurl/grpcui — только в dev, как pprof и monitor.
+	if cfg.Environment == config.EnvDev {
+		reflection.Register(server)
+	}
+
+	return server, healthServer
+}
+
+// rateLimit — лимитер и то, что нужно для его перенастройки при
+// перезагрузке конфига: квоты и RATE_LIMIT_ENABLED меняются на лету,
+// хранилище — только перезапуском.
+type rateLimit struct {
+	// limiter — nil, если хранилище не собрано (postgres без Postgres при
+	// выключенном лимитере).
+	limiter *middleware.RateLimiter
+	// worker — очистка устаревших вёдер postgres-хранилища.
+	worker func(ctx context.Context)
+	// retention — срок хранения вёдер postgres-хранилища, выбранный при
+	// старте; 0 — хранилище в памяти, без ограничения.
+	retention time.Duration
+}
+
+// setupRateLimit собирает лимитер по конфигу. Выключенный лимитер тоже
+// собирается (пропускает всё): его включает перезагрузка конфига.
+func setupRateLimit(cfg *config.Config, pg *database.Postgres, log logger.Logger) (*rateLimit, error) {
+	defaultLimit, quotas, err := parseRateLimit(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rl := &rateLimit{}
+	var store ratelimit.Store
+
+	switch cfg.RateLimit.Backend {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "postgres":
+		if pg == nil {
+			if cfg.RateLimit.Enabled {
+				return nil, errors.New("rate limit backend postgres requires DB_BACKEND=postgres")
+			}
+			return rl, nil
+		}
+		// Ведро, простоявшее дольше самого длинного периода, уже полное — его
+		// можно удалять без изменения лимитов.
+		rl.retention = longestPeriod(defaultLimit, quotas)
+
+		pgStore := ratelimit.NewPostgresStore(pg.Pool, rl.retention, log)
+		store, rl.worker = pgStore, pgStore.Run
+
+		log.Warn(context.Background(), "rate limit: postgres backend runs an upsert per request on the main pool, size PG_POOL_MAX for it",
+			"pool_max", cfg.DB.PoolMax)
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q (want memory | postgres)", cfg.RateLimit.Backend)
+	}
+
+	rl.limiter = middleware.NewRateLimiter(middleware.RateLimitConfig{
+		Store:   store,
+		Default: defaultLimit,
+		Quotas:  quotas,
+		Log:     log,
+	})
+	rl.limiter.Update(cfg.RateLimit.Enabled, defaultLimit, quotas)
+
+	return rl, nil
+}
+
+// validate проверяет, что квоты cfg можно применить без перезапуска.
+func (rl *rateLimit) validate(cfg *config.Config) error {
+	defaultLimit, quotas, err := parseRateLimit(cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.RateLimit.Enabled && rl.limiter == nil {
+		return errors.New("RATE_LIMIT_ENABLED: limiter storage was not set up at startup, restart required")
+	}
+	if period := longestPeriod(defaultLimit, quotas); rl.retention > 0 && period > rl.retention {
+		return fmt.Errorf("rate limit period %s exceeds bucket retention %s set at startup, restart required", period, rl.retention)
+	}
+	return nil
+}
+
+// apply применяет квоты cfg, уже прошедшие validate.
+func (rl *rateLimit) apply(cfg *config.Config) {
+	if rl.limiter == nil {
+		return
+	}
+	defaultLimit, quotas, _ := parseRateLimit(cfg)
+	rl.limiter.Update(cfg.RateLimit.Enabled, defaultLimit, quotas)
+}
+
+func parseRateLimit(cfg *config.Config) (ratelimit.Limit, []middleware.Quota, error) {
+	defaultLimit, err := ratelimit.ParseLimit(cfg.RateLimit.Default)
+	if err != nil {
+		return ratelimit.Limit{}, nil, fmt.Errorf("rate limit default: %w", err)
+	}
+
+	quotas, err := middleware.ParseQuotas(cfg.RateLimit.Routes)
+	if err != nil {
+		return ratelimit.Limit{}, nil, err
+	}
+
+	return defaultLimit, quotas, nil
+}
+
+func longestPeriod(defaultLimit ratelimit.Limit, quotas []middleware.Quota) time.Duration {
+	period := defaultLimit.Period()
+	for _, quota := range quotas {
+		period = max(period, quota.Limit.Period())
+	}
+	return period
+}
+
+// setupWorkers собирает фоновые воркеры по конфигу; ошибка конфигурации
+// (например, неизвестный publisher) — ошибка старта.
+func setupWorkers(cfg *config.Config, pg *database.Postgres, log logger.Logger) ([]func(ctx context.Context), error) {
+	var workers []func(ctx context.Context)
+
+	if cfg.Webhooks.Enabled && !cfg.Outbox.Enabled {
+		return nil, errors.New("webhooks require outbox: set OUTBOX_ENABLED=true or WEBHOOKS_ENABLED=false")
+	}
+
+	if cfg.Outbox.Enabled {
+		publisher, err := outbox.NewPublisher(outbox.PublisherConfig{
+			Kind:       cfg.Outbox.Publisher,
+			WebhookURL: cfg.Outbox.WebhookURL,
+			Timeout:    cfg.Outbox.PublishTimeout,
+		}, log)
+		if err != nil {
+			return nil, fmt.Errorf("outbox publisher: %w", err)
+		}
+
+		// Диспетчер webhook-ов — ещё один получатель событий outbox: он лишь
+		// ставит доставки в очередь, отправляет их отдельный воркер.
+		if cfg.Webhooks.Enabled {
+			publisher = outbox.MultiPublisher{publisher, webhook.NewDispatcher(pg.Pool)}
+		}
+
+		relay := outbox.NewRelay(pg.Pool, publisher,
+			outbox.BatchSize(cfg.Outbox.BatchSize),
+			outbox.PollInterval(cfg.Outbox.PollInterval),
+			outbox.MaxAttempts(cfg.Outbox.MaxAttempts),
+			outbox.PublishTimeout(cfg.Outbox.PublishTimeout),
+			outbox.WithLogger(log),
+		)
+		workers = append(workers, relay.Run)
+	}
+
+	if cfg.Webhooks.Enabled {
+		deliveryWorker := webhook.NewWorker(pg.Pool,
+			webhook.BatchSize(cfg.Webhooks.BatchSize),
+			webhook.PollInterval(cfg.Webhooks.PollInterval),
+			webhook.MaxAttempts(cfg.Webhooks.MaxAttempts),
+			webhook.DisableAfter(cfg.Webhooks.DisableAfter),
+			webhook.WithHTTPClient(netguard.NewHTTPClient(cfg.Webhooks.Timeout)),
+			webhook.WithLogger(log),
+		)
+		workers = append(workers, deliveryWorker.Run)
+	}
+
+	return workers, nil
+}
+
+// closeDB закрывает пул БД; в демо-режиме пула нет.
+func (a *App) closeDB() {
+	if a.pg != nil {
+		a.pg.Close()
+	}
 }
@@ -2,85 +2,62 @@ package app
 
 import (
 	"clean-arch-template/config"
+	"clean-arch-template/internal/migrate"
 	"clean-arch-template/pkg/logger"
+	"clean-arch-template/pkg/metrics"
 	"context"
-	"database/sql"
 	"fmt"
-	"os"
-	"time"
-
-	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/pressly/goose/v3"
-	"github.com/pressly/goose/v3/lock"
-)
-
-const (
-	defaultAttempts = 3
-	defaultTimeout  = time.Second
 )
 
-// applyMigrations применяет миграции при старте через goose. Любая ошибка
-// возвращается наверх — сервис не должен принимать трафик на битой схеме.
-// Session-lock (pg advisory lock) защищает от параллельного применения
-// несколькими репликами. В продакшене предпочтителен отдельный Job/initContainer.
-func applyMigrations(ctx context.Context, cfg config.DB, log logger.Logger) error {
-	db, err := sql.Open("pgx", cfg.DSN())
+// applyMigrations применяет миграции при старте. Любая ошибка возвращается
+// наверх — сервис не должен принимать трафик на битой схеме. В продакшене
+// предпочтителен отдельный Job/initContainer (`migrate up`) и
+// `serve --skip-migrations`.
+func applyMigrations(ctx context.Context, cfg config.DB, m *metrics.Metrics, log logger.Logger) error {
+	migrator, err := migrate.New(ctx, cfg, log)
 	if err != nil {
-		return fmt.Errorf("migrate: open db: %w", err)
+		return err
 	}
 	defer func() {
-		if cerr := db.Close(); cerr != nil {
+		if cerr := migrator.Close(); cerr != nil {
 			log.Error(ctx, "migrate: close db", "error", cerr.Error())
 		}
 	}()
 
-	for attempts := defaultAttempts; attempts > 0; attempts-- {
-		err = db.PingContext(ctx)
-		if err == nil {
-			break
-		}
-		if attempts == 1 { // последняя попытка — пауза впустую не нужна
-			break
-		}
-		log.Debug(ctx, fmt.Sprintf("migrate: postgres is trying to connect, attempts left: %d", attempts-1))
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("migrate: cancelled while waiting for postgres: %w", ctx.Err())
-		case <-time.After(defaultTimeout):
-		}
-	}
-	if err != nil {
-		return fmt.Errorf("migrate: postgres connect: %w", err)
-	}
-
-	sessionLocker, err := lock.NewPostgresSessionLocker()
-	if err != nil {
-		return fmt.Errorf("migrate: session locker: %w", err)
-	}
-
-	provider, err := goose.NewProvider(
-		goose.DialectPostgres,
-		db,
-		os.DirFS(cfg.MigrationsDir),
-		goose.WithSessionLocker(sessionLocker),
-	)
+	results, err := migrator.Up(ctx)
 	if err != nil {
-		return fmt.Errorf("migrate: provider: %w", err)
+		return err
 	}
-	// provider.Close() не вызывается: он закрывает тот же db, который уже
-	// закрывает defer выше — второй Close был бы мёртвой веткой.
 
-	results, err := provider.Up(ctx)
+	version, err := migrator.Version(ctx)
 	if err != nil {
-		return fmt.Errorf("migrate: up: %w", err)
+		return err
 	}
+	m.MigrationsApplied(ctx, version, len(results))
 
 	if len(results) == 0 {
-		log.Info(ctx, "Migrate: no change")
+		log.Info(ctx, "Migrate: no change", "version", version)
 		return nil
 	}
 
-	log.Info(ctx, fmt.Sprintf("Migrate: applied %d migrations", len(results)))
+	log.Info(ctx, fmt.Sprintf("Migrate: applied %d migrations", len(results)), "version", version)
 
 	return nil
 }
+
+// verifyMigrations — старт с --skip-migrations: схему накатывает Job, но
+// сервис всё равно не стартует на схеме, не совпадающей со встроенным
+// набором миграций (pending, неизвестные версии, изменённые файлы).
+func verifyMigrations(ctx context.Context, cfg config.DB, log logger.Logger) error {
+	migrator, err := migrate.New(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := migrator.Close(); cerr != nil {
+			log.Error(ctx, "migrate: close db", "error", cerr.Error())
+		}
+	}()
+
+	return migrator.Verify(ctx)
+}
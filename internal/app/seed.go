@@ -0,0 +1,65 @@
+package app
+
+import (
+	"clean-arch-template/config"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// seedUsers — демо-счета для локального стенда; балансы в минимальных
+// единицах (центах).
+var seedUsers = []struct {
+	name    string
+	balance int64
+}{
+	{"Alice", 100_000},
+	{"Bob", 50_000},
+	{"Carol", 25_000},
+}
+
+// Seed наполняет пустую БД демо-данными. Повторный запуск ничего не меняет:
+// если в users уже есть строки, сид пропускается целиком. Схема должна быть
+// накатана (`migrate up`).
+func Seed(ctx context.Context, cfg config.DB, log logger.Logger) error {
+	conn, err := pgx.Connect(ctx, cfg.DSN())
+	if err != nil {
+		return fmt.Errorf("seed: connect: %w", err)
+	}
+	defer func() {
+		if cerr := conn.Close(ctx); cerr != nil {
+			log.Error(ctx, "seed: close connection", "error", cerr.Error())
+		}
+	}()
+
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		// Блокировка таблицы сериализует параллельные сиды: проверка
+		// пустоты и вставка не разъезжаются.
+		if _, err := tx.Exec(ctx, "LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+			return fmt.Errorf("seed: lock users: %w", err)
+		}
+
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users)").Scan(&exists); err != nil {
+			return fmt.Errorf("seed: check users: %w", err)
+		}
+		if exists {
+			log.Info(ctx, "Seed: users table is not empty, skipped")
+			return nil
+		}
+
+		batch := &pgx.Batch{}
+		for _, u := range seedUsers {
+			batch.Queue("INSERT INTO users (name, balance) VALUES ($1, $2)", u.name, u.balance)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("seed: insert users: %w", err)
+		}
+
+		log.Info(ctx, fmt.Sprintf("Seed: inserted %d users", len(seedUsers)))
+
+		return nil
+	})
+}
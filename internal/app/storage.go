@@ -0,0 +1,105 @@
+package app
+
+import (
+	"clean-arch-template/config"
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/internal/usecase/repository"
+	"clean-arch-template/pkg/cache"
+	"clean-arch-template/pkg/database"
+	"clean-arch-template/pkg/logger"
+	"clean-arch-template/pkg/metrics"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Хранилища пользователей (DB_BACKEND).
+const (
+	backendPostgres = "postgres"
+	backendMemory   = "memory"
+)
+
+// openPostgres подключает пул, регистрирует его метрики и применяет (или
+// проверяет) миграции. При ошибке пул закрыт.
+func openPostgres(ctx context.Context, cfg *config.Config, meterProvider metric.MeterProvider, appMetrics *metrics.Metrics, log logger.Logger, o options) (*database.Postgres, error) {
+	//nolint:contextcheck // database.New не принимает ctx: пул создаётся один раз при старте
+	pg, err := database.New(cfg,
+		database.MaxPoolSize(cfg.PoolMax),
+		database.MinPoolSize(cfg.PoolMin),
+		database.ConnTimeout(cfg.ConnectTimeout),
+		database.HealthCheckPeriod(cfg.HealthCheckPeriod),
+		database.QueryTracing(cfg.Tracing.DBSpans),
+		database.SessionTimeouts(database.Timeouts{
+			Statement: cfg.StatementTimeout,
+			Lock:      cfg.LockTimeout,
+			IdleInTx:  cfg.IdleInTxTimeout,
+		}),
+		database.TxRetry(cfg.TxMaxAttempts, cfg.TxRetryBaseDelay, cfg.TxRetryMaxDelay),
+		database.ReplicaMaxLag(cfg.ReplicaMaxLag),
+		database.ReplicaCheckPeriod(cfg.ReplicaCheckPeriod),
+		database.WithLogger(log),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("postgres connection failed: %w", err)
+	}
+
+	if _, err := metrics.RegisterPool(meterProvider, pg.Pool); err != nil {
+		pg.Close()
+		return nil, fmt.Errorf("pool metrics: %w", err)
+	}
+
+	if o.skipMigrations {
+		if err := verifyMigrations(ctx, cfg.DB, log); err != nil {
+			pg.Close()
+			return nil, fmt.Errorf("verify migrations failed: %w", err)
+		}
+		log.Info(ctx, "Migrate: skipped (--skip-migrations), schema verified")
+	} else if err := applyMigrations(ctx, cfg.DB, appMetrics, log); err != nil {
+		pg.Close()
+		return nil, fmt.Errorf("apply migrations failed: %w", err)
+	}
+
+	return pg, nil
+}
+
+// newDemoUserRepository — хранилище демо-режима, наполненное теми же
+// счетами, что и `seed` для Postgres.
+func newDemoUserRepository(ctx context.Context) (*repository.MemoryUserRepository, error) {
+	repo := repository.NewMemoryUserRepository()
+	for _, u := range seedUsers {
+		user, err := repo.InsertUser(ctx, &entity.User{Name: u.name})
+		if err != nil {
+			return nil, fmt.Errorf("demo seed: %w", err)
+		}
+		if err := repo.SetBalance(user.ID, u.balance); err != nil {
+			return nil, fmt.Errorf("demo seed: %w", err)
+		}
+	}
+	return repo, nil
+}
+
+// newCachedUserRepository оборачивает репозиторий кэшем чтений; инвалидация
+// рассылается через pg_notify, слушатель запускается воркером.
+func newCachedUserRepository(cfg *config.Config, next usecase.UserRepository, pg *database.Postgres, log logger.Logger) (*repository.CachedUserRepository, error) {
+	repo, err := repository.NewCachedUserRepository(next,
+		cache.NewLRU[entity.User](cfg.UserCache.Size, cfg.UserCache.TTL),
+		cache.NewLRU[[]entity.User](cfg.UserCache.Size, cfg.UserCache.TTL),
+		pg.DBGetter,
+		log,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("user cache: %w", err)
+	}
+	return repo, nil
+}
+
+// pgConnect — выделенное соединение с настройками пула для LISTEN: в пуле его
+// держать нельзя, оно занято всё время жизни слушателя.
+func pgConnect(pg *database.Postgres) func(ctx context.Context) (*pgx.Conn, error) {
+	return func(ctx context.Context) (*pgx.Conn, error) {
+		return pgx.ConnectConfig(ctx, pg.Pool.Config().ConnConfig.Copy())
+	}
+}
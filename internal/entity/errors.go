@@ -13,4 +13,15 @@ var (
 	ErrInsufficientFunds     = errors.New("insufficient funds")
 	ErrSourceAccountNotFound = errors.New("source account not found")
 	ErrDestAccountNotFound   = errors.New("destination account not found")
+	ErrWebhookNotFound       = errors.New("webhook subscription not found")
+	ErrInvalidWebhookURL     = errors.New("webhook url must be an absolute http(s) url of a public host")
+	ErrInvalidEventTypes     = errors.New("event types must be a non-empty list of transfer.debited, transfer.credited")
+	ErrInvalidWebhookSecret  = errors.New("webhook secret must be at least 16 characters")
+	ErrTooManyStreams        = errors.New("too many concurrent event streams, retry later")
+	ErrStreamClosed          = errors.New("event stream closed: server is shutting down")
+	// ErrQueryTimeout — запрос прерван statement_timeout (или транзакция —
+	// idle_in_transaction_session_timeout); ErrResourceBusy — не дождались
+	// блокировки (lock_timeout) и после повторов.
+	ErrQueryTimeout = errors.New("database query timed out")
+	ErrResourceBusy = errors.New("resource is locked by another operation, retry later")
 )
@@ -0,0 +1,23 @@
+package entity
+
+// EventType — тип доменного события; строка уходит во внешние системы как есть,
+// поэтому значения — часть публичного контракта и не переименовываются.
+type EventType string
+
+const (
+	EventUserCreated      EventType = "user.created"
+	EventUserUpdated      EventType = "user.updated"
+	EventUserDeleted      EventType = "user.deleted"
+	EventMoneyTransferred EventType = "transfer.completed"
+
+	// События с точки зрения одного счёта: transfer.completed раскладывается
+	// на списание у отправителя и зачисление у получателя.
+	EventTransferDebited  EventType = "transfer.debited"
+	EventTransferCredited EventType = "transfer.credited"
+)
+
+// UserDeleted — payload события user.deleted: от удалённого пользователя
+// остаётся только идентификатор.
+type UserDeleted struct {
+	ID int `json:"id"`
+}
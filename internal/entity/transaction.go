@@ -17,3 +17,33 @@ type Transfer struct {
 	// Amount in minimal currency units, 100 cents = 1$.
 	Amount int64 `json:"amount"`
 }
+
+// AccountEvent — перевод глазами одного из счетов: списание или зачисление.
+// ID совпадает с id строки transactions — по нему клиент возобновляет поток.
+type AccountEvent struct {
+	ID             int64     `json:"id"`
+	Type           EventType `json:"type"`
+	AccountID      int64     `json:"account_id"`
+	CounterpartyID int64     `json:"counterparty_id"`
+	// Amount in minimal currency units, 100 cents = 1$.
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EventFor возвращает перевод с точки зрения accountID.
+func (t Transaction) EventFor(accountID int64) AccountEvent {
+	event := AccountEvent{
+		ID:             t.ID,
+		Type:           EventTransferCredited,
+		AccountID:      accountID,
+		CounterpartyID: t.FromUserID,
+		Amount:         t.Amount,
+		CreatedAt:      t.CreatedAt,
+	}
+	if t.FromUserID == accountID {
+		event.Type = EventTransferDebited
+		event.CounterpartyID = t.ToUserID
+	}
+
+	return event
+}
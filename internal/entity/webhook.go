@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// WebhookSubscription — подписка партнёра на события своего счёта.
+// Secret хранится в открытом виде: им подписывается каждая доставка
+// (HMAC-SHA256), поэтому хеш здесь не подходит.
+type WebhookSubscription struct {
+	ID         int64       `json:"id"`
+	AccountID  int64       `json:"account_id"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	Secret     string      `json:"-"`
+	// Enabled сбрасывается автоматически после серии неудачных доставок.
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransferNotification — payload доставок transfer.debited/transfer.credited:
+// перевод глазами владельца AccountID.
+type TransferNotification struct {
+	AccountID      int64 `json:"account_id"`
+	CounterpartyID int64 `json:"counterparty_id"`
+	// Amount in minimal currency units, 100 cents = 1$.
+	Amount int64 `json:"amount"`
+}
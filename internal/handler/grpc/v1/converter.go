@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/handler/grpc/v1/userpb"
+)
+
+func toUserPB(user entity.User) *userpb.User {
+	return &userpb.User{Id: int64(user.ID), Name: user.Name}
+}
+
+func ToListUsersResponseFromEntity(users []entity.User) *userpb.ListUsersResponse {
+	resp := &userpb.ListUsersResponse{Users: make([]*userpb.User, 0, len(users))}
+
+	for _, user := range users {
+		resp.Users = append(resp.Users, toUserPB(user))
+	}
+
+	return resp
+}
+
+func ToUserPBFromEntity(user *entity.User) *userpb.User {
+	return toUserPB(*user)
+}
+
+func ToTransferEntity(req *userpb.TransferMoneyRequest) entity.Transfer {
+	return entity.Transfer{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+	}
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/handler/grpc/v1/userpb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToListUsersResponseFromEntity(t *testing.T) {
+	t.Run("empty list", func(t *testing.T) {
+		result := ToListUsersResponseFromEntity([]entity.User{})
+		assert.Empty(t, result.GetUsers())
+	})
+
+	t.Run("list with users", func(t *testing.T) {
+		users := []entity.User{
+			{ID: 1, Name: "User 1"},
+			{ID: 2, Name: "User 2"},
+		}
+		result := ToListUsersResponseFromEntity(users)
+
+		assert.Len(t, result.GetUsers(), 2)
+		assert.Equal(t, int64(1), result.GetUsers()[0].GetId())
+		assert.Equal(t, "User 1", result.GetUsers()[0].GetName())
+		assert.Equal(t, int64(2), result.GetUsers()[1].GetId())
+		assert.Equal(t, "User 2", result.GetUsers()[1].GetName())
+	})
+}
+
+func TestToUserPBFromEntity(t *testing.T) {
+	result := ToUserPBFromEntity(&entity.User{ID: 1, Name: "Test User"})
+
+	assert.Equal(t, int64(1), result.GetId())
+	assert.Equal(t, "Test User", result.GetName())
+}
+
+func TestToTransferEntity(t *testing.T) {
+	result := ToTransferEntity(&userpb.TransferMoneyRequest{
+		FromAccountId: 1,
+		ToAccountId:   2,
+		Amount:        100,
+	})
+
+	assert.Equal(t, entity.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 100}, result)
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mapError маппит доменные ошибки в gRPC-статусы по тем же правилам, что и
+// REST (rest/v1/errors.go). Неизвестные ошибки логируются (с trace_id из ctx)
+// и уходят клиенту как generic Internal.
+func (uh *UserHandler) mapError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, entity.ErrUserNotFound),
+		errors.Is(err, entity.ErrSourceAccountNotFound),
+		errors.Is(err, entity.ErrDestAccountNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, entity.ErrInvalidUserName),
+		errors.Is(err, entity.ErrInvalidPagination),
+		errors.Is(err, entity.ErrNegativeAmount),
+		errors.Is(err, entity.ErrSameAccount):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrInsufficientFunds):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entity.ErrResourceBusy):
+		uh.log.Warn(ctx, "request failed", "error", err.Error())
+		return status.Error(codes.Unavailable, entity.ErrResourceBusy.Error())
+	case errors.Is(err, entity.ErrQueryTimeout):
+		uh.log.Warn(ctx, "request failed", "error", err.Error())
+		return status.Error(codes.DeadlineExceeded, entity.ErrQueryTimeout.Error())
+	default:
+		uh.log.Error(ctx, "request failed", "error", err.Error())
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
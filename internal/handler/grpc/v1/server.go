@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"clean-arch-template/internal/handler/grpc/v1/userpb"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// NewServer собирает gRPC-сервер: otelgrpc stats handler создаёт server-спаны
+// и извлекает trace context из metadata (аналог otelfiber для REST),
+// стандартный health-сервис отдаёт статус для балансировщиков и k8s-проб.
+// Статусом health управляет вызывающий код: SERVING после старта,
+// NOT_SERVING при graceful shutdown.
+func NewServer(userHandler userpb.UserServiceServer) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	userpb.RegisterUserServiceServer(server, userHandler)
+
+	return server, healthServer
+}
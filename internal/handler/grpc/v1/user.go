@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"clean-arch-template/internal/handler/grpc/v1/userpb"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	_ UserUseCase              = (*usecase.UserUseCase)(nil)
+	_ userpb.UserServiceServer = (*UserHandler)(nil)
+)
+
+const tracerName = "user grpc handler"
+
+// Ограничения, которые в REST проверяет схема Huma (rest/v1/schemas.go).
+// У protobuf схемы валидации нет, поэтому транспорт проверяет их сам;
+// доменные правила остаются в use case.
+const (
+	maxPageSize   = 1000
+	maxNameLength = 255
+)
+
+type UserHandler struct {
+	userpb.UnimplementedUserServiceServer
+
+	userUC UserUseCase
+	log    logger.Logger
+}
+
+func NewUserHandler(uc UserUseCase, log logger.Logger) *UserHandler {
+	return &UserHandler{userUC: uc, log: log}
+}
+
+func (uh *UserHandler) ListUsers(ctx context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	// otelgrpc уже создаёт server-спан; здесь — вложенный internal-спан,
+	// новый ctx передаётся вниз, чтобы спаны usecase/pgx стали его детьми.
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ListUsers")
+	defer span.End()
+
+	if req.GetSize() > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page size must not exceed %d", maxPageSize)
+	}
+
+	cmd := usecase.FindAllUsersCommand{
+		Page: int(req.GetPage()),
+		Size: int(req.GetSize()),
+	}
+
+	users, err := uh.userUC.FindAllUsers(ctx, cmd)
+	if err != nil {
+		return nil, uh.mapError(ctx, err)
+	}
+
+	return ToListUsersResponseFromEntity(users), nil
+}
+
+func (uh *UserHandler) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.GetUserResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "GetUser")
+	defer span.End()
+
+	cmd := usecase.FindUserByIDCommand{ID: int(req.GetId())}
+
+	user, err := uh.userUC.FindUserByID(ctx, cmd)
+	if err != nil {
+		return nil, uh.mapError(ctx, err)
+	}
+
+	return &userpb.GetUserResponse{User: ToUserPBFromEntity(user)}, nil
+}
+
+func (uh *UserHandler) CreateUser(ctx context.Context, req *userpb.CreateUserRequest) (*userpb.CreateUserResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "CreateUser")
+	defer span.End()
+
+	if err := validateNameLength(req.GetName()); err != nil {
+		return nil, err
+	}
+
+	cmd := usecase.CreateUpdateUserCommand{}
+	cmd.User.Name = req.GetName()
+
+	user, err := uh.userUC.CreateUser(ctx, cmd)
+	if err != nil {
+		return nil, uh.mapError(ctx, err)
+	}
+
+	return &userpb.CreateUserResponse{User: ToUserPBFromEntity(user)}, nil
+}
+
+func (uh *UserHandler) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.UpdateUserResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "UpdateUser")
+	defer span.End()
+
+	if err := validateNameLength(req.GetName()); err != nil {
+		return nil, err
+	}
+
+	cmd := usecase.CreateUpdateUserCommand{}
+	cmd.User.ID = int(req.GetId())
+	cmd.User.Name = req.GetName()
+
+	user, err := uh.userUC.UpdateUser(ctx, cmd)
+	if err != nil {
+		return nil, uh.mapError(ctx, err)
+	}
+
+	return &userpb.UpdateUserResponse{User: ToUserPBFromEntity(user)}, nil
+}
+
+func (uh *UserHandler) DeleteUser(ctx context.Context, req *userpb.DeleteUserRequest) (*userpb.DeleteUserResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "DeleteUser")
+	defer span.End()
+
+	cmd := usecase.DeleteUserByIDCommand{ID: int(req.GetId())}
+
+	if err := uh.userUC.DeleteUser(ctx, cmd); err != nil {
+		return nil, uh.mapError(ctx, err)
+	}
+
+	return &userpb.DeleteUserResponse{}, nil
+}
+
+func (uh *UserHandler) TransferMoney(ctx context.Context, req *userpb.TransferMoneyRequest) (*userpb.TransferMoneyResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "TransferMoney")
+	defer span.End()
+
+	cmd := usecase.TransferMoneyCommand{Transfer: ToTransferEntity(req)}
+
+	if err := uh.userUC.TransferMoney(ctx, cmd); err != nil {
+		return nil, uh.mapError(ctx, err)
+	}
+
+	return &userpb.TransferMoneyResponse{}, nil
+}
+
+// validateNameLength проверяет только верхнюю границу: пустое и не-UTF-8
+// имя отклоняет use case доменной ошибкой.
+func validateNameLength(name string) error {
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return status.Errorf(codes.InvalidArgument, "user name must not exceed %d characters", maxNameLength)
+	}
+
+	return nil
+}
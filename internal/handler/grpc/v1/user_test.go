@@ -0,0 +1,233 @@
+package v1
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/handler/grpc/v1/userpb"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/logger/loggertest"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient поднимает настоящий gRPC-сервер из NewServer поверх bufconn:
+// проверяется весь путь — регистрация, сериализация, маппинг статусов.
+func newTestClient(t *testing.T) (userpb.UserServiceClient, healthpb.HealthClient, *MockUserUseCase, *loggertest.Fake) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	uc := NewMockUserUseCase(ctrl)
+	fakeLog := &loggertest.Fake{}
+
+	server, _ := NewServer(NewUserHandler(uc, fakeLog))
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return userpb.NewUserServiceClient(conn), healthpb.NewHealthClient(conn), uc, fakeLog
+}
+
+func TestListUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		client, _, uc, _ := newTestClient(t)
+
+		uc.EXPECT().FindAllUsers(gomock.Any(), usecase.FindAllUsersCommand{Page: 1, Size: 10}).
+			Return([]entity.User{{ID: 1, Name: "Test User 1"}, {ID: 2, Name: "Test User 2"}}, nil)
+
+		resp, err := client.ListUsers(context.Background(), &userpb.ListUsersRequest{Page: 1, Size: 10})
+		require.NoError(t, err)
+		require.Len(t, resp.GetUsers(), 2)
+		assert.Equal(t, int64(2), resp.GetUsers()[1].GetId())
+		assert.Equal(t, "Test User 2", resp.GetUsers()[1].GetName())
+	})
+
+	t.Run("page size over limit is rejected without use case call", func(t *testing.T) {
+		client, _, _, _ := newTestClient(t)
+
+		_, err := client.ListUsers(context.Background(), &userpb.ListUsersRequest{Page: 1, Size: maxPageSize + 1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	t.Parallel()
+
+	client, _, uc, _ := newTestClient(t)
+
+	uc.EXPECT().FindUserByID(gomock.Any(), usecase.FindUserByIDCommand{ID: 1}).
+		Return(&entity.User{ID: 1, Name: "Mike"}, nil)
+
+	resp, err := client.GetUser(context.Background(), &userpb.GetUserRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.GetUser().GetId())
+	assert.Equal(t, "Mike", resp.GetUser().GetName())
+}
+
+func TestCreateUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		client, _, uc, _ := newTestClient(t)
+
+		cmd := usecase.CreateUpdateUserCommand{User: entity.User{Name: "Mike"}}
+		uc.EXPECT().CreateUser(gomock.Any(), cmd).Return(&entity.User{ID: 3, Name: "Mike"}, nil)
+
+		resp, err := client.CreateUser(context.Background(), &userpb.CreateUserRequest{Name: "Mike"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), resp.GetUser().GetId())
+	})
+
+	t.Run("too long name is rejected without use case call", func(t *testing.T) {
+		client, _, _, _ := newTestClient(t)
+
+		_, err := client.CreateUser(context.Background(), &userpb.CreateUserRequest{Name: strings.Repeat("я", maxNameLength+1)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	t.Parallel()
+
+	client, _, uc, _ := newTestClient(t)
+
+	cmd := usecase.CreateUpdateUserCommand{User: entity.User{ID: 1, Name: "Updated"}}
+	uc.EXPECT().UpdateUser(gomock.Any(), cmd).Return(&entity.User{ID: 1, Name: "Updated"}, nil)
+
+	resp, err := client.UpdateUser(context.Background(), &userpb.UpdateUserRequest{Id: 1, Name: "Updated"})
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", resp.GetUser().GetName())
+}
+
+func TestDeleteUser(t *testing.T) {
+	t.Parallel()
+
+	client, _, uc, _ := newTestClient(t)
+
+	uc.EXPECT().DeleteUser(gomock.Any(), usecase.DeleteUserByIDCommand{ID: 1}).Return(nil)
+
+	_, err := client.DeleteUser(context.Background(), &userpb.DeleteUserRequest{Id: 1})
+	require.NoError(t, err)
+}
+
+func TestTransferMoney(t *testing.T) {
+	t.Parallel()
+
+	client, _, uc, _ := newTestClient(t)
+
+	cmd := usecase.TransferMoneyCommand{Transfer: entity.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 100}}
+	uc.EXPECT().TransferMoney(gomock.Any(), cmd).Return(nil)
+
+	_, err := client.TransferMoney(context.Background(), &userpb.TransferMoneyRequest{
+		FromAccountId: 1,
+		ToAccountId:   2,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+}
+
+func TestErrorMapping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "user not found", err: entity.ErrUserNotFound, code: codes.NotFound},
+		{name: "source not found", err: entity.ErrSourceAccountNotFound, code: codes.NotFound},
+		{name: "destination not found", err: entity.ErrDestAccountNotFound, code: codes.NotFound},
+		{name: "negative amount", err: entity.ErrNegativeAmount, code: codes.InvalidArgument},
+		{name: "same account", err: entity.ErrSameAccount, code: codes.InvalidArgument},
+		{name: "insufficient funds", err: entity.ErrInsufficientFunds, code: codes.FailedPrecondition},
+		{name: "wrapped domain error", err: errors.Join(errors.New("ctx"), entity.ErrInsufficientFunds), code: codes.FailedPrecondition},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, _, uc, fakeLog := newTestClient(t)
+
+			uc.EXPECT().TransferMoney(gomock.Any(), gomock.Any()).Return(tc.err)
+
+			_, err := client.TransferMoney(context.Background(), &userpb.TransferMoneyRequest{FromAccountId: 1, ToAccountId: 2, Amount: 1})
+			assert.Equal(t, tc.code, status.Code(err))
+			assert.Empty(t, fakeLog.Entries, "доменные ошибки не логируются")
+		})
+	}
+}
+
+func TestDatabaseTimeoutsMapping(t *testing.T) {
+	t.Parallel()
+
+	tests := map[error]codes.Code{
+		entity.ErrQueryTimeout: codes.DeadlineExceeded,
+		entity.ErrResourceBusy: codes.Unavailable,
+	}
+	for domainErr, code := range tests {
+		client, _, uc, fakeLog := newTestClient(t)
+
+		uc.EXPECT().FindUserByID(gomock.Any(), gomock.Any()).
+			Return(nil, errors.Join(domainErr, errors.New("canceling statement due to statement timeout")))
+
+		_, err := client.GetUser(context.Background(), &userpb.GetUserRequest{Id: 1})
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, code, st.Code())
+		assert.Equal(t, domainErr.Error(), st.Message(), "текст Postgres клиенту не уходит")
+		require.Len(t, fakeLog.Entries, 1)
+		assert.Equal(t, "WARN", fakeLog.Entries[0].Level)
+	}
+}
+
+func TestInternalErrorIsLoggedAndMasked(t *testing.T) {
+	t.Parallel()
+
+	client, _, uc, fakeLog := newTestClient(t)
+
+	uc.EXPECT().FindUserByID(gomock.Any(), gomock.Any()).Return(nil, errors.New("boom"))
+
+	_, err := client.GetUser(context.Background(), &userpb.GetUserRequest{Id: 1})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal server error", st.Message())
+	require.Len(t, fakeLog.Entries, 1)
+	assert.Equal(t, "ERROR", fakeLog.Entries[0].Level)
+}
+
+func TestHealthService(t *testing.T) {
+	t.Parallel()
+
+	_, health, _, _ := newTestClient(t)
+
+	resp, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
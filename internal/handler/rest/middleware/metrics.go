@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// durationBuckets — границы из семантических конвенций OTel для
+// http.server.request.duration.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// HTTPMetrics пишет http.server.request.duration (в Prometheus —
+// http_server_request_duration_seconds) по методу, шаблону маршрута и
+// статусу. Ставится после otelfiber: в user context уже лежит спан
+// запроса, и SDK прикладывает к бакетам exemplars с его trace_id. Метрики
+// самого otelfiber при этом выключают (WithoutMetrics): он пишет их с
+// контекстом без спана.
+func HTTPMetrics(mp metric.MeterProvider, skipPaths ...string) (fiber.Handler, error) {
+	duration, err := mp.Meter("clean-arch-template/internal/handler/rest").Float64Histogram(
+		"http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[p] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if _, ok := skip[c.Path()]; ok {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		start := time.Now()
+
+		err := c.Next()
+
+		// Ошибку, вернувшуюся по цепочке, в ответ превратит ErrorHandler уже
+		// после нас — статус берём из неё.
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+
+		duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("http.request.method", c.Method()),
+			attribute.String("http.route", c.Route().Path),
+			attribute.Int("http.response.status_code", status),
+		))
+
+		return err
+	}, nil
+}
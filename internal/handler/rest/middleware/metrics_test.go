@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	httpMetrics, err := HTTPMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), "/metrics")
+	require.NoError(t, err)
+
+	tracer := sdktrace.NewTracerProvider().Tracer("test")
+	var span trace.Span
+
+	app := fiber.New()
+	// Вместо otelfiber: кладём спан в user context до HTTPMetrics.
+	app.Use(func(c *fiber.Ctx) error {
+		var ctx context.Context
+		ctx, span = tracer.Start(c.UserContext(), "request")
+		defer span.End()
+		c.SetUserContext(ctx)
+		return c.Next()
+	})
+	app.Use(httpMetrics)
+	app.Get("/user/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/boom", func(*fiber.Ctx) error { return fiber.ErrServiceUnavailable })
+	app.Get("/metrics", func(c *fiber.Ctx) error { return c.SendString("") })
+
+	for _, path := range []string{"/user/1", "/user/2", "/boom", "/metrics"} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	md := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "http.server.request.duration", md.Name)
+	assert.Equal(t, "s", md.Unit)
+
+	counts := map[string]uint64{}
+	for _, dp := range md.Data.(metricdata.Histogram[float64]).DataPoints {
+		route, _ := dp.Attributes.Value(attribute.Key("http.route"))
+		status, _ := dp.Attributes.Value(attribute.Key("http.response.status_code"))
+		counts[route.AsString()+" "+status.Emit()] += dp.Count
+
+		require.NotEmpty(t, dp.Exemplars, "exemplar с trace_id запроса")
+		assert.Len(t, dp.Exemplars[0].TraceID, 16)
+	}
+
+	// /metrics пропущен, id в пути не размножает серии.
+	assert.Equal(t, map[string]uint64{"/user/:id 200": 2, "/boom 503": 1}, counts)
+}
@@ -0,0 +1,167 @@
+// Package middleware — fiber middleware REST-слоя.
+package middleware
+
+import (
+	"clean-arch-template/pkg/logger"
+	"clean-arch-template/pkg/ratelimit"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultQuotaName = "default"
+
+// Quota — лимит операции: Method и Path (префикс по сегментам пути).
+type Quota struct {
+	Method string
+	Path   string
+	Limit  ratelimit.Limit
+}
+
+func (q Quota) name() string {
+	return q.Method + " " + q.Path
+}
+
+// matches сравнивает без учёта регистра: роутер fiber по умолчанию
+// регистронезависим, и /Transfer попадает в тот же обработчик, что и
+// /transfer, — квота не должна обходиться сменой регистра.
+func (q Quota) matches(method, path string) bool {
+	if !strings.EqualFold(q.Method, method) {
+		return false
+	}
+	prefix := strings.TrimSuffix(q.Path, "/") + "/"
+	return strings.EqualFold(path, q.Path) || len(path) >= len(prefix) && strings.EqualFold(path[:len(prefix)], prefix)
+}
+
+// ParseQuotas разбирает квоты из конфига: "<METHOD> <путь>" → "<запросов>/<период>".
+func ParseQuotas(routes map[string]string) ([]Quota, error) {
+	quotas := make([]Quota, 0, len(routes))
+	for route, raw := range routes {
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("rate limit route %q: want \"<METHOD> /path\"", route)
+		}
+
+		limit, err := ratelimit.ParseLimit(raw)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit route %q: %w", route, err)
+		}
+
+		quotas = append(quotas, Quota{Method: strings.ToUpper(method), Path: path, Limit: limit})
+	}
+
+	return quotas, nil
+}
+
+type RateLimitConfig struct {
+	Store ratelimit.Store
+	// Default — квота операций без своей записи в Quotas.
+	Default ratelimit.Limit
+	Quotas  []Quota
+	Log     logger.Logger
+}
+
+// RateLimit — token bucket на пару (операция, клиент). Отвечает 429 c
+// Retry-After; заголовки RateLimit-* (draft-ietf-httpapi-ratelimit-headers)
+// ставятся на каждый ответ. Ошибка хранилища не блокирует запросы: лимитер
+// защищает БД, и недоступность БД не должна дополнительно валить API.
+func RateLimit(cfg RateLimitConfig) fiber.Handler {
+	return NewRateLimiter(cfg).Handler()
+}
+
+// RateLimiter — RateLimit с квотами, которые меняются на лету (горячая
+// перезагрузка конфига). Хранилище вёдер общее для всех версий квот:
+// клиент, выбравший квоту, не получает её заново после перезагрузки.
+type RateLimiter struct {
+	store ratelimit.Store
+	log   logger.Logger
+	rules atomic.Pointer[rateLimitRules]
+}
+
+// rateLimitRules — действующие квоты; подменяются целиком.
+type rateLimitRules struct {
+	disabled bool
+	def      ratelimit.Limit
+	// quotas отсортированы: самый длинный путь — самый конкретный — первым.
+	quotas []Quota
+}
+
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{store: cfg.Store, log: cfg.Log}
+	if l.log == nil {
+		l.log = logger.Nop()
+	}
+	l.Update(true, cfg.Default, cfg.Quotas)
+
+	return l
+}
+
+// Update атомарно подменяет квоты; enabled=false пропускает все запросы без
+// проверки и заголовков RateLimit-*.
+func (l *RateLimiter) Update(enabled bool, def ratelimit.Limit, quotas []Quota) {
+	sorted := slices.Clone(quotas)
+	slices.SortFunc(sorted, func(a, b Quota) int { return len(b.Path) - len(a.Path) })
+
+	l.rules.Store(&rateLimitRules{disabled: !enabled, def: def, quotas: sorted})
+}
+
+func (l *RateLimiter) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rules := l.rules.Load()
+		if rules.disabled {
+			return c.Next()
+		}
+
+		name, limit := defaultQuotaName, rules.def
+		for _, quota := range rules.quotas {
+			if quota.matches(c.Method(), c.Path()) {
+				name, limit = quota.name(), quota.Limit
+				break
+			}
+		}
+
+		res, err := l.store.Take(c.UserContext(), name+"|"+ClientKey(c), limit)
+		if err != nil {
+			l.log.Error(c.UserContext(), "rate limit check failed, request allowed", "error", err.Error())
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		c.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Period())))
+
+		if !res.Allowed {
+			retryAfter := max(ceilSeconds(res.RetryAfter), 1)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+			return c.Status(http.StatusTooManyRequests).JSON(NewProblem(c.UserContext(), &huma.ErrorModel{
+				Title:  http.StatusText(http.StatusTooManyRequests),
+				Status: http.StatusTooManyRequests,
+				Detail: fmt.Sprintf("rate limit exceeded for %s, retry in %ds", name, retryAfter),
+			}), "application/problem+json")
+		}
+
+		return c.Next()
+	}
+}
+
+// ClientKey — чей это запрос. Аутентификации в API нет, поэтому ключ — IP
+// клиента. За ингрессом это адрес из HTTP.ProxyHeader, но только если запрос
+// пришёл от доверенного прокси (HTTP.TrustedProxies); иначе — адрес
+// соединения, и подделанный заголовок не даёт обойти лимит.
+func ClientKey(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"clean-arch-template/pkg/ratelimit"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedApp(t *testing.T, store ratelimit.Store) *fiber.App {
+	t.Helper()
+
+	return newRateLimitedAppWithConfig(t, store, fiber.Config{})
+}
+
+func newRateLimitedAppWithConfig(t *testing.T, store ratelimit.Store, cfg fiber.Config) *fiber.App {
+	t.Helper()
+
+	quotas, err := ParseQuotas(map[string]string{
+		"POST /transfer": "1/1m",
+		"GET /users":     "2/1m",
+	})
+	require.NoError(t, err)
+
+	app := fiber.New(cfg)
+	app.Use(RateLimit(RateLimitConfig{
+		Store:   store,
+		Default: ratelimit.PerPeriod(100, time.Minute),
+		Quotas:  quotas,
+	}))
+	app.All("/*", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusNoContent) })
+
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, method, path, realIP string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	if realIP != "" {
+		req.Header.Set("X-Real-IP", realIP)
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func TestRateLimitPerRouteQuota(t *testing.T) {
+	t.Parallel()
+
+	app := newRateLimitedApp(t, ratelimit.NewMemoryStore())
+
+	resp := do(t, app, http.MethodPost, "/transfer", "")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "1;w=60", resp.Header.Get("RateLimit-Policy"))
+
+	resp = do(t, app, http.MethodPost, "/transfer", "")
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+
+	// Список — своя, более мягкая квота; прочие операции — квота по умолчанию.
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodGet, "/users/1/10", "").StatusCode)
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodGet, "/users/2/10", "").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, do(t, app, http.MethodGet, "/users/3/10", "").StatusCode)
+
+	resp = do(t, app, http.MethodGet, "/user/1", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "100", resp.Header.Get("RateLimit-Limit"))
+}
+
+func TestRateLimitQuotaIgnoresPathCase(t *testing.T) {
+	t.Parallel()
+
+	app := newRateLimitedApp(t, ratelimit.NewMemoryStore())
+
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodPost, "/transfer", "").StatusCode)
+	for _, path := range []string{"/Transfer", "/TRANSFER", "/transfer/"} {
+		resp := do(t, app, http.MethodPost, path, "")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "%s — тот же маршрут и та же квота", path)
+	}
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodPost, "/transfers", "").StatusCode,
+		"префикс — по сегментам пути")
+}
+
+func TestRateLimitKeyedByClientIPFromTrustedProxy(t *testing.T) {
+	t.Parallel()
+
+	// app.Test отдаёт запрос с адреса 0.0.0.0 — им и представлен ингресс.
+	app := newRateLimitedAppWithConfig(t, ratelimit.NewMemoryStore(), fiber.Config{
+		ProxyHeader:             "X-Real-IP",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0/32"},
+		EnableIPValidation:      true,
+	})
+
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodPost, "/transfer", "203.0.113.1").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, do(t, app, http.MethodPost, "/transfer", "203.0.113.1").StatusCode)
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodPost, "/transfer", "203.0.113.2").StatusCode,
+		"у другого клиента за тем же ингрессом своё ведро")
+}
+
+func TestRateLimitIgnoresProxyHeaderFromUntrustedPeer(t *testing.T) {
+	t.Parallel()
+
+	app := newRateLimitedAppWithConfig(t, ratelimit.NewMemoryStore(), fiber.Config{
+		ProxyHeader:             "X-Real-IP",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"10.0.0.0/8"},
+		EnableIPValidation:      true,
+	})
+
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodPost, "/transfer", "203.0.113.1").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, do(t, app, http.MethodPost, "/transfer", "203.0.113.2").StatusCode,
+		"заголовок от недоверенного адреса не меняет ключ")
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("db down")
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	t.Parallel()
+
+	app := newRateLimitedApp(t, failingStore{})
+
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodPost, "/transfer", "").StatusCode)
+}
+
+func TestRateLimiterUpdate(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(RateLimitConfig{
+		Store:   ratelimit.NewMemoryStore(),
+		Default: ratelimit.PerPeriod(1, time.Minute),
+	})
+	app := fiber.New()
+	app.Use(limiter.Handler())
+	app.All("/*", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusNoContent) })
+
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodPost, "/transfer", "").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, do(t, app, http.MethodPost, "/transfer", "").StatusCode)
+
+	quotas, err := ParseQuotas(map[string]string{"POST /transfer": "5/1m"})
+	require.NoError(t, err)
+	limiter.Update(true, ratelimit.PerPeriod(1, time.Minute), quotas)
+	resp := do(t, app, http.MethodPost, "/transfer", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode, "новая квота — новое ведро")
+	assert.Equal(t, "5", resp.Header.Get("RateLimit-Limit"))
+
+	limiter.Update(false, ratelimit.PerPeriod(1, time.Minute), nil)
+	resp = do(t, app, http.MethodGet, "/user/1", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("RateLimit-Limit"), "выключенный лимитер не ставит заголовки")
+	assert.Equal(t, http.StatusNoContent, do(t, app, http.MethodGet, "/user/1", "").StatusCode)
+}
+
+func TestParseQuotasRejectsMalformedRoute(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseQuotas(map[string]string{"/transfer": "1/1m"})
+	require.Error(t, err)
+
+	_, err = ParseQuotas(map[string]string{"POST /transfer": "fast"})
+	require.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"clean-arch-template/pkg/requestid"
+	"context"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestID принимает X-Request-ID клиента (если он проходит
+// requestid.Valid) или генерирует новый, возвращает его в ответе и кладёт в
+// user context — оттуда его берут логгер и ответы об ошибках.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Set(requestid.Header, id)
+		c.SetUserContext(requestid.NewContext(c.UserContext(), id))
+
+		return c.Next()
+	}
+}
+
+// Problem — problem+json (RFC 9457) с расширением request_id: по нему
+// клиент сообщает об ошибке, а мы находим её в логах.
+type Problem struct {
+	*huma.ErrorModel
+
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewProblem дополняет model идентификатором запроса из ctx.
+func NewProblem(ctx context.Context, model *huma.ErrorModel) *Problem {
+	return &Problem{ErrorModel: model, RequestID: requestid.FromContext(ctx)}
+}
+
+// ProblemTransformer — huma.Transformer, добавляющий request_id во все
+// ошибки Huma, включая ошибки валидации, которые до хендлеров не доходят.
+func ProblemTransformer(ctx huma.Context, _ string, v any) (any, error) {
+	if model, ok := v.(*huma.ErrorModel); ok {
+		return NewProblem(ctx.Context(), model), nil
+	}
+
+	return v, nil
+}
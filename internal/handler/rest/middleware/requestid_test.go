@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"clean-arch-template/pkg/requestid"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humafiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestIDApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	app := fiber.New()
+	app.Use(RequestID())
+
+	config := huma.DefaultConfig("test", "1.0.0")
+	config.Transformers = append(config.Transformers, ProblemTransformer)
+	api := humafiber.New(app, config)
+
+	huma.Register(api, huma.Operation{Method: http.MethodGet, Path: "/echo"},
+		func(ctx context.Context, _ *struct{}) (*struct{ Body string }, error) {
+			return &struct{ Body string }{Body: requestid.FromContext(ctx)}, nil
+		})
+	huma.Register(api, huma.Operation{Method: http.MethodGet, Path: "/missing"},
+		func(context.Context, *struct{}) (*struct{}, error) {
+			return nil, huma.Error404NotFound("user not found")
+		})
+
+	return app
+}
+
+func get(t *testing.T, app *fiber.App, path, requestID string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if requestID != "" {
+		req.Header.Set(requestid.Header, requestID)
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	var body map[string]any
+	if resp.StatusCode >= http.StatusBadRequest {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	}
+
+	return resp, body
+}
+
+func TestRequestIDAcceptsClientID(t *testing.T) {
+	t.Parallel()
+
+	resp, _ := get(t, newRequestIDApp(t), "/echo", "client-req-1")
+
+	assert.Equal(t, "client-req-1", resp.Header.Get(requestid.Header))
+
+	var body string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "client-req-1", body, "ID доступен хендлерам через context")
+}
+
+func TestRequestIDGeneratesWhenMissingOrInvalid(t *testing.T) {
+	t.Parallel()
+
+	app := newRequestIDApp(t)
+
+	for _, sent := range []string{"", `bad"id`} {
+		resp, _ := get(t, app, "/echo", sent)
+
+		id := resp.Header.Get(requestid.Header)
+		assert.True(t, requestid.Valid(id), "sent %q", sent)
+		assert.NotEqual(t, sent, id)
+	}
+}
+
+func TestProblemCarriesRequestID(t *testing.T) {
+	t.Parallel()
+
+	resp, body := get(t, newRequestIDApp(t), "/missing", "req-404")
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "req-404", body["request_id"])
+	assert.Equal(t, "user not found", body["detail"])
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"bufio"
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+)
+
+var _ ActivityUseCase = (*usecase.ActivityUseCase)(nil)
+
+const (
+	activityTracerName = "activity handler"
+
+	// sseRetry — пауза переподключения EventSource, мс.
+	sseRetry = 3000
+)
+
+type ActivityHandler struct {
+	activityUC ActivityUseCase
+	log        logger.Logger
+
+	heartbeat    time.Duration
+	writeTimeout time.Duration
+}
+
+// NewActivityHandler: heartbeat — период комментариев-пингов в простое (не
+// даёт прокси закрыть соединение и выявляет ушедших клиентов), writeTimeout —
+// дедлайн записи одной порции.
+func NewActivityHandler(uc ActivityUseCase, heartbeat, writeTimeout time.Duration, log logger.Logger) *ActivityHandler {
+	return &ActivityHandler{activityUC: uc, log: log, heartbeat: heartbeat, writeTimeout: writeTimeout}
+}
+
+// StreamAccountEvents открывает поток до отправки заголовков: нет счёта или
+// исчерпан лимит потоков — обычный JSON-ответ с кодом ошибки.
+func (ah *ActivityHandler) StreamAccountEvents(ctx context.Context, req *StreamAccountEventsRequest) (*huma.StreamResponse, error) {
+	ctx, span := otel.Tracer(activityTracerName).Start(ctx, "StreamAccountEvents")
+	defer span.End()
+
+	stream, err := ah.activityUC.OpenAccountStream(ctx, usecase.StreamAccountEventsCommand{
+		AccountID:   req.ID,
+		LastEventID: req.LastEventID,
+	})
+	if err != nil {
+		return nil, mapError(ctx, ah.log, err)
+	}
+
+	// fasthttp пишет тело уже после возврата из обработчика, а middleware
+	// таймаута к тому моменту отменит ctx запроса: поток живёт до ухода
+	// клиента или остановки ленты.
+	streamCtx := context.WithoutCancel(ctx)
+
+	return &huma.StreamResponse{
+		Body: func(hctx huma.Context) {
+			hctx.SetHeader("Content-Type", "text/event-stream")
+			hctx.SetHeader("Cache-Control", "no-cache")
+			hctx.SetHeader("X-Accel-Buffering", "no")
+
+			ah.writeStream(hctx, func(w io.Writer, flush func() error) {
+				defer stream.Close()
+				ah.pump(streamCtx, req.ID, stream, w, flush)
+			})
+		},
+	}, nil
+}
+
+// pump пишет события и heartbeat-комментарии, пока клиент читает. Остановка
+// ленты (graceful shutdown) завершает поток штатно: EventSource
+// переподключится к другому инстансу с Last-Event-ID.
+func (ah *ActivityHandler) pump(ctx context.Context, accountID int64, stream *usecase.AccountStream, w io.Writer, flush func() error) {
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry); err != nil || flush() != nil {
+		return
+	}
+
+	for {
+		nextCtx, cancel := context.WithTimeout(ctx, ah.heartbeat)
+		event, err := stream.Next(nextCtx)
+		cancel()
+
+		switch {
+		case err == nil:
+			err = writeEvent(w, event)
+		case errors.Is(err, context.DeadlineExceeded):
+			_, err = io.WriteString(w, ": heartbeat\n\n")
+		case errors.Is(err, entity.ErrStreamClosed):
+			return
+		default:
+			ah.log.Error(ctx, "account events stream failed", "account_id", accountID, "error", err.Error())
+			return
+		}
+
+		// Ошибка записи — клиент ушёл.
+		if err != nil || flush() != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event entity.AccountEvent) error {
+	data, err := json.Marshal(ToAccountEventDTO(event))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+
+	return err
+}
+
+// writeStream запускает запись поверх адаптера: fasthttp (fiber) требует
+// отдельный stream writer, net/http (humatest) пишет прямо в ResponseWriter.
+func (ah *ActivityHandler) writeStream(hctx huma.Context, write func(w io.Writer, flush func() error)) {
+	bw := hctx.BodyWriter()
+
+	if fctx, ok := bw.(*fasthttp.RequestCtx); ok {
+		conn := fctx.Conn()
+		fctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			write(w, func() error {
+				// fasthttp ставит дедлайн записи один раз на ответ — продлеваем
+				// его на каждую порцию, иначе поток оборвётся через HTTP_WRITE_TIMEOUT.
+				if ah.writeTimeout > 0 {
+					_ = conn.SetWriteDeadline(time.Now().Add(ah.writeTimeout))
+				}
+				return w.Flush()
+			})
+		})
+		return
+	}
+
+	flusher, _ := bw.(http.Flusher)
+	write(bw, func() error {
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+}
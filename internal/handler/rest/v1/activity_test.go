@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/logger/loggertest"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closedFeed отдаёт подписку, лента которой уже остановлена: поток выдаёт
+// догрузку из БД и штатно завершается, так что humatest получает ответ целиком.
+type closedFeed struct{ err error }
+
+func (f closedFeed) Subscribe(int64) (usecase.Subscription, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	done := make(chan struct{})
+	close(done)
+	return closedSubscription{done: done}, nil
+}
+
+type closedSubscription struct{ done chan struct{} }
+
+func (closedSubscription) Events() <-chan entity.AccountEvent { return nil }
+func (closedSubscription) Resync() <-chan struct{}            { return nil }
+func (s closedSubscription) Done() <-chan struct{}            { return s.done }
+func (closedSubscription) Close()                             {}
+
+type stubActivityRepository struct{ events []entity.AccountEvent }
+
+func (r stubActivityRepository) GetAccountEventsAfter(_ context.Context, _, afterID int64, _ int) ([]entity.AccountEvent, error) {
+	var out []entity.AccountEvent
+	for _, event := range r.events {
+		if event.ID > afterID {
+			out = append(out, event)
+		}
+	}
+	return out, nil
+}
+
+func (r stubActivityRepository) GetAccountLastEventID(context.Context, int64) (int64, error) {
+	var last int64
+	for _, event := range r.events {
+		last = max(last, event.ID)
+	}
+	return last, nil
+}
+
+func newActivityTestAPI(t *testing.T, feed usecase.ActivityFeed) humatest.TestAPI {
+	t.Helper()
+
+	_, api := humatest.New(t, SetupHumaConfig())
+
+	repo := stubActivityRepository{events: []entity.AccountEvent{
+		{ID: 5, Type: entity.EventTransferCredited, AccountID: 1, CounterpartyID: 2, Amount: 10, CreatedAt: time.Unix(0, 0)},
+		{ID: 6, Type: entity.EventTransferDebited, AccountID: 1, CounterpartyID: 2, Amount: 300, CreatedAt: time.Unix(0, 0)},
+	}}
+	uc := usecase.NewActivityUseCase(&mockUserRepository{users: mockUsers}, repo, feed)
+	SetupActivityRoutes(api, NewActivityHandler(uc, time.Minute, time.Second, &loggertest.Fake{}))
+
+	return api
+}
+
+func TestStreamAccountEventsResumesFromLastEventID(t *testing.T) {
+	api := newActivityTestAPI(t, closedFeed{})
+
+	resp := api.Get("/user/1/events", "Last-Event-ID: 5")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+
+	body := resp.Body.String()
+	assert.Contains(t, body, "retry: 3000\n\n")
+	assert.Contains(t, body, "id: 6\nevent: transfer.debited\ndata: {\"id\":6,")
+	assert.NotContains(t, body, "id: 5\n", "событие из Last-Event-ID уже получено клиентом")
+}
+
+func TestStreamAccountEventsErrors(t *testing.T) {
+	resp := newActivityTestAPI(t, closedFeed{}).Get("/user/42/events")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = newActivityTestAPI(t, closedFeed{err: entity.ErrTooManyStreams}).Get("/user/1/events")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"clean-arch-template/internal/entity"
+	"time"
 )
 
 func toUserDTO(user entity.User) UserDTO {
@@ -30,3 +31,62 @@ func ToTransferEntity(dto TransferDTO) entity.Transfer {
 		Amount:        dto.Amount,
 	}
 }
+
+func toWebhookDTO(webhook entity.WebhookSubscription) WebhookDTO {
+	eventTypes := make([]string, 0, len(webhook.EventTypes))
+	for _, eventType := range webhook.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	return WebhookDTO{
+		ID:         webhook.ID,
+		AccountID:  webhook.AccountID,
+		URL:        webhook.URL,
+		EventTypes: eventTypes,
+		Enabled:    webhook.Enabled,
+		CreatedAt:  webhook.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// ToCreatedWebhookOutputFromEntity — единственный ответ, в котором есть секрет.
+func ToCreatedWebhookOutputFromEntity(webhook *entity.WebhookSubscription) *WebhookResponse {
+	dto := toWebhookDTO(*webhook)
+	dto.Secret = webhook.Secret
+
+	return &WebhookResponse{Body: dto}
+}
+
+func ToWebhookOutputFromEntity(webhook *entity.WebhookSubscription) *WebhookResponse {
+	return &WebhookResponse{Body: toWebhookDTO(*webhook)}
+}
+
+func ToWebhookListOutputFromEntity(webhooks []entity.WebhookSubscription) *ListWebhooksResponse {
+	resp := &ListWebhooksResponse{}
+	resp.Body.Webhooks = make([]WebhookDTO, 0, len(webhooks))
+
+	for _, webhook := range webhooks {
+		resp.Body.Webhooks = append(resp.Body.Webhooks, toWebhookDTO(webhook))
+	}
+
+	return resp
+}
+
+func toEventTypes(raw []string) []entity.EventType {
+	eventTypes := make([]entity.EventType, 0, len(raw))
+	for _, eventType := range raw {
+		eventTypes = append(eventTypes, entity.EventType(eventType))
+	}
+
+	return eventTypes
+}
+
+func ToAccountEventDTO(event entity.AccountEvent) AccountEventDTO {
+	return AccountEventDTO{
+		ID:             event.ID,
+		Type:           string(event.Type),
+		AccountID:      event.AccountID,
+		CounterpartyID: event.CounterpartyID,
+		Amount:         event.Amount,
+		CreatedAt:      event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
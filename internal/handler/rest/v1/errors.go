@@ -2,6 +2,7 @@ package v1
 
 import (
 	"clean-arch-template/internal/entity"
+	"clean-arch-template/pkg/logger"
 	"context"
 	"errors"
 
@@ -10,21 +11,36 @@ import (
 
 // mapError маппит доменные ошибки в HTTP-ошибки. Неизвестные ошибки логируются
 // (с trace_id из ctx) и уходят клиенту как generic 500.
-func (uh *UserHandler) mapError(ctx context.Context, err error) error {
+// Общая для всех хендлеров пакета: одна доменная ошибка — один HTTP-код.
+func mapError(ctx context.Context, log logger.Logger, err error) error {
 	switch {
 	case errors.Is(err, entity.ErrUserNotFound),
+		errors.Is(err, entity.ErrWebhookNotFound),
 		errors.Is(err, entity.ErrSourceAccountNotFound),
 		errors.Is(err, entity.ErrDestAccountNotFound):
 		return huma.Error404NotFound(err.Error())
 	case errors.Is(err, entity.ErrInvalidUserName),
 		errors.Is(err, entity.ErrInvalidPagination),
 		errors.Is(err, entity.ErrNegativeAmount),
-		errors.Is(err, entity.ErrSameAccount):
+		errors.Is(err, entity.ErrSameAccount),
+		errors.Is(err, entity.ErrInvalidWebhookURL),
+		errors.Is(err, entity.ErrInvalidEventTypes),
+		errors.Is(err, entity.ErrInvalidWebhookSecret):
 		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, entity.ErrTooManyStreams),
+		errors.Is(err, entity.ErrStreamClosed):
+		return huma.Error503ServiceUnavailable(err.Error())
 	case errors.Is(err, entity.ErrInsufficientFunds):
 		return huma.Error409Conflict(err.Error())
+	// Текст — только доменной ошибки: сообщение Postgres клиенту не уходит.
+	case errors.Is(err, entity.ErrResourceBusy):
+		log.Warn(ctx, "request failed", "error", err.Error())
+		return huma.Error503ServiceUnavailable(entity.ErrResourceBusy.Error())
+	case errors.Is(err, entity.ErrQueryTimeout):
+		log.Warn(ctx, "request failed", "error", err.Error())
+		return huma.Error504GatewayTimeout(entity.ErrQueryTimeout.Error())
 	default:
-		uh.log.Error(ctx, "request failed", "error", err.Error())
+		log.Error(ctx, "request failed", "error", err.Error())
 		return huma.Error500InternalServerError("internal server error")
 	}
 }
@@ -16,3 +16,15 @@ type UserUseCase interface {
 	DeleteUser(ctx context.Context, cmd usecase.DeleteUserByIDCommand) error
 	TransferMoney(ctx context.Context, cmd usecase.TransferMoneyCommand) error
 }
+
+type WebhookUseCase interface {
+	CreateWebhook(ctx context.Context, cmd usecase.CreateWebhookCommand) (*entity.WebhookSubscription, error)
+	FindWebhookByID(ctx context.Context, cmd usecase.FindWebhookByIDCommand) (*entity.WebhookSubscription, error)
+	FindAccountWebhooks(ctx context.Context, cmd usecase.FindAccountWebhooksCommand) ([]entity.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, cmd usecase.UpdateWebhookCommand) (*entity.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, cmd usecase.DeleteWebhookByIDCommand) error
+}
+
+type ActivityUseCase interface {
+	OpenAccountStream(ctx context.Context, cmd usecase.StreamAccountEventsCommand) (*usecase.AccountStream, error)
+}
@@ -129,3 +129,140 @@ func (mr *MockUserUseCaseMockRecorder) UpdateUser(ctx, cmd any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserUseCase)(nil).UpdateUser), ctx, cmd)
 }
+
+// MockWebhookUseCase is a mock of WebhookUseCase interface.
+type MockWebhookUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookUseCaseMockRecorder is the mock recorder for MockWebhookUseCase.
+type MockWebhookUseCaseMockRecorder struct {
+	mock *MockWebhookUseCase
+}
+
+// NewMockWebhookUseCase creates a new mock instance.
+func NewMockWebhookUseCase(ctrl *gomock.Controller) *MockWebhookUseCase {
+	mock := &MockWebhookUseCase{ctrl: ctrl}
+	mock.recorder = &MockWebhookUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookUseCase) EXPECT() *MockWebhookUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookUseCase) CreateWebhook(ctx context.Context, cmd usecase.CreateWebhookCommand) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, cmd)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) CreateWebhook(ctx, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).CreateWebhook), ctx, cmd)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookUseCase) DeleteWebhook(ctx context.Context, cmd usecase.DeleteWebhookByIDCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, cmd)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) DeleteWebhook(ctx, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).DeleteWebhook), ctx, cmd)
+}
+
+// FindAccountWebhooks mocks base method.
+func (m *MockWebhookUseCase) FindAccountWebhooks(ctx context.Context, cmd usecase.FindAccountWebhooksCommand) ([]entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccountWebhooks", ctx, cmd)
+	ret0, _ := ret[0].([]entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAccountWebhooks indicates an expected call of FindAccountWebhooks.
+func (mr *MockWebhookUseCaseMockRecorder) FindAccountWebhooks(ctx, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccountWebhooks", reflect.TypeOf((*MockWebhookUseCase)(nil).FindAccountWebhooks), ctx, cmd)
+}
+
+// FindWebhookByID mocks base method.
+func (m *MockWebhookUseCase) FindWebhookByID(ctx context.Context, cmd usecase.FindWebhookByIDCommand) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWebhookByID", ctx, cmd)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWebhookByID indicates an expected call of FindWebhookByID.
+func (mr *MockWebhookUseCaseMockRecorder) FindWebhookByID(ctx, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWebhookByID", reflect.TypeOf((*MockWebhookUseCase)(nil).FindWebhookByID), ctx, cmd)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookUseCase) UpdateWebhook(ctx context.Context, cmd usecase.UpdateWebhookCommand) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, cmd)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) UpdateWebhook(ctx, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).UpdateWebhook), ctx, cmd)
+}
+
+// MockActivityUseCase is a mock of ActivityUseCase interface.
+type MockActivityUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockActivityUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockActivityUseCaseMockRecorder is the mock recorder for MockActivityUseCase.
+type MockActivityUseCaseMockRecorder struct {
+	mock *MockActivityUseCase
+}
+
+// NewMockActivityUseCase creates a new mock instance.
+func NewMockActivityUseCase(ctrl *gomock.Controller) *MockActivityUseCase {
+	mock := &MockActivityUseCase{ctrl: ctrl}
+	mock.recorder = &MockActivityUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActivityUseCase) EXPECT() *MockActivityUseCaseMockRecorder {
+	return m.recorder
+}
+
+// OpenAccountStream mocks base method.
+func (m *MockActivityUseCase) OpenAccountStream(ctx context.Context, cmd usecase.StreamAccountEventsCommand) (*usecase.AccountStream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAccountStream", ctx, cmd)
+	ret0, _ := ret[0].(*usecase.AccountStream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenAccountStream indicates an expected call of OpenAccountStream.
+func (mr *MockActivityUseCaseMockRecorder) OpenAccountStream(ctx, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAccountStream", reflect.TypeOf((*MockActivityUseCase)(nil).OpenAccountStream), ctx, cmd)
+}
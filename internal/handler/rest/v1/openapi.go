@@ -16,6 +16,14 @@ type Handler interface {
 	TransferMoney(ctx context.Context, req *TransferMoneyRequest) (*struct{}, error)
 }
 
+type WebhookEndpoints interface {
+	CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*WebhookResponse, error)
+	FindWebhookByID(ctx context.Context, req *FindWebhookRequest) (*WebhookResponse, error)
+	ListAccountWebhooks(ctx context.Context, req *ListAccountWebhooksRequest) (*ListWebhooksResponse, error)
+	UpdateWebhook(ctx context.Context, req *UpdateWebhookRequest) (*WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, req *FindWebhookRequest) (*struct{}, error)
+}
+
 func SetupHumaConfig() huma.Config {
 	openapiConfig := huma.DefaultConfig("Clean Architecture Template", "1.0.0")
 	openapiConfig.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
@@ -32,6 +40,12 @@ func SetupHumaConfig() huma.Config {
 	return openapiConfig
 }
 
+// withDBErrors добавляет коды таймаутов БД: 503 — не дождались блокировки,
+// 504 — истёк statement_timeout (см. mapError).
+func withDBErrors(codes ...int) []int {
+	return append(codes, http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+}
+
 // SetupRoutes регистрирует операции. Схемы запросов/ответов и параметры Huma
 // генерирует из Go-типов (см. schemas.go) — руками их не описываем, чтобы
 // контракт не расходился с кодом. Errors добавляет коды ошибок в OpenAPI.
@@ -43,7 +57,7 @@ func SetupRoutes(api huma.API, userHandler Handler) {
 		Summary:     "list all users",
 		Description: "Get a page of users. Pages are 1-based.",
 		Tags:        []string{"Users"},
-		Errors:      []int{http.StatusBadRequest, http.StatusInternalServerError},
+		Errors:      withDBErrors(http.StatusBadRequest, http.StatusInternalServerError),
 	}, userHandler.ListUsers)
 
 	huma.Register(api, huma.Operation{
@@ -53,7 +67,7 @@ func SetupRoutes(api huma.API, userHandler Handler) {
 		Summary:     "user by id",
 		Description: "Get a user by id.",
 		Tags:        []string{"Users"},
-		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		Errors:      withDBErrors(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
 	}, userHandler.FindUserByID)
 
 	huma.Register(api, huma.Operation{
@@ -64,7 +78,7 @@ func SetupRoutes(api huma.API, userHandler Handler) {
 		Description:   "Create a new user record.",
 		Tags:          []string{"Users"},
 		DefaultStatus: http.StatusCreated,
-		Errors:        []int{http.StatusBadRequest, http.StatusInternalServerError},
+		Errors:        withDBErrors(http.StatusBadRequest, http.StatusInternalServerError),
 	}, userHandler.CreateUser)
 
 	huma.Register(api, huma.Operation{
@@ -74,7 +88,7 @@ func SetupRoutes(api huma.API, userHandler Handler) {
 		Summary:     "update user",
 		Description: "Update an existing user by ID. The ID from the path is authoritative; any ID in the body is ignored.",
 		Tags:        []string{"Users"},
-		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		Errors:      withDBErrors(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
 	}, userHandler.UpdateUser)
 
 	huma.Register(api, huma.Operation{
@@ -85,7 +99,7 @@ func SetupRoutes(api huma.API, userHandler Handler) {
 		Description:   "Delete a user by ID.",
 		Tags:          []string{"Users"},
 		DefaultStatus: http.StatusNoContent,
-		Errors:        []int{http.StatusNotFound, http.StatusInternalServerError},
+		Errors:        withDBErrors(http.StatusNotFound, http.StatusInternalServerError),
 	}, userHandler.DeleteUser)
 
 	huma.Register(api, huma.Operation{
@@ -96,11 +110,93 @@ func SetupRoutes(api huma.API, userHandler Handler) {
 		Description:   "Transfer money between two accounts.",
 		Tags:          []string{"Users"},
 		DefaultStatus: http.StatusNoContent,
-		Errors: []int{
+		Errors: withDBErrors(
 			http.StatusBadRequest,
 			http.StatusNotFound,
 			http.StatusConflict,
 			http.StatusInternalServerError,
-		},
+		),
 	}, userHandler.TransferMoney)
 }
+
+// SetupWebhookRoutes регистрирует CRUD подписок партнёров на события счёта.
+func SetupWebhookRoutes(api huma.API, webhookHandler WebhookEndpoints) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-webhook",
+		Method:        http.MethodPost,
+		Path:          "/user/{id}/webhooks",
+		Summary:       "create webhook subscription",
+		Description:   "Subscribe a URL to events of the account. The signing secret is returned only in this response.",
+		Tags:          []string{"Webhooks"},
+		DefaultStatus: http.StatusCreated,
+		Errors:        withDBErrors(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+	}, webhookHandler.CreateWebhook)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-account-webhooks",
+		Method:      http.MethodGet,
+		Path:        "/user/{id}/webhooks",
+		Summary:     "account webhook subscriptions",
+		Description: "List webhook subscriptions of the account.",
+		Tags:        []string{"Webhooks"},
+		Errors:      withDBErrors(http.StatusInternalServerError),
+	}, webhookHandler.ListAccountWebhooks)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-webhook-by-id",
+		Method:      http.MethodGet,
+		Path:        "/webhooks/{id}",
+		Summary:     "webhook subscription by id",
+		Description: "Get a webhook subscription by id.",
+		Tags:        []string{"Webhooks"},
+		Errors:      withDBErrors(http.StatusNotFound, http.StatusInternalServerError),
+	}, webhookHandler.FindWebhookByID)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-webhook",
+		Method:      http.MethodPut,
+		Path:        "/webhooks/{id}",
+		Summary:     "update webhook subscription",
+		Description: "Replace URL and event types, enable or disable the subscription, optionally rotate the secret.",
+		Tags:        []string{"Webhooks"},
+		Errors:      withDBErrors(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+	}, webhookHandler.UpdateWebhook)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-webhook",
+		Method:        http.MethodDelete,
+		Path:          "/webhooks/{id}",
+		Summary:       "delete webhook subscription",
+		Description:   "Delete a webhook subscription and its pending deliveries.",
+		Tags:          []string{"Webhooks"},
+		DefaultStatus: http.StatusNoContent,
+		Errors:        withDBErrors(http.StatusNotFound, http.StatusInternalServerError),
+	}, webhookHandler.DeleteWebhook)
+}
+
+type ActivityEndpoints interface {
+	StreamAccountEvents(ctx context.Context, req *StreamAccountEventsRequest) (*huma.StreamResponse, error)
+}
+
+// SetupActivityRoutes регистрирует SSE-поток переводов счёта.
+func SetupActivityRoutes(api huma.API, activityHandler ActivityEndpoints) {
+	huma.Register(api, huma.Operation{
+		OperationID: "stream-account-events",
+		Method:      http.MethodGet,
+		Path:        "/user/{id}/events",
+		Summary:     "account activity stream",
+		Description: "Server-Sent Events stream of transfers affecting the account " +
+			"(`transfer.debited`, `transfer.credited`), pushed as they commit. " +
+			"Idle periods carry `: heartbeat` comments. On reconnect send `Last-Event-ID` to receive missed events first.",
+		Tags: []string{"Users"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Event stream; each event's data is an AccountEventDTO",
+				Content: map[string]*huma.MediaType{
+					"text/event-stream": {Schema: &huma.Schema{Type: huma.TypeString}},
+				},
+			},
+		},
+		Errors: []int{http.StatusNotFound, http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusInternalServerError},
+	}, activityHandler.StreamAccountEvents)
+}
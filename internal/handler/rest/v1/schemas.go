@@ -52,3 +52,73 @@ type (
 		Body TransferDTO
 	}
 )
+
+type (
+	WebhookDTO struct {
+		ID         int64    `json:"id"          doc:"Subscription ID"  example:"1"`
+		AccountID  int64    `json:"account_id"  doc:"Account ID"       example:"1"`
+		URL        string   `json:"url"         doc:"Delivery URL"     example:"https://partner.example.com/hooks"`
+		EventTypes []string `json:"event_types" doc:"Subscribed event types" example:"[\"transfer.debited\",\"transfer.credited\"]"`
+		Enabled    bool     `json:"enabled"     doc:"false after too many consecutive failed deliveries" example:"true"`
+		CreatedAt  string   `json:"created_at"  doc:"Creation time, RFC 3339" example:"2026-10-18T12:00:00Z"`
+		// Secret отдаётся только в ответе на создание.
+		Secret string `json:"secret,omitempty" doc:"HMAC-SHA256 signing secret, returned only on creation"`
+	}
+
+	CreateWebhookBody struct {
+		URL        string   `json:"url"              doc:"Delivery URL, http(s)" example:"https://partner.example.com/hooks" format:"uri" maxLength:"2048"`
+		EventTypes []string `json:"event_types"      doc:"Event types to deliver" example:"[\"transfer.debited\"]" minItems:"1" enum:"transfer.debited,transfer.credited"`
+		Secret     string   `json:"secret,omitempty" doc:"Signing secret; generated when omitted" minLength:"16" maxLength:"256"`
+	}
+
+	UpdateWebhookBody struct {
+		URL        string   `json:"url"              doc:"Delivery URL, http(s)" example:"https://partner.example.com/hooks" format:"uri" maxLength:"2048"`
+		EventTypes []string `json:"event_types"      doc:"Event types to deliver" example:"[\"transfer.debited\"]" minItems:"1" enum:"transfer.debited,transfer.credited"`
+		Enabled    bool     `json:"enabled"          doc:"Re-enabling resets the failure counter" example:"true"`
+		Secret     string   `json:"secret,omitempty" doc:"New signing secret; the current one is kept when omitted" minLength:"16" maxLength:"256"`
+	}
+
+	CreateWebhookRequest struct {
+		AccountID int64 `path:"id" minimum:"1" example:"1" doc:"account (user) id"`
+		Body      CreateWebhookBody
+	}
+
+	UpdateWebhookRequest struct {
+		ID   int64 `path:"id" minimum:"1" example:"1" doc:"subscription id"`
+		Body UpdateWebhookBody
+	}
+
+	FindWebhookRequest struct {
+		ID int64 `path:"id" minimum:"1" example:"1" doc:"subscription id"`
+	}
+
+	ListAccountWebhooksRequest struct {
+		AccountID int64 `path:"id" minimum:"1" example:"1" doc:"account (user) id"`
+	}
+
+	WebhookResponse struct {
+		Body WebhookDTO
+	}
+
+	ListWebhooksResponse struct {
+		Body struct {
+			Webhooks []WebhookDTO `json:"webhooks"`
+		}
+	}
+)
+
+type (
+	AccountEventDTO struct {
+		ID             int64  `json:"id"              doc:"Transaction ID, also the SSE event id" example:"42"`
+		Type           string `json:"type"            doc:"transfer.debited or transfer.credited" example:"transfer.debited"`
+		AccountID      int64  `json:"account_id"      doc:"Account ID"      example:"1"`
+		CounterpartyID int64  `json:"counterparty_id" doc:"Other account"   example:"2"`
+		Amount         int64  `json:"amount"          doc:"Amount in minimal currency units, 100 cents = 1$" example:"100"`
+		CreatedAt      string `json:"created_at"      doc:"Commit time, RFC 3339" example:"2026-10-18T12:00:00Z"`
+	}
+
+	StreamAccountEventsRequest struct {
+		ID          int64 `path:"id"              minimum:"1" example:"1" doc:"account (user) id"`
+		LastEventID int64 `header:"Last-Event-ID" minimum:"0" doc:"Resume after this event id; sent by EventSource on reconnect"`
+	}
+)
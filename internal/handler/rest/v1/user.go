@@ -34,7 +34,7 @@ func (uh *UserHandler) ListUsers(ctx context.Context, req *ListUserRequest) (*Li
 
 	users, err := uh.userUC.FindAllUsers(ctx, cmd)
 	if err != nil {
-		return nil, uh.mapError(ctx, err)
+		return nil, mapError(ctx, uh.log, err)
 	}
 
 	return ToUserListOutputFromEntity(users), nil
@@ -48,7 +48,7 @@ func (uh *UserHandler) FindUserByID(ctx context.Context, req *FindUserRequest) (
 
 	user, err := uh.userUC.FindUserByID(ctx, cmd)
 	if err != nil {
-		return nil, uh.mapError(ctx, err)
+		return nil, mapError(ctx, uh.log, err)
 	}
 
 	return ToUserOutputFromEntity(user), nil
@@ -63,7 +63,7 @@ func (uh *UserHandler) CreateUser(ctx context.Context, req *CreateUserRequest) (
 
 	user, err := uh.userUC.CreateUser(ctx, cmd)
 	if err != nil {
-		return nil, uh.mapError(ctx, err)
+		return nil, mapError(ctx, uh.log, err)
 	}
 
 	return ToUserOutputFromEntity(user), nil
@@ -81,7 +81,7 @@ func (uh *UserHandler) UpdateUser(ctx context.Context, req *UpdateUserRequest) (
 
 	user, err := uh.userUC.UpdateUser(ctx, cmd)
 	if err != nil {
-		return nil, uh.mapError(ctx, err)
+		return nil, mapError(ctx, uh.log, err)
 	}
 
 	return ToUserOutputFromEntity(user), nil
@@ -94,7 +94,7 @@ func (uh *UserHandler) DeleteUser(ctx context.Context, req *FindUserRequest) (*s
 	cmd := usecase.DeleteUserByIDCommand{ID: req.ID}
 
 	if err := uh.userUC.DeleteUser(ctx, cmd); err != nil {
-		return nil, uh.mapError(ctx, err)
+		return nil, mapError(ctx, uh.log, err)
 	}
 
 	return &struct{}{}, nil
@@ -107,7 +107,7 @@ func (uh *UserHandler) TransferMoney(ctx context.Context, req *TransferMoneyRequ
 	cmd := usecase.TransferMoneyCommand{Transfer: ToTransferEntity(req.Body)}
 
 	if err := uh.userUC.TransferMoney(ctx, cmd); err != nil {
-		return nil, uh.mapError(ctx, err)
+		return nil, mapError(ctx, uh.log, err)
 	}
 
 	return &struct{}{}, nil
@@ -4,6 +4,7 @@ import (
 	"clean-arch-template/internal/entity"
 	"clean-arch-template/internal/usecase"
 	"clean-arch-template/pkg/logger/loggertest"
+	"clean-arch-template/pkg/metrics"
 	"context"
 	"encoding/json"
 	"errors"
@@ -35,7 +36,7 @@ func newTestAPI(t *testing.T) (humatest.TestAPI, *loggertest.Fake) {
 
 	fakeLog := &loggertest.Fake{}
 	mockRepo := &mockUserRepository{users: users}
-	userUC := usecase.NewUserUseCase(mockRepo)
+	userUC := usecase.NewUserUseCase(mockRepo, metrics.Nop())
 	SetupRoutes(api, NewUserHandler(userUC, fakeLog))
 
 	return api, fakeLog
@@ -290,6 +291,27 @@ func TestInternalErrorIsLoggedAndMasked(t *testing.T) {
 	}
 }
 
+func TestDatabaseTimeoutsMapTo503And504(t *testing.T) {
+	api, fakeLog := newTestAPI(t)
+
+	resp := api.Get("/users/1/14") // sentinel-лимит: statement_timeout
+	if resp.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected 504, got %d", resp.Code)
+	}
+	if strings.Contains(resp.Body.String(), "canceling statement") {
+		t.Fatalf("Postgres error text must be masked, got: %s", resp.Body.String())
+	}
+
+	resp = api.Get("/users/1/15") // sentinel-лимит: lock_timeout
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503, got %d", resp.Code)
+	}
+
+	if len(fakeLog.Entries) != 2 || fakeLog.Entries[0].Level != "WARN" {
+		t.Fatalf("Expected two WARN log entries, got %+v", fakeLog.Entries)
+	}
+}
+
 // mockUserRepository implements usecase.UserRepository interface for testing.
 // Каждый существующий аккаунт считается имеющим баланс mockBalance:
 // большие суммы дают entity.ErrInsufficientFunds.
@@ -304,8 +326,13 @@ func (m *mockUserRepository) GetAllUsersWithOrders(_ context.Context, _, _ int)
 }
 
 func (m *mockUserRepository) GetAllUsers(_ context.Context, _, limit int) ([]entity.User, error) {
-	if limit == 13 {
+	switch limit {
+	case 13:
 		return nil, errors.New("boom")
+	case 14:
+		return nil, errors.Join(entity.ErrQueryTimeout, errors.New("canceling statement due to statement timeout"))
+	case 15:
+		return nil, errors.Join(entity.ErrResourceBusy, errors.New("canceling statement due to lock timeout"))
 	}
 	return m.users, nil
 }
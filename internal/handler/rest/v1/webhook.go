@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/logger"
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+var _ WebhookUseCase = (*usecase.WebhookUseCase)(nil)
+
+const webhookTracerName = "webhook handler"
+
+type WebhookHandler struct {
+	webhookUC WebhookUseCase
+	log       logger.Logger
+}
+
+func NewWebhookHandler(uc WebhookUseCase, log logger.Logger) *WebhookHandler {
+	return &WebhookHandler{webhookUC: uc, log: log}
+}
+
+func (wh *WebhookHandler) CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*WebhookResponse, error) {
+	ctx, span := otel.Tracer(webhookTracerName).Start(ctx, "CreateWebhook")
+	defer span.End()
+
+	webhook, err := wh.webhookUC.CreateWebhook(ctx, usecase.CreateWebhookCommand{
+		AccountID:  req.AccountID,
+		URL:        req.Body.URL,
+		EventTypes: toEventTypes(req.Body.EventTypes),
+		Secret:     req.Body.Secret,
+	})
+	if err != nil {
+		return nil, mapError(ctx, wh.log, err)
+	}
+
+	return ToCreatedWebhookOutputFromEntity(webhook), nil
+}
+
+func (wh *WebhookHandler) FindWebhookByID(ctx context.Context, req *FindWebhookRequest) (*WebhookResponse, error) {
+	ctx, span := otel.Tracer(webhookTracerName).Start(ctx, "FindWebhookByID")
+	defer span.End()
+
+	webhook, err := wh.webhookUC.FindWebhookByID(ctx, usecase.FindWebhookByIDCommand{ID: req.ID})
+	if err != nil {
+		return nil, mapError(ctx, wh.log, err)
+	}
+
+	return ToWebhookOutputFromEntity(webhook), nil
+}
+
+func (wh *WebhookHandler) ListAccountWebhooks(ctx context.Context, req *ListAccountWebhooksRequest) (*ListWebhooksResponse, error) {
+	ctx, span := otel.Tracer(webhookTracerName).Start(ctx, "ListAccountWebhooks")
+	defer span.End()
+
+	webhooks, err := wh.webhookUC.FindAccountWebhooks(ctx, usecase.FindAccountWebhooksCommand{AccountID: req.AccountID})
+	if err != nil {
+		return nil, mapError(ctx, wh.log, err)
+	}
+
+	return ToWebhookListOutputFromEntity(webhooks), nil
+}
+
+func (wh *WebhookHandler) UpdateWebhook(ctx context.Context, req *UpdateWebhookRequest) (*WebhookResponse, error) {
+	ctx, span := otel.Tracer(webhookTracerName).Start(ctx, "UpdateWebhook")
+	defer span.End()
+
+	webhook, err := wh.webhookUC.UpdateWebhook(ctx, usecase.UpdateWebhookCommand{
+		ID:         req.ID,
+		URL:        req.Body.URL,
+		EventTypes: toEventTypes(req.Body.EventTypes),
+		Enabled:    req.Body.Enabled,
+		Secret:     req.Body.Secret,
+	})
+	if err != nil {
+		return nil, mapError(ctx, wh.log, err)
+	}
+
+	return ToWebhookOutputFromEntity(webhook), nil
+}
+
+func (wh *WebhookHandler) DeleteWebhook(ctx context.Context, req *FindWebhookRequest) (*struct{}, error) {
+	ctx, span := otel.Tracer(webhookTracerName).Start(ctx, "DeleteWebhook")
+	defer span.End()
+
+	if err := wh.webhookUC.DeleteWebhook(ctx, usecase.DeleteWebhookByIDCommand{ID: req.ID}); err != nil {
+		return nil, mapError(ctx, wh.log, err)
+	}
+
+	return &struct{}{}, nil
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/logger/loggertest"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newWebhookTestAPI(t *testing.T) (humatest.TestAPI, *MockWebhookUseCase) {
+	t.Helper()
+
+	_, api := humatest.New(t, SetupHumaConfig())
+
+	uc := NewMockWebhookUseCase(gomock.NewController(t))
+	SetupWebhookRoutes(api, NewWebhookHandler(uc, &loggertest.Fake{}))
+
+	return api, uc
+}
+
+var testWebhook = entity.WebhookSubscription{
+	ID:         5,
+	AccountID:  1,
+	URL:        "https://partner.example.com/hooks",
+	EventTypes: []entity.EventType{entity.EventTransferDebited},
+	Secret:     "0123456789abcdef",
+	Enabled:    true,
+	CreatedAt:  time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+}
+
+func TestCreateWebhookReturnsSecretOnce(t *testing.T) {
+	api, uc := newWebhookTestAPI(t)
+
+	uc.EXPECT().CreateWebhook(gomock.Any(), usecase.CreateWebhookCommand{
+		AccountID:  1,
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []entity.EventType{entity.EventTransferDebited},
+	}).Return(&testWebhook, nil)
+	uc.EXPECT().FindWebhookByID(gomock.Any(), usecase.FindWebhookByIDCommand{ID: 5}).Return(&testWebhook, nil)
+
+	resp := api.Post("/user/1/webhooks", map[string]any{
+		"url":         "https://partner.example.com/hooks",
+		"event_types": []string{"transfer.debited"},
+	})
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var created WebhookDTO
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.Equal(t, "0123456789abcdef", created.Secret)
+	assert.Equal(t, "2026-10-18T12:00:00Z", created.CreatedAt)
+
+	resp = api.Get("/webhooks/5")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "0123456789abcdef", "секрет не отдаётся после создания")
+}
+
+func TestCreateWebhookRejectsUnknownEventType(t *testing.T) {
+	api, _ := newWebhookTestAPI(t)
+
+	// Схема Huma отсекает неизвестный тип до use case — мок без ожиданий.
+	resp := api.Post("/user/1/webhooks", map[string]any{
+		"url":         "https://partner.example.com/hooks",
+		"event_types": []string{"user.created"},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestWebhookErrorsMapping(t *testing.T) {
+	api, uc := newWebhookTestAPI(t)
+
+	uc.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(nil, entity.ErrInvalidWebhookURL)
+	uc.EXPECT().DeleteWebhook(gomock.Any(), usecase.DeleteWebhookByIDCommand{ID: 9}).Return(entity.ErrWebhookNotFound)
+
+	resp := api.Post("/user/1/webhooks", map[string]any{
+		"url":         "mailto:ops@example.com",
+		"event_types": []string{"transfer.credited"},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = api.Delete("/webhooks/9")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,98 @@
+package migrate
+
+import (
+	"clean-arch-template/pkg/logger"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// defaultBatchSize — строк на батч бэкфилла: каждая пачка — отдельная
+// короткая транзакция, блокировки строк не держатся на всю миграцию.
+const defaultBatchSize = 1000
+
+// Backfill — Go-миграция, переписывающая данные пачками. Выполняется без
+// общей транзакции (goose RunDB), но под session advisory lock провайдера,
+// как и SQL-миграции. Возобновляемость — на стороне Batch: он выбирает только
+// ещё не обработанные строки, поэтому после обрыва повторный Up продолжает с
+// места остановки, а goose записывает версию лишь после успешного завершения.
+type Backfill struct {
+	Version   int64
+	Name      string
+	BatchSize int
+	// Remaining — сколько строк осталось обработать (для прогресса в логе).
+	Remaining func(ctx context.Context, db *sql.DB) (int64, error)
+	// Batch обрабатывает до limit строк после курсора after (keyset по id)
+	// и возвращает последний обработанный id и число строк; 0 строк — конец.
+	Batch func(ctx context.Context, db *sql.DB, after int64, limit int) (last int64, n int64, err error)
+	// Finish — необязательный шаг после последнего батча (ограничения схемы,
+	// которые держатся только на заполненных данных).
+	Finish func(ctx context.Context, db *sql.DB) error
+	// Down — откат; nil — откат только снимает версию.
+	Down func(ctx context.Context, db *sql.DB) error
+}
+
+// Migration — регистрация в goose.Provider (goose.WithGoMigrations).
+func (b Backfill) Migration(log logger.Logger) *goose.Migration {
+	up := &goose.GoFunc{RunDB: func(ctx context.Context, db *sql.DB) error {
+		return b.run(ctx, db, log)
+	}}
+
+	var down *goose.GoFunc
+	if b.Down != nil {
+		down = &goose.GoFunc{RunDB: b.Down}
+	}
+
+	return goose.NewGoMigration(b.Version, up, down)
+}
+
+func (b Backfill) run(ctx context.Context, db *sql.DB, log logger.Logger) error {
+	batchSize := b.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	total, err := b.Remaining(ctx, db)
+	if err != nil {
+		return fmt.Errorf("backfill %s: count: %w", b.Name, err)
+	}
+	log.Info(ctx, "backfill: start", "migration", b.Name, "version", b.Version, "remaining", total)
+
+	var (
+		cursor int64
+		done   int64
+		start  = time.Now()
+	)
+	for total > 0 {
+		if err := ctx.Err(); err != nil {
+			// Обработанные батчи закоммичены: следующий запуск продолжит с них.
+			return fmt.Errorf("backfill %s: interrupted after %d rows: %w", b.Name, done, err)
+		}
+
+		last, n, err := b.Batch(ctx, db, cursor, batchSize)
+		if err != nil {
+			return fmt.Errorf("backfill %s: batch after id %d: %w", b.Name, cursor, err)
+		}
+		if n == 0 {
+			break
+		}
+		cursor = last
+		done += n
+
+		log.Info(ctx, "backfill: progress", "migration", b.Name,
+			"done", done, "total", total, "percent", min(done*100/total, 100), "cursor", cursor)
+	}
+
+	if b.Finish != nil {
+		if err := b.Finish(ctx, db); err != nil {
+			return fmt.Errorf("backfill %s: finish: %w", b.Name, err)
+		}
+	}
+
+	log.Info(ctx, "backfill: done", "migration", b.Name, "rows", done, "duration", time.Since(start).String())
+
+	return nil
+}
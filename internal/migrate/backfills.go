@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"clean-arch-template/pkg/logger"
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+// backfills — Go-миграции бинаря. Версии — в общей нумерации с SQL-файлами
+// из migrations/: goose упорядочивает их вместе.
+func backfills() []Backfill {
+	return []Backfill{backfillUsersCreatedAt}
+}
+
+func goMigrations(log logger.Logger) []*goose.Migration {
+	list := backfills()
+	out := make([]*goose.Migration, 0, len(list))
+	for _, b := range list {
+		out = append(out, b.Migration(log))
+	}
+	return out
+}
+
+// SourceName — имя миграции для вывода CLI: путь SQL-файла или "go:<name>"
+// для бэкфилла (у Go-миграций, зарегистрированных без файла, пути нет).
+func SourceName(src *goose.Source) string {
+	if src.Type != goose.TypeGo || src.Path != "" {
+		return src.Path
+	}
+	for _, b := range backfills() {
+		if b.Version == src.Version {
+			return "go:" + b.Name
+		}
+	}
+	return "go"
+}
+
+// backfillUsersCreatedAt заполняет users.created_at у старых строк, где он
+// NULL: время первого перевода пользователя, иначе updated_at, иначе now().
+// После заполнения колонка становится NOT NULL.
+var backfillUsersCreatedAt = Backfill{
+	Version: 20261018000005,
+	Name:    "users_created_at",
+	Remaining: func(ctx context.Context, db *sql.DB) (int64, error) {
+		var n int64
+		err := db.QueryRowContext(ctx, "SELECT count(*) FROM users WHERE created_at IS NULL").Scan(&n)
+		return n, err
+	},
+	Batch: func(ctx context.Context, db *sql.DB, after int64, limit int) (int64, int64, error) {
+		var last, n int64
+		err := db.QueryRowContext(ctx, `
+			WITH batch AS (
+				SELECT id FROM users
+				WHERE created_at IS NULL AND id > $1
+				ORDER BY id
+				LIMIT $2
+			), updated AS (
+				UPDATE users u
+				SET created_at = COALESCE(
+					(SELECT min(t.created_at) FROM transactions t
+					 WHERE t.from_user_id = u.id OR t.to_user_id = u.id),
+					u.updated_at,
+					now())
+				FROM batch
+				WHERE u.id = batch.id
+				RETURNING u.id
+			)
+			SELECT COALESCE(max(id), 0), count(*) FROM updated`, after, limit).Scan(&last, &n)
+		return last, n, err
+	},
+	Finish: func(ctx context.Context, db *sql.DB) error {
+		_, err := db.ExecContext(ctx, "ALTER TABLE users ALTER COLUMN created_at SET NOT NULL")
+		return err
+	},
+	Down: func(ctx context.Context, db *sql.DB) error {
+		// Заполненные значения остаются: откатывается только ограничение.
+		_, err := db.ExecContext(ctx, "ALTER TABLE users ALTER COLUMN created_at DROP NOT NULL")
+		return err
+	},
+}
@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// ErrAlreadyManaged — в goose_db_version уже есть применённые версии:
+// baseline повторно не выполняется.
+var ErrAlreadyManaged = errors.New("database is already managed by goose")
+
+// Baseline переводит БД, созданную golang-migrate, под управление goose без
+// применения миграций: создаёт goose_db_version и отмечает применёнными все
+// версии бинаря до version включительно (0 — все). Схема к этому моменту
+// должна соответствовать version — это проверяет оператор.
+//
+// Отказывает, если goose уже ведёт БД или golang-migrate оставил схему в
+// состоянии dirty. schema_migrations не трогает: её удаляют вручную.
+// Выполняется в одной транзакции под тем же advisory lock, что и Up.
+func (m *Migrator) Baseline(ctx context.Context, version int64) ([]int64, error) {
+	var versions []int64
+	for _, src := range m.provider.ListSources() {
+		if version == 0 || src.Version <= version {
+			versions = append(versions, src.Version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("migrate: baseline: no migrations up to version %d", version)
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: baseline: conn: %w", err)
+	}
+	defer conn.Close()
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("migrate: baseline: session locker: %w", err)
+	}
+	if err := locker.SessionLock(ctx, conn); err != nil {
+		return nil, fmt.Errorf("migrate: baseline: lock: %w", err)
+	}
+	defer func() {
+		// Свежий ctx: отмена ctx не должна оставить lock висеть на соединении.
+		if err := locker.SessionUnlock(context.WithoutCancel(ctx), conn); err != nil {
+			m.log.Error(ctx, "migrate: baseline: unlock", "error", err.Error())
+		}
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: baseline: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := m.checkGolangMigrate(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	store, err := database.NewStore(database.DialectPostgres, versionTable)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: baseline: store: %w", err)
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", versionTable).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("migrate: baseline: check version table: %w", err)
+	}
+
+	if exists {
+		var applied bool
+		err := tx.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM "+versionTable+" WHERE version_id > 0 AND is_applied)").Scan(&applied)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: baseline: check versions: %w", err)
+		}
+		if applied {
+			return nil, fmt.Errorf("migrate: baseline: %w", ErrAlreadyManaged)
+		}
+	} else {
+		// Как goose: таблица и нулевая версия.
+		if err := store.CreateVersionTable(ctx, tx); err != nil {
+			return nil, fmt.Errorf("migrate: baseline: %w", err)
+		}
+		if err := store.Insert(ctx, tx, database.InsertRequest{Version: 0}); err != nil {
+			return nil, fmt.Errorf("migrate: baseline: %w", err)
+		}
+	}
+
+	for _, v := range versions {
+		if err := store.Insert(ctx, tx, database.InsertRequest{Version: v}); err != nil {
+			return nil, fmt.Errorf("migrate: baseline: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("migrate: baseline: commit: %w", err)
+	}
+
+	// Суммы — от текущих файлов: baseline и есть утверждение «схема
+	// соответствует этим файлам».
+	return versions, m.syncChecksums(ctx, nil)
+}
+
+// checkGolangMigrate: dirty в schema_migrations значит, что последняя
+// миграция golang-migrate упала посередине — фиксировать такую схему нельзя.
+func (m *Migrator) checkGolangMigrate(ctx context.Context, tx *sql.Tx) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", legacyVersionTable).Scan(&exists); err != nil {
+		return fmt.Errorf("migrate: baseline: check schema_migrations: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err := tx.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("migrate: baseline: read schema_migrations: %w", err)
+	case dirty:
+		return fmt.Errorf("migrate: baseline: golang-migrate version %d is dirty, fix the schema first", version)
+	}
+
+	m.log.Info(ctx, "migrate: baseline: golang-migrate schema found", "version", version)
+
+	return nil
+}
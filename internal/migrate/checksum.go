@@ -0,0 +1,165 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+// checksumTable — SHA-256 применённых SQL-миграций (миграция
+// 20261018000004). goose хранит только номера версий.
+const checksumTable = "schema_migration_checksums"
+
+// ErrChecksumMismatch — файл уже применённой миграции изменён: схема в БД
+// не соответствует тому, что описывает бинарь.
+var ErrChecksumMismatch = errors.New("applied migration files were modified")
+
+// ErrPending — схема отстаёт от бинаря (serve --skip-migrations до migrate up).
+var ErrPending = errors.New("database has pending migrations")
+
+// Verify — проверка при старте без применения (serve --skip-migrations):
+// набор миграций бинаря совпадает с записанным в БД — нет неизвестных и
+// pending-версий, контрольные суммы применённых файлов не изменились.
+func (m *Migrator) Verify(ctx context.Context) error {
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(plan.Unknown) > 0 {
+		return fmt.Errorf("migrate: %w: %v", ErrUnknownVersions, plan.Unknown)
+	}
+	if len(plan.Pending) > 0 {
+		versions := make([]int64, 0, len(plan.Pending))
+		for _, pm := range plan.Pending {
+			versions = append(versions, pm.Version)
+		}
+		return fmt.Errorf("migrate: %w: %v", ErrPending, versions)
+	}
+
+	return m.verifyChecksums(ctx)
+}
+
+// checksums — SHA-256 SQL-файлов бинаря по версиям.
+func (m *Migrator) checksums() (map[int64]string, error) {
+	sums := make(map[int64]string)
+	for _, src := range m.provider.ListSources() {
+		if src.Type != goose.TypeSQL {
+			continue
+		}
+		data, err := fs.ReadFile(m.fsys, src.Path)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", src.Path, err)
+		}
+		sum := sha256.Sum256(data)
+		sums[src.Version] = hex.EncodeToString(sum[:])
+	}
+	return sums, nil
+}
+
+// recordedChecksums — записанные суммы; nil, если таблицы ещё нет (свежая БД
+// или схема ниже 20261018000004).
+func (m *Migrator) recordedChecksums(ctx context.Context) (map[int64]string, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", checksumTable).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("migrate: check checksum table: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, checksum FROM "+checksumTable)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: list checksums: %w", err)
+	}
+	defer rows.Close()
+
+	recorded := make(map[int64]string)
+	for rows.Next() {
+		var (
+			version int64
+			sum     string
+		)
+		if err := rows.Scan(&version, &sum); err != nil {
+			return nil, fmt.Errorf("migrate: scan checksum: %w", err)
+		}
+		recorded[version] = sum
+	}
+
+	return recorded, rows.Err()
+}
+
+func (m *Migrator) verifyChecksums(ctx context.Context) error {
+	recorded, err := m.recordedChecksums(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := m.checksums()
+	if err != nil {
+		return err
+	}
+
+	var modified []string
+	for version, sum := range recorded {
+		if cur, ok := current[version]; ok && cur != sum {
+			modified = append(modified, fmt.Sprint(version))
+		}
+	}
+	if len(modified) > 0 {
+		slices.Sort(modified)
+		return fmt.Errorf("migrate: %w: %s", ErrChecksumMismatch, strings.Join(modified, ", "))
+	}
+
+	return nil
+}
+
+// syncChecksums приводит таблицу сумм к состоянию после операции: откаченные
+// версии удаляются (повторное применение запишет новую сумму), применённые
+// без записи — записываются. Версии, применённые до появления таблицы,
+// получают сумму текущего файла при первом запуске.
+func (m *Migrator) syncChecksums(ctx context.Context, results []*goose.MigrationResult) error {
+	recorded, err := m.recordedChecksums(ctx)
+	if err != nil || recorded == nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Direction != "down" {
+			continue
+		}
+		if _, err := m.db.ExecContext(ctx, "DELETE FROM "+checksumTable+" WHERE version = $1", r.Source.Version); err != nil {
+			return fmt.Errorf("migrate: delete checksum %d: %w", r.Source.Version, err)
+		}
+		delete(recorded, r.Source.Version)
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := m.checksums()
+	if err != nil {
+		return err
+	}
+
+	for _, version := range applied {
+		sum, ok := current[version]
+		if _, done := recorded[version]; !ok || done {
+			continue
+		}
+		_, err := m.db.ExecContext(ctx,
+			"INSERT INTO "+checksumTable+" (version, checksum) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING",
+			version, sum)
+		if err != nil {
+			return fmt.Errorf("migrate: record checksum %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
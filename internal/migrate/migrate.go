@@ -0,0 +1,257 @@
+// Package migrate — goose-миграции схемы: общий мигратор для старта
+// сервиса (serve) и CLI (migrate up|down|status|redo|to).
+package migrate
+
+import (
+	"clean-arch-template/config"
+	"clean-arch-template/migrations"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+const (
+	defaultAttempts = 3
+	defaultTimeout  = time.Second
+
+	// versionTable — таблица версий goose (имя по умолчанию).
+	versionTable = "goose_db_version"
+	// legacyVersionTable — таблица версий golang-migrate: схема создана до
+	// перехода на goose, данные в ней есть (см. Baseline).
+	legacyVersionTable = "schema_migrations"
+)
+
+// Migrator владеет отдельным *sql.DB (goose работает через database/sql) и
+// goose.Provider. Session-lock (pg advisory lock) защищает от параллельного
+// применения несколькими репликами или Job-ом и репликой одновременно.
+//
+// Migrator не применяет то, что опасно без участия человека: при версиях в
+// БД, неизвестных бинарю, отказывает всегда; разрушающие Up-миграции
+// (destructiveMarker) и любые откаты — только с AllowDestructive.
+type Migrator struct {
+	db               *sql.DB
+	fsys             fs.FS
+	provider         *goose.Provider
+	allowDestructive bool
+	log              logger.Logger
+}
+
+// New открывает соединение, дожидается Postgres (несколько попыток с паузой,
+// прерываемых ctx) и создаёт провайдер над миграциями, встроенными в бинарь,
+// или над cfg.MigrationsDir, если он задан (разработка).
+func New(ctx context.Context, cfg config.DB, log logger.Logger) (*Migrator, error) {
+	db, err := sql.Open("pgx", cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("migrate: open db: %w", err)
+	}
+
+	fsys := fs.FS(migrations.FS)
+	if cfg.MigrationsDir != "" {
+		log.Info(ctx, "migrate: using migrations from directory", "dir", cfg.MigrationsDir)
+		fsys = os.DirFS(cfg.MigrationsDir)
+	}
+
+	m, err := newMigrator(ctx, db, fsys, log)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	m.allowDestructive = cfg.AllowDestructiveMigrations
+
+	return m, nil
+}
+
+// Option настраивает Migrator, созданный Open.
+type Option func(*Migrator)
+
+// WithAllowDestructive — то же, что DB.AllowDestructiveMigrations у New.
+func WithAllowDestructive(allow bool) Option {
+	return func(m *Migrator) { m.allowDestructive = allow }
+}
+
+// Open — мигратор над встроенными миграциями по готовой строке подключения.
+// Им пользуется тестовый харнесс (internal/pgtest): search_path изолированной
+// схемы задаётся параметром DSN.
+func Open(ctx context.Context, dsn string, log logger.Logger, opts ...Option) (*Migrator, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: open db: %w", err)
+	}
+
+	m, err := newMigrator(ctx, db, migrations.FS, log)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+func newMigrator(ctx context.Context, db *sql.DB, fsys fs.FS, log logger.Logger) (*Migrator, error) {
+	var err error
+	for attempts := defaultAttempts; attempts > 0; attempts-- {
+		err = db.PingContext(ctx)
+		if err == nil {
+			break
+		}
+		if attempts == 1 { // последняя попытка — пауза впустую не нужна
+			break
+		}
+		log.Debug(ctx, fmt.Sprintf("migrate: postgres is trying to connect, attempts left: %d", attempts-1))
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("migrate: cancelled while waiting for postgres: %w", ctx.Err())
+		case <-time.After(defaultTimeout):
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("migrate: postgres connect: %w", err)
+	}
+
+	sessionLocker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("migrate: session locker: %w", err)
+	}
+
+	provider, err := goose.NewProvider(
+		goose.DialectPostgres,
+		db,
+		fsys,
+		goose.WithSessionLocker(sessionLocker),
+		// Go-миграции — только явно перечисленные (backfills), без
+		// глобального реестра goose.
+		goose.WithDisableGlobalRegistry(true),
+		goose.WithGoMigrations(goMigrations(log)...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: provider: %w", err)
+	}
+
+	return &Migrator{db: db, fsys: fsys, provider: provider, log: log}, nil
+}
+
+// Close закрывает соединение мигратора. provider.Close() закрывает тот же db,
+// поэтому вызывается только он.
+func (m *Migrator) Close() error {
+	return m.provider.Close()
+}
+
+// Up применяет все pending-миграции, если Plan.Check их пропускает.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	if err := m.checkPlan(ctx, 0); err != nil {
+		return nil, err
+	}
+
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: up: %w", err)
+	}
+	return results, m.syncChecksums(ctx, results)
+}
+
+// Down откатывает последнюю применённую миграцию.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	if err := m.checkDown(); err != nil {
+		return nil, err
+	}
+
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: down: %w", err)
+	}
+	return result, m.syncChecksums(ctx, []*goose.MigrationResult{result})
+}
+
+// Redo откатывает и заново применяет последнюю миграцию — проверка Down-части.
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := m.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := m.provider.UpByOne(ctx)
+	if err != nil {
+		return []*goose.MigrationResult{down}, fmt.Errorf("migrate: redo: %w", err)
+	}
+
+	results := []*goose.MigrationResult{down, up}
+	return results, m.syncChecksums(ctx, results)
+}
+
+// To переводит схему на version: вверх или вниз в зависимости от текущей.
+func (m *Migrator) To(ctx context.Context, version int64) ([]*goose.MigrationResult, error) {
+	current, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*goose.MigrationResult
+	switch {
+	case version > current:
+		if err := m.checkPlan(ctx, version); err != nil {
+			return nil, err
+		}
+		results, err = m.provider.UpTo(ctx, version)
+	case version < current:
+		if err := m.checkDown(); err != nil {
+			return nil, err
+		}
+		results, err = m.provider.DownTo(ctx, version)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return results, fmt.Errorf("migrate: to %d: %w", version, err)
+	}
+
+	return results, m.syncChecksums(ctx, results)
+}
+
+// Status — состояние каждой известной бинарю миграции.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	status, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: status: %w", err)
+	}
+	return status, nil
+}
+
+// Version — текущая версия схемы в БД.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	version, err := m.provider.GetDBVersion(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("migrate: db version: %w", err)
+	}
+	return version, nil
+}
+
+// checkPlan — перед Up: план допустим и применённые файлы не изменены.
+func (m *Migrator) checkPlan(ctx context.Context, upTo int64) error {
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	if err := plan.Check(upTo, m.allowDestructive); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	return m.verifyChecksums(ctx)
+}
+
+// checkDown: откат удаляет таблицы и данные — всегда разрушающий.
+func (m *Migrator) checkDown() error {
+	if !m.allowDestructive {
+		return fmt.Errorf("migrate: down: %w", ErrDestructive)
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-package app
+package migrate
 
 import (
 	"context"
@@ -11,7 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestApplyMigrationsStopsOnCancelledContext(t *testing.T) {
+func TestNewStopsOnCancelledContext(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -28,7 +28,7 @@ func TestApplyMigrationsStopsOnCancelledContext(t *testing.T) {
 	}
 
 	start := time.Now()
-	err := applyMigrations(ctx, cfg, &loggertest.Fake{})
+	_, err := New(ctx, cfg, &loggertest.Fake{})
 
 	require.ErrorIs(t, err, context.Canceled)
 	require.Less(t, time.Since(start), defaultTimeout,
@@ -0,0 +1,210 @@
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+// destructiveMarker помечает Up-часть миграции, которую нельзя применять без
+// явного разрешения (AllowDestructive): необратимо меняет данные или ломается
+// при повторном применении. Текст после маркера — причина для оператора.
+//
+//	-- +goose Up
+//	-- migrate:destructive повторное применение умножает балансы на 100
+const destructiveMarker = "-- migrate:destructive"
+
+var (
+	// ErrUnknownVersions — в БД применены версии, которых нет в бинаре: схема
+	// новее кода (откатили деплой, не откатив миграции).
+	ErrUnknownVersions = errors.New("database has migrations unknown to this binary")
+	// ErrDestructive — план содержит разрушающие шаги без AllowDestructive.
+	ErrDestructive = errors.New("destructive migrations require explicit permission (--allow-destructive)")
+)
+
+// Plan — что сделает Up, без применения.
+type Plan struct {
+	Current int64
+	Pending []PendingMigration
+	// Unknown — применённые в БД версии, которых нет среди миграций бинаря.
+	Unknown []int64
+	// Fresh — пустая БД: ни одной применённой миграции и нет таблицы
+	// golang-migrate (legacyVersionTable). Данных, которые разрушающий шаг
+	// мог бы испортить, ещё нет, поэтому маркер не блокирует Up.
+	Fresh bool
+}
+
+// PendingMigration — неприменённая миграция и её Up-SQL.
+type PendingMigration struct {
+	Version     int64
+	Path        string
+	SQL         string
+	Destructive bool
+	Reason      string
+}
+
+// Plan строит план Up: pending-миграции с SQL и версии БД, неизвестные бинарю.
+func (m *Migrator) Plan(ctx context.Context) (*Plan, error) {
+	current, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	legacy, err := m.tableExists(ctx, legacyVersionTable)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Current: current, Fresh: current == 0 && !legacy}
+	for _, s := range status {
+		if s.State != goose.StatePending {
+			continue
+		}
+		pending, err := m.readPending(s.Source)
+		if err != nil {
+			return nil, err
+		}
+		plan.Pending = append(plan.Pending, pending)
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range applied {
+		if !slices.ContainsFunc(status, func(s *goose.MigrationStatus) bool { return s.Source.Version == v }) {
+			plan.Unknown = append(plan.Unknown, v)
+		}
+	}
+
+	return plan, nil
+}
+
+// Check — можно ли выполнять план до версии upTo (0 — весь план). На пустой
+// БД (Fresh) разрушающие миграции не блокируются: первый старт из образа
+// разворачивает схему без --allow-destructive.
+func (p *Plan) Check(upTo int64, allowDestructive bool) error {
+	if len(p.Unknown) > 0 {
+		return fmt.Errorf("%w: %v (schema version %d)", ErrUnknownVersions, p.Unknown, p.Current)
+	}
+
+	if allowDestructive || p.Fresh {
+		return nil
+	}
+
+	var blocked []string
+	for _, pm := range p.Pending {
+		if upTo > 0 && pm.Version > upTo {
+			break
+		}
+		if pm.Destructive {
+			blocked = append(blocked, fmt.Sprintf("%d (%s)", pm.Version, pm.Reason))
+		}
+	}
+	if len(blocked) > 0 {
+		return fmt.Errorf("%w: %s", ErrDestructive, strings.Join(blocked, ", "))
+	}
+
+	return nil
+}
+
+func (m *Migrator) readPending(src *goose.Source) (PendingMigration, error) {
+	pm := PendingMigration{Version: src.Version, Path: SourceName(src)}
+	if src.Type != goose.TypeSQL {
+		pm.SQL = "-- Go-миграция: данные переписываются пачками, без общей транзакции"
+		return pm, nil
+	}
+
+	data, err := fs.ReadFile(m.fsys, src.Path)
+	if err != nil {
+		return pm, fmt.Errorf("migrate: read %s: %w", src.Path, err)
+	}
+
+	pm.SQL, pm.Destructive, pm.Reason = upSection(string(data))
+
+	return pm, nil
+}
+
+// upSection возвращает текст между "-- +goose Up" и "-- +goose Down" как
+// есть, с комментариями: оператор читает его глазами перед применением.
+func upSection(content string) (sql string, destructive bool, reason string) {
+	var (
+		b    strings.Builder
+		inUp bool
+	)
+
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		line := sc.Text()
+		annotation := strings.ToLower(strings.Join(strings.Fields(line), " "))
+		switch {
+		case strings.HasPrefix(annotation, "-- +goose up"):
+			inUp = true
+			continue
+		case strings.HasPrefix(annotation, "-- +goose down"):
+			inUp = false
+			continue
+		case !inUp:
+			continue
+		case strings.HasPrefix(line, destructiveMarker):
+			destructive = true
+			reason = strings.TrimSpace(strings.TrimPrefix(line, destructiveMarker))
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	return strings.TrimSpace(b.String()), destructive, reason
+}
+
+// appliedVersions — версии, последняя запись которых в goose_db_version
+// отмечает применение (goose пишет строку и на up, и на down).
+func (m *Migrator) appliedVersions(ctx context.Context) ([]int64, error) {
+	exists, err := m.tableExists(ctx, versionTable)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT version_id FROM (
+			SELECT DISTINCT ON (version_id) version_id, is_applied
+			FROM `+versionTable+`
+			ORDER BY version_id, id DESC
+		) v
+		WHERE is_applied AND version_id > 0
+		ORDER BY version_id`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: list applied versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []int64
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("migrate: scan version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+func (m *Migrator) tableExists(ctx context.Context, table string) (bool, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+		return false, fmt.Errorf("migrate: check table %s: %w", table, err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,62 @@
+package migrate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpSection(t *testing.T) {
+	t.Parallel()
+
+	sql, destructive, reason := upSection(`-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE t (id int);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE t;
+`)
+	assert.Equal(t, "-- +goose StatementBegin\nCREATE TABLE t (id int);\n-- +goose StatementEnd", sql)
+	assert.False(t, destructive)
+	assert.Empty(t, reason)
+}
+
+func TestUpSectionDestructiveMigration(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("../../migrations/20260712000001_money_bigint_fk_indexes.sql")
+	require.NoError(t, err)
+
+	sql, destructive, reason := upSection(string(data))
+	assert.True(t, destructive, "миграция балансов помечена migrate:destructive")
+	assert.Contains(t, reason, "умножает балансы на 100")
+	assert.Contains(t, sql, "ROUND(balance * 100)")
+	assert.NotContains(t, sql, "DROP INDEX", "Down-часть в план Up не попадает")
+}
+
+func TestPlanCheck(t *testing.T) {
+	t.Parallel()
+
+	plan := &Plan{
+		Current: 1,
+		Pending: []PendingMigration{
+			{Version: 2},
+			{Version: 3, Destructive: true, Reason: "balances x100"},
+			{Version: 4},
+		},
+	}
+
+	require.NoError(t, plan.Check(2, false), "до разрушающей миграции план проходит")
+	require.ErrorIs(t, plan.Check(0, false), ErrDestructive)
+	require.ErrorContains(t, plan.Check(3, false), "3 (balances x100)")
+	require.NoError(t, plan.Check(0, true))
+
+	fresh := &Plan{Pending: plan.Pending, Fresh: true}
+	require.NoError(t, fresh.Check(0, false), "на пустой БД маркер не блокирует первый старт")
+
+	plan.Unknown = []int64{99}
+	require.ErrorIs(t, plan.Check(0, true), ErrUnknownVersions, "схема новее кода — отказ даже с разрешением")
+}
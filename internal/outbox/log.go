@@ -0,0 +1,29 @@
+package outbox
+
+import (
+	"clean-arch-template/pkg/logger"
+	"context"
+)
+
+// LogPublisher пишет события в лог приложения (stdout): вариант по умолчанию
+// для локального запуска и отладки, когда внешнего получателя нет.
+type LogPublisher struct {
+	log logger.Logger
+}
+
+var _ Publisher = (*LogPublisher)(nil)
+
+func NewLogPublisher(log logger.Logger) *LogPublisher {
+	return &LogPublisher{log: log}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, msg Message) error {
+	p.log.Info(ctx, "outbox event",
+		"event_id", msg.ID,
+		"event_type", msg.Type,
+		"aggregate_id", msg.AggregateID,
+		"payload", string(msg.Payload),
+	)
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"clean-arch-template/pkg/logger"
+	"time"
+)
+
+// Option -.
+type Option func(*Relay)
+
+// BatchSize — сколько сообщений Relay забирает за один проход.
+func BatchSize(size int) Option {
+	return func(r *Relay) {
+		r.batchSize = size
+	}
+}
+
+// PollInterval — пауза между проходами, когда очередь пуста.
+func PollInterval(interval time.Duration) Option {
+	return func(r *Relay) {
+		r.pollInterval = interval
+	}
+}
+
+// MaxAttempts — после стольких неудачных попыток сообщение уходит в dead.
+func MaxAttempts(attempts int) Option {
+	return func(r *Relay) {
+		r.maxAttempts = attempts
+	}
+}
+
+// ClaimLease — на сколько захваченная пачка скрыта от других реплик; не
+// меньше четырёх PublishTimeout.
+func ClaimLease(lease time.Duration) Option {
+	return func(r *Relay) {
+		r.claimLease = lease
+	}
+}
+
+// PublishTimeout — предел одной публикации; по нему же пачка ограничена
+// временем (см. Relay.ProcessBatch).
+func PublishTimeout(timeout time.Duration) Option {
+	return func(r *Relay) {
+		r.publishTimeout = timeout
+	}
+}
+
+// WithLogger -.
+func WithLogger(l logger.Logger) Option {
+	return func(r *Relay) {
+		r.log = l
+	}
+}
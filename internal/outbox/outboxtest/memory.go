@@ -0,0 +1,40 @@
+// Package outboxtest provides an in-memory outbox.Publisher for consumer unit tests.
+package outboxtest
+
+import (
+	"clean-arch-template/internal/outbox"
+	"context"
+	"sync"
+)
+
+// Publisher накапливает опубликованные сообщения для ассертов. Если задан
+// FailWith, Publish возвращает её и сообщение не сохраняет — так тесты
+// проверяют ветку retry/dead letter.
+type Publisher struct {
+	mu       sync.Mutex
+	Messages []outbox.Message
+	FailWith error
+}
+
+var _ outbox.Publisher = (*Publisher)(nil)
+
+func (p *Publisher) Publish(_ context.Context, msg outbox.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.FailWith != nil {
+		return p.FailWith
+	}
+	p.Messages = append(p.Messages, msg)
+
+	return nil
+}
+
+// Published возвращает копию накопленных сообщений; безопасно вызывать
+// параллельно с Publish.
+func (p *Publisher) Published() []outbox.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]outbox.Message(nil), p.Messages...)
+}
@@ -0,0 +1,83 @@
+// Package outbox доставляет доменные события из таблицы outbox во внешние
+// системы: Relay выбирает готовые к отправке строки и передаёт их Publisher.
+// Доставка at-least-once — получатель дедуплицирует по Message.ID.
+package outbox
+
+import (
+	"clean-arch-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	PublisherLog     = "log"
+	PublisherWebhook = "webhook"
+)
+
+// Message — событие в том виде, в каком оно уходит наружу.
+type Message struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID int64           `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	// Attempts — сколько попыток доставки уже было до текущей.
+	Attempts int `json:"-"`
+}
+
+// Publisher доставляет одно сообщение. Ошибка означает «не доставлено»:
+// Relay повторит попытку с backoff, а после исчерпания попыток переведёт
+// сообщение в dead.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// MultiPublisher публикует сообщение во все Publisher по очереди. Ошибка
+// любого из них возвращает сообщение в retry целиком — остальные получат его
+// повторно, что укладывается в at-least-once.
+type MultiPublisher []Publisher
+
+var _ Publisher = MultiPublisher(nil)
+
+func (m MultiPublisher) Publish(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// PublisherConfig — настройки NewPublisher (секция outbox конфига).
+type PublisherConfig struct {
+	// Kind — PublisherLog или PublisherWebhook (env OUTBOX_PUBLISHER).
+	Kind       string
+	WebhookURL string
+	// Timeout — таймаут запроса webhook; по умолчанию _defaultPublishTimeout.
+	Timeout time.Duration
+}
+
+// NewPublisher — фабрика Publisher по cfg.Kind.
+func NewPublisher(cfg PublisherConfig, log logger.Logger) (Publisher, error) {
+	switch cfg.Kind {
+	case PublisherLog, "":
+		return NewLogPublisher(log), nil
+	case PublisherWebhook:
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("outbox publisher %q requires OUTBOX_WEBHOOK_URL", PublisherWebhook)
+		}
+		timeout := cfg.Timeout
+		if timeout <= 0 {
+			timeout = _defaultPublishTimeout
+		}
+		return NewWebhookPublisher(cfg.WebhookURL, &http.Client{Timeout: timeout}), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q (supported: %s, %s)", cfg.Kind, PublisherLog, PublisherWebhook)
+	}
+}
@@ -0,0 +1,112 @@
+package outbox
+
+import (
+	"clean-arch-template/pkg/logger/loggertest"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublisher(t *testing.T) {
+	t.Parallel()
+
+	cfg := PublisherConfig{}
+
+	p, err := NewPublisher(cfg, &loggertest.Fake{})
+	require.NoError(t, err)
+	assert.IsType(t, &LogPublisher{}, p)
+
+	cfg.Kind = PublisherWebhook
+	_, err = NewPublisher(cfg, &loggertest.Fake{})
+	require.Error(t, err, "webhook без URL — ошибка конфигурации")
+
+	cfg.WebhookURL = "http://example.invalid/events"
+	cfg.Timeout = 2 * time.Second
+	p, err = NewPublisher(cfg, &loggertest.Fake{})
+	require.NoError(t, err)
+	require.IsType(t, &WebhookPublisher{}, p)
+	assert.Equal(t, 2*time.Second, p.(*WebhookPublisher).client.Timeout)
+
+	cfg.Kind = "kafka"
+	_, err = NewPublisher(cfg, &loggertest.Fake{})
+	require.Error(t, err)
+}
+
+func TestMultiPublisherCallsAllAndJoinsErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	ok := publisherFunc(func(context.Context, Message) error { calls++; return nil })
+	failing := publisherFunc(func(context.Context, Message) error { calls++; return errors.New("down") })
+
+	err := MultiPublisher{failing, ok}.Publish(context.Background(), Message{ID: 1})
+	require.Error(t, err)
+	assert.Equal(t, 2, calls, "ошибка первого не должна отменять публикацию во второй")
+}
+
+type publisherFunc func(ctx context.Context, msg Message) error
+
+func (f publisherFunc) Publish(ctx context.Context, msg Message) error { return f(ctx, msg) }
+
+func TestLogPublisher(t *testing.T) {
+	t.Parallel()
+
+	fakeLog := &loggertest.Fake{}
+
+	err := NewLogPublisher(fakeLog).Publish(context.Background(), Message{ID: 1, Type: "user.created", Payload: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+
+	require.Len(t, fakeLog.Entries, 1)
+	assert.Equal(t, "outbox event", fakeLog.Entries[0].Msg)
+	assert.Contains(t, fakeLog.Entries[0].Args, "user.created")
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	t.Parallel()
+
+	msg := Message{
+		ID:          42,
+		Type:        "transfer.completed",
+		AggregateID: 1,
+		Payload:     json.RawMessage(`{"amount":300}`),
+		CreatedAt:   time.Unix(0, 0).UTC(),
+	}
+
+	t.Run("delivers message as JSON", func(t *testing.T) {
+		var got Message
+		var headers http.Header
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header.Clone()
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		t.Cleanup(srv.Close)
+
+		err := NewWebhookPublisher(srv.URL, srv.Client()).Publish(context.Background(), msg)
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+		assert.Equal(t, "42", headers.Get("X-Event-ID"))
+		assert.Equal(t, "transfer.completed", headers.Get("X-Event-Type"))
+		assert.Equal(t, msg.ID, got.ID)
+		assert.JSONEq(t, `{"amount":300}`, string(got.Payload))
+	})
+
+	t.Run("non-2xx is a delivery failure", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(srv.Close)
+
+		err := NewWebhookPublisher(srv.URL, srv.Client()).Publish(context.Background(), msg)
+		require.Error(t, err)
+	})
+}
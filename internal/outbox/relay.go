@@ -0,0 +1,266 @@
+package outbox
+
+import (
+	"clean-arch-template/pkg/backoff"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	_defaultBatchSize    = 100
+	_defaultPollInterval = time.Second
+	_defaultMaxAttempts  = 10
+
+	// _defaultClaimLease — на сколько захваченная пачка скрыта от других
+	// реплик. Если реплика упала посреди публикации, сообщения снова станут
+	// видны по истечении lease и будут доставлены повторно (at-least-once).
+	_defaultClaimLease     = time.Minute
+	_defaultPublishTimeout = 5 * time.Second
+
+	baseRetryDelay = time.Second
+	maxRetryDelay  = 5 * time.Minute
+)
+
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+	StatusDead      = "dead"
+)
+
+// DB — подмножество pgxpool.Pool, нужное Relay; транзакции не требуются:
+// захват пачки — один атомарный UPDATE.
+type DB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Relay периодически забирает готовые pending-сообщения и публикует их.
+// Несколько реплик работают параллельно: FOR UPDATE SKIP LOCKED раздаёт им
+// непересекающиеся пачки без ожидания на блокировках.
+type Relay struct {
+	db        DB
+	publisher Publisher
+
+	batchSize      int
+	pollInterval   time.Duration
+	maxAttempts    int
+	claimLease     time.Duration
+	publishTimeout time.Duration
+
+	log logger.Logger
+}
+
+func NewRelay(db DB, publisher Publisher, opts ...Option) *Relay {
+	r := &Relay{
+		db:             db,
+		publisher:      publisher,
+		batchSize:      _defaultBatchSize,
+		pollInterval:   _defaultPollInterval,
+		maxAttempts:    _defaultMaxAttempts,
+		claimLease:     _defaultClaimLease,
+		publishTimeout: _defaultPublishTimeout,
+		log:            logger.Nop(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run крутит цикл доставки до отмены ctx. Ошибки прохода логируются и не
+// останавливают цикл: недоступная БД не должна убивать воркер навсегда.
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		n, err := r.ProcessBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.log.Error(ctx, "outbox: process batch", "error", err.Error())
+		}
+
+		// Полная пачка — в очереди, вероятно, есть ещё: берём следующую сразу.
+		if err == nil && n == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+// ProcessBatch захватывает до batchSize сообщений, публикует их по одному и
+// фиксирует результат каждого. Возвращает число захваченных сообщений.
+//
+// Пачка ограничена временем: сообщение публикуется, только если до конца
+// lease хватает бюджета на публикацию и запись результата, иначе остаток
+// пачки сразу возвращается в очередь. Так ни одна публикация не идёт после
+// истечения lease, когда сообщение уже может забрать другая реплика.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	lease := r.lease()
+	deadline := time.Now().Add(lease)
+
+	messages, err := r.claim(ctx, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, msg := range messages {
+		if ctx.Err() != nil {
+			// Незавершённые сообщения вернутся в оборот после истечения lease.
+			return len(messages), ctx.Err()
+		}
+
+		if time.Until(deadline) < r.messageBudget() {
+			return len(messages), r.release(ctx, messages[i:])
+		}
+
+		if err := r.publish(ctx, msg); err != nil {
+			if markErr := r.markFailed(ctx, msg, err); markErr != nil {
+				return len(messages), markErr
+			}
+			continue
+		}
+
+		if err := r.markPublished(ctx, msg); err != nil {
+			return len(messages), err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// messageBudget — сколько lease должно оставаться, чтобы начать публикацию
+// сообщения: publishTimeout на саму публикацию и столько же на запись
+// результата.
+func (r *Relay) messageBudget() time.Duration {
+	return 2 * r.publishTimeout
+}
+
+// lease — claimLease, но не меньше двух бюджетов сообщения: иначе пачка не
+// успела бы опубликовать ни одного сообщения.
+func (r *Relay) lease() time.Duration {
+	return max(r.claimLease, 2*r.messageBudget())
+}
+
+// publish ограничивает одну публикацию publishTimeout: зависший получатель
+// не должен съесть lease остальной пачки.
+func (r *Relay) publish(ctx context.Context, msg Message) error {
+	ctx, cancel := context.WithTimeout(ctx, r.publishTimeout)
+	defer cancel()
+
+	return r.publisher.Publish(ctx, msg)
+}
+
+// claim одним запросом блокирует пачку (SKIP LOCKED) и сдвигает её
+// available_at на lease: публикация идёт вне транзакции и не держит
+// соединение пула, пока ждёт внешний Publisher.
+func (r *Relay) claim(ctx context.Context, lease time.Duration) ([]Message, error) {
+	raw, err := r.db.Query(ctx, `
+		UPDATE outbox
+		SET available_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE status = 'pending' AND available_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, payload, attempts, created_at
+	`, r.batchSize, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox messages: %w", err)
+	}
+
+	messages, err := pgx.CollectRows(raw, func(row pgx.CollectableRow) (Message, error) {
+		var msg Message
+		err := row.Scan(&msg.ID, &msg.Type, &msg.AggregateID, &msg.Payload, &msg.Attempts, &msg.CreatedAt)
+		return msg, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// release возвращает неопубликованный остаток пачки в очередь, не дожидаясь
+// истечения lease.
+func (r *Relay) release(ctx context.Context, messages []Message) error {
+	ids := make([]int64, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE outbox
+		SET available_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($1) AND status = 'pending'
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("release %d outbox messages: %w", len(ids), err)
+	}
+
+	r.log.Warn(ctx, "outbox: batch ran out of lease, remaining messages released",
+		"released", len(ids), "lease", r.lease().String(), "publish_timeout", r.publishTimeout.String())
+
+	return nil
+}
+
+func (r *Relay) markPublished(ctx context.Context, msg Message) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE outbox
+		SET status = 'published',
+		    attempts = attempts + 1,
+		    last_error = NULL,
+		    published_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, msg.ID)
+	if err != nil {
+		return fmt.Errorf("mark outbox message %d published: %w", msg.ID, err)
+	}
+
+	return nil
+}
+
+// markFailed откладывает сообщение на экспоненциальную паузу либо, если попытки
+// исчерпаны, переводит его в dead: из выборки Relay оно выпадает, разбор —
+// вручную (UPDATE outbox SET status = 'pending', attempts = 0 ...).
+func (r *Relay) markFailed(ctx context.Context, msg Message, publishErr error) error {
+	attempt := msg.Attempts + 1
+	status := StatusPending
+	if attempt >= r.maxAttempts {
+		status = StatusDead
+	}
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE outbox
+		SET status = $2,
+		    attempts = $3,
+		    last_error = $4,
+		    available_at = CURRENT_TIMESTAMP + make_interval(secs => $5)
+		WHERE id = $1
+	`, msg.ID, status, attempt, publishErr.Error(), backoff.Exponential(attempt, baseRetryDelay, maxRetryDelay).Seconds())
+	if err != nil {
+		return errors.Join(publishErr, fmt.Errorf("mark outbox message %d failed: %w", msg.ID, err))
+	}
+
+	if status == StatusDead {
+		r.log.Error(ctx, "outbox: message moved to dead letter",
+			"event_id", msg.ID, "event_type", msg.Type, "attempts", attempt, "error", publishErr.Error())
+	} else {
+		r.log.Warn(ctx, "outbox: publish failed, will retry",
+			"event_id", msg.ID, "event_type", msg.Type, "attempts", attempt, "error", publishErr.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package outbox_test
+
+import (
+	"clean-arch-template/internal/outbox"
+	"clean-arch-template/internal/outbox/outboxtest"
+	"clean-arch-template/pkg/logger/loggertest"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockDB(t *testing.T) pgxmock.PgxConnIface {
+	t.Helper()
+
+	mockDb, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDb.Close(context.Background()) })
+
+	return mockDb
+}
+
+func claimedRows(attempts int) *pgxmock.Rows {
+	return pgxmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "attempts", "created_at"}).
+		AddRow(int64(7), "user.created", int64(1), []byte(`{"id":1,"name":"test"}`), attempts, time.Unix(0, 0))
+}
+
+func TestRelayPublishesClaimedMessages(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	publisher := &outboxtest.Publisher{}
+	relay := outbox.NewRelay(mockDb, publisher, outbox.BatchSize(10))
+
+	mockDb.ExpectQuery("UPDATE outbox").
+		WithArgs(10, pgxmock.AnyArg()).
+		WillReturnRows(claimedRows(0))
+	mockDb.ExpectExec("UPDATE outbox SET status = 'published'").
+		WithArgs(int64(7)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	n, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	published := publisher.Published()
+	require.Len(t, published, 1)
+	assert.Equal(t, int64(7), published[0].ID)
+	assert.Equal(t, "user.created", published[0].Type)
+	assert.JSONEq(t, `{"id":1,"name":"test"}`, string(published[0].Payload))
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRelaySchedulesRetryOnPublishFailure(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	fakeLog := &loggertest.Fake{}
+	publisher := &outboxtest.Publisher{FailWith: errors.New("receiver down")}
+	relay := outbox.NewRelay(mockDb, publisher, outbox.MaxAttempts(3), outbox.WithLogger(fakeLog))
+
+	mockDb.ExpectQuery("UPDATE outbox").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(claimedRows(1))
+	// Вторая попытка из трёх: сообщение остаётся pending с backoff 2s.
+	mockDb.ExpectExec("UPDATE outbox").
+		WithArgs(int64(7), outbox.StatusPending, 2, "receiver down", float64(2)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	_, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, publisher.Published())
+
+	require.Len(t, fakeLog.Entries, 1)
+	assert.Equal(t, "WARN", fakeLog.Entries[0].Level)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRelayMovesExhaustedMessageToDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	fakeLog := &loggertest.Fake{}
+	publisher := &outboxtest.Publisher{FailWith: errors.New("receiver down")}
+	relay := outbox.NewRelay(mockDb, publisher, outbox.MaxAttempts(3), outbox.WithLogger(fakeLog))
+
+	mockDb.ExpectQuery("UPDATE outbox").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(claimedRows(2))
+	mockDb.ExpectExec("UPDATE outbox").
+		WithArgs(int64(7), outbox.StatusDead, 3, "receiver down", pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	_, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, fakeLog.Entries, 1)
+	assert.Equal(t, "ERROR", fakeLog.Entries[0].Level)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// TestRelayReleasesMessagesThatDoNotFitLease — пачка ограничена временем:
+// публикация начинается, только пока до конца lease хватает двух
+// PublishTimeout, остаток сразу возвращается в очередь.
+func TestRelayReleasesMessagesThatDoNotFitLease(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	fakeLog := &loggertest.Fake{}
+	publisher := &outboxtest.Publisher{}
+	slow := publisherFunc(func(ctx context.Context, msg outbox.Message) error {
+		time.Sleep(80 * time.Millisecond)
+		return publisher.Publish(ctx, msg)
+	})
+	// lease = 4 × PublishTimeout = 400ms; бюджет сообщения — 200ms: сообщения
+	// начинаются на 0, 80 и 160ms, на 240ms осталось 160ms — остаток в очередь.
+	relay := outbox.NewRelay(mockDb, slow, outbox.ClaimLease(0), outbox.PublishTimeout(100*time.Millisecond),
+		outbox.WithLogger(fakeLog))
+
+	rows := pgxmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "attempts", "created_at"})
+	for id := int64(1); id <= 5; id++ {
+		rows.AddRow(id, "user.created", id, []byte(`{}`), 0, time.Unix(0, 0))
+	}
+	mockDb.ExpectQuery("UPDATE outbox").
+		WithArgs(pgxmock.AnyArg(), 0.4).
+		WillReturnRows(rows)
+	for id := int64(1); id <= 3; id++ {
+		mockDb.ExpectExec("UPDATE outbox SET status = 'published'").
+			WithArgs(id).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	}
+	mockDb.ExpectExec("UPDATE outbox SET available_at = CURRENT_TIMESTAMP").
+		WithArgs([]int64{4, 5}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	n, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Len(t, publisher.Published(), 3)
+
+	require.Len(t, fakeLog.Entries, 1)
+	assert.Equal(t, "WARN", fakeLog.Entries[0].Level)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestRelayLimitsPublishByTimeout(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	hanging := publisherFunc(func(ctx context.Context, _ outbox.Message) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	relay := outbox.NewRelay(mockDb, hanging, outbox.PublishTimeout(10*time.Millisecond), outbox.WithLogger(&loggertest.Fake{}))
+
+	mockDb.ExpectQuery("UPDATE outbox").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(claimedRows(0))
+	mockDb.ExpectExec("UPDATE outbox").
+		WithArgs(int64(7), outbox.StatusPending, 1, context.DeadlineExceeded.Error(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	_, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+type publisherFunc func(ctx context.Context, msg outbox.Message) error
+
+func (f publisherFunc) Publish(ctx context.Context, msg outbox.Message) error { return f(ctx, msg) }
+
+func TestRelayRunStopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	relay := outbox.NewRelay(mockDb, &outboxtest.Publisher{}, outbox.PollInterval(time.Hour))
+
+	mockDb.ExpectQuery("UPDATE outbox").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "attempts", "created_at"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	// Пустая очередь — Run уходит в паузу на PollInterval; отмена должна её прервать.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run must return promptly after context cancellation")
+	}
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+
+	// !!! NO UPSTREAM DEPENDENCIES HERE, ONLY ENTITY/DOMAIN !!!
+	"clean-arch-template/internal/entity"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// catchUpBatch — сколько пропущенных событий дочитывается за один запрос.
+	catchUpBatch = 100
+	// recentIDsLimit — сколько последних отданных id помнит поток для
+	// дедупликации живых событий, уже отданных догрузкой из БД.
+	recentIDsLimit = 256
+)
+
+type ActivityUseCase struct {
+	userRepo     UserRepository
+	activityRepo ActivityRepository
+	feed         ActivityFeed
+}
+
+func NewActivityUseCase(ur UserRepository, ar ActivityRepository, feed ActivityFeed) *ActivityUseCase {
+	return &ActivityUseCase{userRepo: ur, activityRepo: ar, feed: feed}
+}
+
+// OpenAccountStream проверяет счёт и подписывается на его события. Ошибки
+// (нет счёта, лимит потоков) возвращаются до начала стриминга, чтобы
+// транспорт мог ответить обычным HTTP-кодом. Поток нужно закрыть (Close).
+func (uc *ActivityUseCase) OpenAccountStream(ctx context.Context, cmd StreamAccountEventsCommand) (*AccountStream, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ActivityUseCase.OpenAccountStream")
+	defer span.End()
+
+	if _, err := uc.userRepo.GetUserByID(ctx, int(cmd.AccountID)); err != nil {
+		return nil, err
+	}
+
+	// Подписка — до догрузки из БД: событие, закоммиченное между запросом и
+	// подпиской, иначе потерялось бы.
+	sub, err := uc.feed.Subscribe(cmd.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Без Last-Event-ID поток начинается с текущего момента: курсор догрузки
+	// ставится на последний перевод, иначе resync до первого живого события
+	// (переподключение LISTEN, переполнение буфера) выдал бы клиенту всю
+	// историю счёта.
+	lastID, resume := cmd.LastEventID, cmd.LastEventID > 0
+	if !resume {
+		if lastID, err = uc.activityRepo.GetAccountLastEventID(ctx, cmd.AccountID); err != nil {
+			sub.Close()
+			return nil, err
+		}
+	}
+
+	return &AccountStream{
+		repo:        uc.activityRepo,
+		sub:         sub,
+		accountID:   cmd.AccountID,
+		lastID:      lastID,
+		needCatchUp: resume,
+		recent:      make(map[int64]struct{}, recentIDsLimit),
+	}, nil
+}
+
+// AccountStream склеивает догрузку из БД и живые события в один поток без
+// повторов. Не потокобезопасен: читается одним SSE-соединением.
+type AccountStream struct {
+	repo      ActivityRepository
+	sub       Subscription
+	accountID int64
+
+	// lastID — максимальный отданный id, с него продолжается догрузка.
+	lastID      int64
+	needCatchUp bool
+	pending     []entity.AccountEvent
+
+	recent      map[int64]struct{}
+	recentOrder []int64
+}
+
+// Next блокируется до следующего события. Возвращает ctx.Err() по отмене ctx
+// (поток остаётся рабочим — так транспорт отмеряет heartbeat) и
+// entity.ErrStreamClosed при остановке ленты.
+func (s *AccountStream) Next(ctx context.Context) (entity.AccountEvent, error) {
+	for {
+		if len(s.pending) > 0 {
+			event := s.pending[0]
+			s.pending = s.pending[1:]
+			if s.remember(event.ID) {
+				return event, nil
+			}
+			continue
+		}
+
+		if s.needCatchUp {
+			events, err := s.repo.GetAccountEventsAfter(ctx, s.accountID, s.lastID, catchUpBatch)
+			if err != nil {
+				return entity.AccountEvent{}, err
+			}
+			s.pending = events
+			s.needCatchUp = len(events) == catchUpBatch
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return entity.AccountEvent{}, ctx.Err()
+		case <-s.sub.Done():
+			return entity.AccountEvent{}, entity.ErrStreamClosed
+		case <-s.sub.Resync():
+			s.needCatchUp = true
+		case event := <-s.sub.Events():
+			// Живые события не фильтруются по lastID: транзакции коммитятся не
+			// в порядке id, и отставший коммит с меньшим id — всё равно новость.
+			if s.remember(event.ID) {
+				return event, nil
+			}
+		}
+	}
+}
+
+// remember отмечает id отданным; false — событие уже было.
+func (s *AccountStream) remember(id int64) bool {
+	if _, ok := s.recent[id]; ok {
+		return false
+	}
+
+	s.recent[id] = struct{}{}
+	s.recentOrder = append(s.recentOrder, id)
+	if len(s.recentOrder) > recentIDsLimit {
+		delete(s.recent, s.recentOrder[0])
+		s.recentOrder = s.recentOrder[1:]
+	}
+
+	s.lastID = max(s.lastID, id)
+
+	return true
+}
+
+func (s *AccountStream) Close() {
+	s.sub.Close()
+}
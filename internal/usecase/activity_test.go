@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeSubscription — подписка на каналах, которыми управляет тест.
+type fakeSubscription struct {
+	events chan entity.AccountEvent
+	resync chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+func newFakeSubscription() *fakeSubscription {
+	return &fakeSubscription{
+		events: make(chan entity.AccountEvent, 8),
+		resync: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *fakeSubscription) Events() <-chan entity.AccountEvent { return s.events }
+func (s *fakeSubscription) Resync() <-chan struct{}            { return s.resync }
+func (s *fakeSubscription) Done() <-chan struct{}              { return s.done }
+func (s *fakeSubscription) Close()                             { s.closed = true }
+
+func newActivityUseCase(t *testing.T, sub Subscription) (*ActivityUseCase, *MockUserRepository, *MockActivityRepository) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	users := NewMockUserRepository(ctrl)
+	activity := NewMockActivityRepository(ctrl)
+	feed := NewMockActivityFeed(ctrl)
+	feed.EXPECT().Subscribe(gomock.Any()).Return(sub, nil).AnyTimes()
+
+	return NewActivityUseCase(users, activity, feed), users, activity
+}
+
+func event(id int64) entity.AccountEvent {
+	return entity.AccountEvent{ID: id, Type: entity.EventTransferDebited, AccountID: 1}
+}
+
+func TestAccountStreamResumesThenGoesLiveWithoutDuplicates(t *testing.T) {
+	t.Parallel()
+
+	sub := newFakeSubscription()
+	uc, users, activity := newActivityUseCase(t, sub)
+	ctx := context.Background()
+
+	users.EXPECT().GetUserByID(gomock.Any(), 1).Return(&entity.User{ID: 1}, nil)
+	activity.EXPECT().GetAccountEventsAfter(gomock.Any(), int64(1), int64(5), catchUpBatch).
+		Return([]entity.AccountEvent{event(6), event(7)}, nil)
+
+	stream, err := uc.OpenAccountStream(ctx, StreamAccountEventsCommand{AccountID: 1, LastEventID: 5})
+	require.NoError(t, err)
+
+	// 7 пришло и живым, и догрузкой; 9 закоммитилось раньше 8.
+	sub.events <- event(7)
+	sub.events <- event(9)
+	sub.events <- event(8)
+
+	var got []int64
+	for range 4 {
+		ev, err := stream.Next(ctx)
+		require.NoError(t, err)
+		got = append(got, ev.ID)
+	}
+	assert.Equal(t, []int64{6, 7, 9, 8}, got)
+
+	stream.Close()
+	assert.True(t, sub.closed)
+}
+
+func TestAccountStreamCatchesUpOnResync(t *testing.T) {
+	t.Parallel()
+
+	sub := newFakeSubscription()
+	uc, users, activity := newActivityUseCase(t, sub)
+	ctx := context.Background()
+
+	users.EXPECT().GetUserByID(gomock.Any(), 1).Return(&entity.User{ID: 1}, nil)
+	activity.EXPECT().GetAccountLastEventID(gomock.Any(), int64(1)).Return(int64(2), nil)
+	activity.EXPECT().GetAccountEventsAfter(gomock.Any(), int64(1), int64(3), catchUpBatch).
+		Return([]entity.AccountEvent{event(4)}, nil)
+
+	stream, err := uc.OpenAccountStream(ctx, StreamAccountEventsCommand{AccountID: 1})
+	require.NoError(t, err)
+
+	sub.events <- event(3)
+	ev, err := stream.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), ev.ID)
+
+	sub.resync <- struct{}{}
+	ev, err = stream.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), ev.ID)
+}
+
+// TestAccountStreamResyncBeforeFirstEvent — поток без Last-Event-ID
+// догружает после resync только новое, а не всю историю счёта.
+func TestAccountStreamResyncBeforeFirstEvent(t *testing.T) {
+	t.Parallel()
+
+	sub := newFakeSubscription()
+	uc, users, activity := newActivityUseCase(t, sub)
+	ctx := context.Background()
+
+	users.EXPECT().GetUserByID(gomock.Any(), 1).Return(&entity.User{ID: 1}, nil)
+	activity.EXPECT().GetAccountLastEventID(gomock.Any(), int64(1)).Return(int64(41), nil)
+	activity.EXPECT().GetAccountEventsAfter(gomock.Any(), int64(1), int64(41), catchUpBatch).
+		Return([]entity.AccountEvent{event(42)}, nil)
+
+	stream, err := uc.OpenAccountStream(ctx, StreamAccountEventsCommand{AccountID: 1})
+	require.NoError(t, err)
+
+	sub.resync <- struct{}{}
+	ev, err := stream.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), ev.ID)
+}
+
+func TestOpenAccountStreamClosesSubscriptionOnError(t *testing.T) {
+	t.Parallel()
+
+	sub := newFakeSubscription()
+	uc, users, activity := newActivityUseCase(t, sub)
+
+	users.EXPECT().GetUserByID(gomock.Any(), 1).Return(&entity.User{ID: 1}, nil)
+	activity.EXPECT().GetAccountLastEventID(gomock.Any(), int64(1)).Return(int64(0), errInternalServErr)
+
+	_, err := uc.OpenAccountStream(context.Background(), StreamAccountEventsCommand{AccountID: 1})
+	require.ErrorIs(t, err, errInternalServErr)
+	assert.True(t, sub.closed, "подписка не должна занимать слот потока")
+}
+
+func TestAccountStreamEndsOnShutdownAndHonoursCtx(t *testing.T) {
+	t.Parallel()
+
+	sub := newFakeSubscription()
+	uc, users, activity := newActivityUseCase(t, sub)
+
+	users.EXPECT().GetUserByID(gomock.Any(), 1).Return(&entity.User{ID: 1}, nil)
+	activity.EXPECT().GetAccountLastEventID(gomock.Any(), int64(1)).Return(int64(0), nil)
+
+	stream, err := uc.OpenAccountStream(context.Background(), StreamAccountEventsCommand{AccountID: 1})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = stream.Next(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(sub.done)
+	_, err = stream.Next(context.Background())
+	require.ErrorIs(t, err, entity.ErrStreamClosed)
+}
+
+func TestOpenAccountStreamUnknownAccount(t *testing.T) {
+	t.Parallel()
+
+	uc, users, _ := newActivityUseCase(t, newFakeSubscription())
+
+	users.EXPECT().GetUserByID(gomock.Any(), 42).Return(nil, entity.ErrUserNotFound)
+
+	_, err := uc.OpenAccountStream(context.Background(), StreamAccountEventsCommand{AccountID: 42})
+	require.ErrorIs(t, err, entity.ErrUserNotFound)
+}
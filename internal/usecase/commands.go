@@ -23,4 +23,39 @@ type (
 	TransferMoneyCommand struct {
 		entity.Transfer
 	}
+
+	CreateWebhookCommand struct {
+		AccountID  int64
+		URL        string
+		EventTypes []entity.EventType
+		// Secret пустой — use case сгенерирует случайный.
+		Secret string
+	}
+
+	UpdateWebhookCommand struct {
+		ID         int64
+		URL        string
+		EventTypes []entity.EventType
+		Enabled    bool
+		// Secret пустой — текущий секрет сохраняется; непустой — ротация.
+		Secret string
+	}
+
+	FindWebhookByIDCommand struct {
+		ID int64
+	}
+
+	FindAccountWebhooksCommand struct {
+		AccountID int64
+	}
+
+	DeleteWebhookByIDCommand struct {
+		ID int64
+	}
+
+	StreamAccountEventsCommand struct {
+		AccountID int64
+		// LastEventID > 0 — сначала отдать переводы после него (Last-Event-ID).
+		LastEventID int64
+	}
 )
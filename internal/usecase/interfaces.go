@@ -17,3 +17,47 @@ type UserRepository interface {
 
 	TransferMoney(ctx context.Context, transfer entity.Transfer) error
 }
+
+// UserMetrics — доменные метрики пользователей и переводов. Реализация
+// (OpenTelemetry, pkg/metrics) подключается в app: use case о ней не знает.
+// ctx несёт спан запроса — из него берутся exemplars.
+type UserMetrics interface {
+	TransferAttempted(ctx context.Context)
+	TransferSucceeded(ctx context.Context, amount int64)
+	// TransferFailed — reason из TransferFailureReason.
+	TransferFailed(ctx context.Context, reason string)
+	UserCreated(ctx context.Context)
+	UserDeleted(ctx context.Context)
+}
+
+type WebhookRepository interface {
+	InsertWebhook(ctx context.Context, input *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	GetWebhookByID(ctx context.Context, id int64) (*entity.WebhookSubscription, error)
+	GetWebhooksByAccountID(ctx context.Context, accountID int64) ([]entity.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, input *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+}
+
+type ActivityRepository interface {
+	GetAccountEventsAfter(ctx context.Context, accountID, afterID int64, limit int) ([]entity.AccountEvent, error)
+	// GetAccountLastEventID — id последнего перевода счёта; 0 — переводов нет.
+	GetAccountLastEventID(ctx context.Context, accountID int64) (int64, error)
+}
+
+// ActivityFeed — живые события счетов (в проде — LISTEN/NOTIFY, internal/activity).
+// Subscribe возвращает entity.ErrTooManyStreams при исчерпании лимита потоков
+// и entity.ErrStreamClosed после начала остановки.
+type ActivityFeed interface {
+	Subscribe(accountID int64) (Subscription, error)
+}
+
+// Subscription — подписка на события одного счёта.
+type Subscription interface {
+	Events() <-chan entity.AccountEvent
+	// Resync срабатывает, когда часть событий могла потеряться (переподключение
+	// слушателя, переполнение буфера): пропущенное нужно дочитать из БД.
+	Resync() <-chan struct{}
+	// Done закрывается при остановке ленты.
+	Done() <-chan struct{}
+	Close()
+}
@@ -143,3 +143,356 @@ func (mr *MockUserRepositoryMockRecorder) UpdateUser(ctx, input any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepository)(nil).UpdateUser), ctx, input)
 }
+
+// MockUserMetrics is a mock of UserMetrics interface.
+type MockUserMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockUserMetricsMockRecorder is the mock recorder for MockUserMetrics.
+type MockUserMetricsMockRecorder struct {
+	mock *MockUserMetrics
+}
+
+// NewMockUserMetrics creates a new mock instance.
+func NewMockUserMetrics(ctrl *gomock.Controller) *MockUserMetrics {
+	mock := &MockUserMetrics{ctrl: ctrl}
+	mock.recorder = &MockUserMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserMetrics) EXPECT() *MockUserMetricsMockRecorder {
+	return m.recorder
+}
+
+// TransferAttempted mocks base method.
+func (m *MockUserMetrics) TransferAttempted(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TransferAttempted", ctx)
+}
+
+// TransferAttempted indicates an expected call of TransferAttempted.
+func (mr *MockUserMetricsMockRecorder) TransferAttempted(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAttempted", reflect.TypeOf((*MockUserMetrics)(nil).TransferAttempted), ctx)
+}
+
+// TransferFailed mocks base method.
+func (m *MockUserMetrics) TransferFailed(ctx context.Context, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TransferFailed", ctx, reason)
+}
+
+// TransferFailed indicates an expected call of TransferFailed.
+func (mr *MockUserMetricsMockRecorder) TransferFailed(ctx, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferFailed", reflect.TypeOf((*MockUserMetrics)(nil).TransferFailed), ctx, reason)
+}
+
+// TransferSucceeded mocks base method.
+func (m *MockUserMetrics) TransferSucceeded(ctx context.Context, amount int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TransferSucceeded", ctx, amount)
+}
+
+// TransferSucceeded indicates an expected call of TransferSucceeded.
+func (mr *MockUserMetricsMockRecorder) TransferSucceeded(ctx, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferSucceeded", reflect.TypeOf((*MockUserMetrics)(nil).TransferSucceeded), ctx, amount)
+}
+
+// UserCreated mocks base method.
+func (m *MockUserMetrics) UserCreated(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UserCreated", ctx)
+}
+
+// UserCreated indicates an expected call of UserCreated.
+func (mr *MockUserMetricsMockRecorder) UserCreated(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCreated", reflect.TypeOf((*MockUserMetrics)(nil).UserCreated), ctx)
+}
+
+// UserDeleted mocks base method.
+func (m *MockUserMetrics) UserDeleted(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UserDeleted", ctx)
+}
+
+// UserDeleted indicates an expected call of UserDeleted.
+func (mr *MockUserMetricsMockRecorder) UserDeleted(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserDeleted", reflect.TypeOf((*MockUserMetrics)(nil).UserDeleted), ctx)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetWebhookByID mocks base method.
+func (m *MockWebhookRepository) GetWebhookByID(ctx context.Context, id int64) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", ctx, id)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhookByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhookByID), ctx, id)
+}
+
+// GetWebhooksByAccountID mocks base method.
+func (m *MockWebhookRepository) GetWebhooksByAccountID(ctx context.Context, accountID int64) ([]entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksByAccountID", ctx, accountID)
+	ret0, _ := ret[0].([]entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksByAccountID indicates an expected call of GetWebhooksByAccountID.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhooksByAccountID(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksByAccountID", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhooksByAccountID), ctx, accountID)
+}
+
+// InsertWebhook mocks base method.
+func (m *MockWebhookRepository) InsertWebhook(ctx context.Context, input *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", ctx, input)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) InsertWebhook(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).InsertWebhook), ctx, input)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookRepository) UpdateWebhook(ctx context.Context, input *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, input)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateWebhook(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateWebhook), ctx, input)
+}
+
+// MockActivityRepository is a mock of ActivityRepository interface.
+type MockActivityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockActivityRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockActivityRepositoryMockRecorder is the mock recorder for MockActivityRepository.
+type MockActivityRepositoryMockRecorder struct {
+	mock *MockActivityRepository
+}
+
+// NewMockActivityRepository creates a new mock instance.
+func NewMockActivityRepository(ctrl *gomock.Controller) *MockActivityRepository {
+	mock := &MockActivityRepository{ctrl: ctrl}
+	mock.recorder = &MockActivityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActivityRepository) EXPECT() *MockActivityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAccountEventsAfter mocks base method.
+func (m *MockActivityRepository) GetAccountEventsAfter(ctx context.Context, accountID, afterID int64, limit int) ([]entity.AccountEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountEventsAfter", ctx, accountID, afterID, limit)
+	ret0, _ := ret[0].([]entity.AccountEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountEventsAfter indicates an expected call of GetAccountEventsAfter.
+func (mr *MockActivityRepositoryMockRecorder) GetAccountEventsAfter(ctx, accountID, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountEventsAfter", reflect.TypeOf((*MockActivityRepository)(nil).GetAccountEventsAfter), ctx, accountID, afterID, limit)
+}
+
+// GetAccountLastEventID mocks base method.
+func (m *MockActivityRepository) GetAccountLastEventID(ctx context.Context, accountID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountLastEventID", ctx, accountID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountLastEventID indicates an expected call of GetAccountLastEventID.
+func (mr *MockActivityRepositoryMockRecorder) GetAccountLastEventID(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountLastEventID", reflect.TypeOf((*MockActivityRepository)(nil).GetAccountLastEventID), ctx, accountID)
+}
+
+// MockActivityFeed is a mock of ActivityFeed interface.
+type MockActivityFeed struct {
+	ctrl     *gomock.Controller
+	recorder *MockActivityFeedMockRecorder
+	isgomock struct{}
+}
+
+// MockActivityFeedMockRecorder is the mock recorder for MockActivityFeed.
+type MockActivityFeedMockRecorder struct {
+	mock *MockActivityFeed
+}
+
+// NewMockActivityFeed creates a new mock instance.
+func NewMockActivityFeed(ctrl *gomock.Controller) *MockActivityFeed {
+	mock := &MockActivityFeed{ctrl: ctrl}
+	mock.recorder = &MockActivityFeedMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActivityFeed) EXPECT() *MockActivityFeedMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockActivityFeed) Subscribe(accountID int64) (Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", accountID)
+	ret0, _ := ret[0].(Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockActivityFeedMockRecorder) Subscribe(accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockActivityFeed)(nil).Subscribe), accountID)
+}
+
+// MockSubscription is a mock of Subscription interface.
+type MockSubscription struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionMockRecorder
+	isgomock struct{}
+}
+
+// MockSubscriptionMockRecorder is the mock recorder for MockSubscription.
+type MockSubscriptionMockRecorder struct {
+	mock *MockSubscription
+}
+
+// NewMockSubscription creates a new mock instance.
+func NewMockSubscription(ctrl *gomock.Controller) *MockSubscription {
+	mock := &MockSubscription{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscription) EXPECT() *MockSubscriptionMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockSubscription) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSubscriptionMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSubscription)(nil).Close))
+}
+
+// Done mocks base method.
+func (m *MockSubscription) Done() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Done")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Done indicates an expected call of Done.
+func (mr *MockSubscriptionMockRecorder) Done() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Done", reflect.TypeOf((*MockSubscription)(nil).Done))
+}
+
+// Events mocks base method.
+func (m *MockSubscription) Events() <-chan entity.AccountEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].(<-chan entity.AccountEvent)
+	return ret0
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockSubscriptionMockRecorder) Events() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockSubscription)(nil).Events))
+}
+
+// Resync mocks base method.
+func (m *MockSubscription) Resync() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resync")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Resync indicates an expected call of Resync.
+func (mr *MockSubscriptionMockRecorder) Resync() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resync", reflect.TypeOf((*MockSubscription)(nil).Resync))
+}
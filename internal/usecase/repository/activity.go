@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"fmt"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ActivityChannel — канал LISTEN/NOTIFY, в который TransferMoney публикует
+// созданную строку transactions (JSON entity.Transaction).
+const ActivityChannel = "account_activity"
+
+// transactionRow повторяет entity.Transaction с тегами колонок: конвертация —
+// простое приведение типов.
+type transactionRow struct {
+	ID         int64     `db:"id"`
+	FromUserID int64     `db:"from_user_id"`
+	ToUserID   int64     `db:"to_user_id"`
+	Amount     int64     `db:"amount"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// ActivityRepository читает только primary, даже при заданных репликах:
+// догрузка по Last-Event-ID сдвигает курсор за отданные id, и строки, ещё не
+// доехавшие до отстающей реплики, поток потерял бы навсегда.
+type ActivityRepository struct {
+	db tx.DBGetter
+}
+
+func NewActivityRepository(db tx.DBGetter) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// GetAccountEventsAfter возвращает до limit переводов счёта с id > afterID
+// по возрастанию id — догрузка пропущенного по Last-Event-ID.
+func (r *ActivityRepository) GetAccountEventsAfter(ctx context.Context, accountID, afterID int64, limit int) ([]entity.AccountEvent, error) {
+	raw, err := r.db(ctx).Query(ctx, `
+		SELECT id, from_user_id, to_user_id, amount, created_at
+		FROM transactions
+		WHERE (from_user_id = $1 OR to_user_id = $1) AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`, accountID, afterID, limit)
+	if err != nil {
+		return nil, dbError(fmt.Errorf("query account events: %w", err))
+	}
+
+	transactions, err := pgx.CollectRows(raw, pgx.RowToStructByName[transactionRow])
+	if err != nil {
+		return nil, dbError(fmt.Errorf("collect account events: %w", err))
+	}
+
+	events := make([]entity.AccountEvent, 0, len(transactions))
+	for _, t := range transactions {
+		events = append(events, entity.Transaction(t).EventFor(accountID))
+	}
+
+	return events, nil
+}
+
+// GetAccountLastEventID — id последнего перевода счёта (0 — переводов нет):
+// точка отсчёта догрузки для потока, открытого без Last-Event-ID.
+func (r *ActivityRepository) GetAccountLastEventID(ctx context.Context, accountID int64) (int64, error) {
+	var id int64
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT COALESCE(MAX(id), 0)
+		FROM transactions
+		WHERE from_user_id = $1 OR to_user_id = $1
+	`, accountID).Scan(&id)
+	if err != nil {
+		return 0, dbError(fmt.Errorf("query last account event: %w", err))
+	}
+
+	return id, nil
+}
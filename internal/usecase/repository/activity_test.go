@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"testing"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountEventsAfter(t *testing.T) {
+	t.Parallel()
+
+	mockDb, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDb.Close(context.Background()) })
+
+	repo := NewActivityRepository(tx.DBGetter(func(ctx context.Context) tx.DB {
+		return mockDb
+	}))
+
+	mockDb.ExpectQuery("SELECT (.+) FROM transactions").
+		WithArgs(int64(1), int64(5), 100).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "from_user_id", "to_user_id", "amount", "created_at"}).
+			AddRow(int64(6), int64(1), int64(2), int64(300), time.Unix(0, 0)).
+			AddRow(int64(7), int64(3), int64(1), int64(50), time.Unix(1, 0)))
+
+	events, err := repo.GetAccountEventsAfter(context.Background(), 1, 5, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, entity.EventTransferDebited, events[0].Type)
+	assert.Equal(t, int64(2), events[0].CounterpartyID)
+	assert.Equal(t, entity.EventTransferCredited, events[1].Type)
+	assert.Equal(t, int64(3), events[1].CounterpartyID)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestGetAccountLastEventID(t *testing.T) {
+	t.Parallel()
+
+	mockDb, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDb.Close(context.Background()) })
+
+	repo := NewActivityRepository(tx.DBGetter(func(ctx context.Context) tx.DB {
+		return mockDb
+	}))
+
+	mockDb.ExpectQuery("SELECT COALESCE\\(MAX\\(id\\), 0\\) FROM transactions").
+		WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"coalesce"}).AddRow(int64(41)))
+
+	id, err := repo.GetAccountLastEventID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(41), id)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/backoff"
+	"clean-arch-template/pkg/cache"
+	"clean-arch-template/pkg/database"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+)
+
+// UserCacheChannel — канал NOTIFY, по которому реплики рассылают друг другу
+// инвалидацию кэша пользователей.
+const UserCacheChannel = "user_cache_invalidation"
+
+const (
+	cacheMeterName = "clean-arch-template/internal/usecase/repository"
+
+	// Имена кэшей в метриках (атрибут cache.name).
+	userCacheName     = "user"
+	userListCacheName = "user_list"
+
+	cacheReconnectBase = time.Second
+	cacheReconnectMax  = 30 * time.Second
+)
+
+var attrCacheName = attribute.Key("cache.name")
+
+// userInvalidation — содержимое уведомления: какие пользователи и сбрасывать
+// ли страницы списка.
+type userInvalidation struct {
+	IDs   []int `json:"ids,omitempty"`
+	Lists bool  `json:"lists,omitempty"`
+}
+
+// CachedUserRepository — кэширующий декоратор usecase.UserRepository:
+// GetUserByID и страницы GetAllUsers читаются из cache.Cache, остальное идёт
+// в next. Промахи по одному ключу схлопываются singleflight-ом: при истечении
+// популярной записи в БД уходит один запрос.
+//
+// Update, Delete и TransferMoney сбрасывают записи затронутых пользователей
+// (Insert, Update и Delete — ещё и страницы списка) локально и рассылают
+// инвалидацию другим репликам через pg_notify (см. Listen). Внутри транзакции
+// кэш не читается и не заполняется: там видны незакоммиченные данные.
+type CachedUserRepository struct {
+	next  usecase.UserRepository
+	users cache.Cache[entity.User]
+	lists cache.Cache[[]entity.User]
+	// db — для pg_notify; nil — без рассылки (одна реплика, демо-режим).
+	db  tx.DBGetter
+	log logger.Logger
+
+	group singleflight.Group
+	// epoch растёт при каждой инвалидации: загрузка, начатая до неё, не
+	// кладёт в кэш прочитанное старое значение, а новые промахи не
+	// присоединяются к её полёту.
+	mu    sync.Mutex
+	epoch uint64
+
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+}
+
+var _ usecase.UserRepository = (*CachedUserRepository)(nil)
+
+func NewCachedUserRepository(
+	next usecase.UserRepository,
+	users cache.Cache[entity.User],
+	lists cache.Cache[[]entity.User],
+	db tx.DBGetter,
+	log logger.Logger,
+) (*CachedUserRepository, error) {
+	meter := otel.Meter(cacheMeterName)
+	hits, err := meter.Int64Counter("app.cache.hits",
+		metric.WithDescription("Cache lookups answered from the cache."),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64Counter("app.cache.misses",
+		metric.WithDescription("Cache lookups that went to the database."),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachedUserRepository{
+		next:   next,
+		users:  users,
+		lists:  lists,
+		db:     db,
+		log:    log,
+		hits:   hits,
+		misses: misses,
+	}, nil
+}
+
+func (r *CachedUserRepository) GetAllUsers(ctx context.Context, offset, limit int) ([]entity.User, error) {
+	if tx.IsWithinTransaction(ctx) {
+		return r.next.GetAllUsers(ctx, offset, limit)
+	}
+
+	key := strconv.Itoa(offset) + ":" + strconv.Itoa(limit)
+	users, err := cached(ctx, r, r.lists, userListCacheName, key, func(ctx context.Context) ([]entity.User, error) {
+		return r.next.GetAllUsers(ctx, offset, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Копия: вызывающий волен менять срез, а в кэше он общий.
+	return slices.Clone(users), nil
+}
+
+// GetAllUsersWithOrders не кэшируется: заказы меняются мимо этого репозитория.
+func (r *CachedUserRepository) GetAllUsersWithOrders(ctx context.Context, offset, limit int) ([]entity.UserOrders, error) {
+	return r.next.GetAllUsersWithOrders(ctx, offset, limit)
+}
+
+func (r *CachedUserRepository) GetUserByID(ctx context.Context, id int) (*entity.User, error) {
+	if tx.IsWithinTransaction(ctx) {
+		return r.next.GetUserByID(ctx, id)
+	}
+
+	user, err := cached(ctx, r, r.users, userCacheName, strconv.Itoa(id), func(ctx context.Context) (entity.User, error) {
+		user, err := r.next.GetUserByID(ctx, id)
+		if err != nil {
+			return entity.User{}, err
+		}
+		return *user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *CachedUserRepository) InsertUser(ctx context.Context, input *entity.User) (*entity.User, error) {
+	user, err := r.next.InsertUser(ctx, input)
+	r.invalidate(ctx, userInvalidation{Lists: true})
+	return user, err
+}
+
+func (r *CachedUserRepository) UpdateUser(ctx context.Context, input *entity.User) (*entity.User, error) {
+	user, err := r.next.UpdateUser(ctx, input)
+	r.invalidate(ctx, userInvalidation{IDs: []int{input.ID}, Lists: true})
+	return user, err
+}
+
+func (r *CachedUserRepository) DeleteUser(ctx context.Context, id int) error {
+	err := r.next.DeleteUser(ctx, id)
+	r.invalidate(ctx, userInvalidation{IDs: []int{id}, Lists: true})
+	return err
+}
+
+func (r *CachedUserRepository) TransferMoney(ctx context.Context, transfer entity.Transfer) error {
+	err := r.next.TransferMoney(ctx, transfer)
+	r.invalidate(ctx, userInvalidation{IDs: []int{int(transfer.FromAccountID), int(transfer.ToAccountID)}})
+	return err
+}
+
+// Listen держит LISTEN на UserCacheChannel до отмены ctx и применяет
+// инвалидации других реплик (и свои же: запись внутри транзакции
+// уведомляет только после COMMIT, а локальный сброс случился раньше). После
+// переподключения кэш очищается целиком: уведомления без слушателя потеряны.
+func (r *CachedUserRepository) Listen(ctx context.Context, connect func(ctx context.Context) (*pgx.Conn, error)) {
+	var failures int
+	for reconnect := false; ; reconnect = true {
+		listened, err := r.listen(ctx, connect, reconnect)
+		if ctx.Err() != nil {
+			return
+		}
+		if listened {
+			failures = 0
+		}
+		failures++
+		r.log.Error(ctx, "user cache: listen failed, reconnecting", "error", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Exponential(failures, cacheReconnectBase, cacheReconnectMax)):
+		}
+	}
+}
+
+func (r *CachedUserRepository) listen(ctx context.Context, connect func(ctx context.Context) (*pgx.Conn, error), reconnect bool) (bool, error) {
+	conn, err := connect(ctx)
+	if err != nil {
+		return false, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = conn.Close(context.WithoutCancel(ctx)) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{UserCacheChannel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("listen %s: %w", UserCacheChannel, err)
+	}
+	if reconnect {
+		r.clear(ctx)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("wait for notification: %w", err)
+		}
+
+		var inv userInvalidation
+		if err := json.Unmarshal([]byte(notification.Payload), &inv); err != nil {
+			// Непонятное уведомление — значит, что-то изменилось: безопаснее
+			// сбросить всё.
+			r.log.Error(ctx, "user cache: malformed invalidation", "error", err.Error())
+			r.clear(ctx)
+			continue
+		}
+		r.drop(ctx, inv)
+	}
+}
+
+// cached отдаёт значение из c или загружает его через load, схлопывая
+// одновременные промахи по key. Загрузка идёт без отмены ctx первого
+// вызывающего (её результат нужен всем ждущим), но каждый ждёт не дольше
+// своего ctx. Загрузка читает primary (database.WithPrimary): промах сразу
+// после инвалидации на отстающей реплике закэшировал бы старую строку на
+// весь TTL, и новой инвалидации, которая бы её исправила, не будет.
+func cached[V any](ctx context.Context, r *CachedUserRepository, c cache.Cache[V], name, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	attrs := metric.WithAttributes(attrCacheName.String(name))
+	if v, ok := c.Get(ctx, key); ok {
+		r.hits.Add(ctx, 1, attrs)
+		return v, nil
+	}
+	r.misses.Add(ctx, 1, attrs)
+
+	r.mu.Lock()
+	epoch := r.epoch
+	r.mu.Unlock()
+
+	flight := r.group.DoChan(name+"/"+key+"@"+strconv.FormatUint(epoch, 10), func() (any, error) {
+		v, err := load(database.WithPrimary(context.WithoutCancel(ctx)))
+		if err != nil {
+			return v, err
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.epoch == epoch {
+			c.Set(ctx, key, v)
+		}
+		return v, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	case res := <-flight:
+		if res.Err != nil {
+			var zero V
+			return zero, res.Err
+		}
+		return res.Val.(V), nil
+	}
+}
+
+// invalidate сбрасывает записи локально и рассылает инвалидацию. Вызывается
+// и при ошибке записи: при обрыве связи исход COMMIT неизвестен.
+func (r *CachedUserRepository) invalidate(ctx context.Context, inv userInvalidation) {
+	r.drop(ctx, inv)
+
+	if r.db == nil {
+		return
+	}
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		r.log.Error(ctx, "user cache: marshal invalidation", "error", err.Error())
+		return
+	}
+	// Внутри транзакции уведомление уйдёт при COMMIT и пропадёт при откате.
+	if _, err := r.db(ctx).Exec(ctx, "SELECT pg_notify($1, $2)", UserCacheChannel, string(payload)); err != nil {
+		// Другие реплики увидят изменение не позже TTL записи.
+		r.log.Warn(ctx, "user cache: broadcast invalidation", "error", err.Error())
+	}
+}
+
+func (r *CachedUserRepository) drop(ctx context.Context, inv userInvalidation) {
+	keys := make([]string, 0, len(inv.IDs))
+	for _, id := range inv.IDs {
+		keys = append(keys, strconv.Itoa(id))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.epoch++
+	if len(keys) > 0 {
+		r.users.Delete(ctx, keys...)
+	}
+	if inv.Lists {
+		r.lists.Clear(ctx)
+	}
+}
+
+func (r *CachedUserRepository) clear(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.epoch++
+	r.users.Clear(ctx)
+	r.lists.Clear(ctx)
+}
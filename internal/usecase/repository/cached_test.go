@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/pgtest"
+	"clean-arch-template/internal/usecase"
+	"clean-arch-template/pkg/cache"
+	"clean-arch-template/pkg/database"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingUserRepo считает чтения, дошедшие до источника, и среди них —
+// разрешившие реплику; gate, если задан, задерживает GetUserByID до закрытия.
+type countingUserRepo struct {
+	usecase.UserRepository
+	gets         atomic.Int32
+	lists        atomic.Int32
+	replicaReads atomic.Int32
+	gate         chan struct{}
+}
+
+func (r *countingUserRepo) GetUserByID(ctx context.Context, id int) (*entity.User, error) {
+	r.gets.Add(1)
+	if !database.PrimaryForced(ctx) {
+		r.replicaReads.Add(1)
+	}
+	if r.gate != nil {
+		<-r.gate
+	}
+	return r.UserRepository.GetUserByID(ctx, id)
+}
+
+func (r *countingUserRepo) GetAllUsers(ctx context.Context, offset, limit int) ([]entity.User, error) {
+	r.lists.Add(1)
+	if !database.PrimaryForced(ctx) {
+		r.replicaReads.Add(1)
+	}
+	return r.UserRepository.GetAllUsers(ctx, offset, limit)
+}
+
+func newTestCachedRepo(t *testing.T, next usecase.UserRepository, db tx.DBGetter) *CachedUserRepository {
+	t.Helper()
+
+	repo, err := NewCachedUserRepository(next,
+		cache.NewLRU[entity.User](100, time.Minute),
+		cache.NewLRU[[]entity.User](100, time.Minute),
+		db, logger.Nop())
+	require.NoError(t, err)
+
+	return repo
+}
+
+// seedMemory — MemoryUserRepository со счетами alice (1) и bob (2).
+func seedMemory(t *testing.T) *countingUserRepo {
+	t.Helper()
+
+	mem := NewMemoryUserRepository()
+	for _, name := range []string{"alice", "bob"} {
+		_, err := mem.InsertUser(context.Background(), &entity.User{Name: name})
+		require.NoError(t, err)
+	}
+	require.NoError(t, mem.SetBalance(1, 100))
+
+	return &countingUserRepo{UserRepository: mem}
+}
+
+func TestCachedUserRepositoryReadsThroughAndInvalidates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	next := seedMemory(t)
+	repo := newTestCachedRepo(t, next, nil)
+
+	first, err := repo.GetUserByID(ctx, 1)
+	require.NoError(t, err)
+	first.Name = "mutated by caller"
+	second, err := repo.GetUserByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", second.Name, "вызывающий не портит значение в кэше")
+	assert.Equal(t, int32(1), next.gets.Load(), "второе чтение — из кэша")
+
+	_, err = repo.GetUserByID(ctx, 42)
+	require.ErrorIs(t, err, entity.ErrUserNotFound)
+	_, err = repo.GetUserByID(ctx, 42)
+	require.ErrorIs(t, err, entity.ErrUserNotFound)
+	assert.Equal(t, int32(3), next.gets.Load(), "ошибки не кэшируются")
+
+	_, err = repo.UpdateUser(ctx, &entity.User{ID: 1, Name: "alice v2"})
+	require.NoError(t, err)
+	user, err := repo.GetUserByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice v2", user.Name, "Update сбрасывает запись")
+
+	_, _ = repo.GetUserByID(ctx, 2)
+	gets := next.gets.Load()
+	require.NoError(t, repo.TransferMoney(ctx, entity.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 10}))
+	_, _ = repo.GetUserByID(ctx, 1)
+	_, _ = repo.GetUserByID(ctx, 2)
+	assert.Equal(t, gets+2, next.gets.Load(), "перевод сбрасывает оба счёта")
+
+	require.NoError(t, repo.DeleteUser(ctx, 2))
+	_, err = repo.GetUserByID(ctx, 2)
+	require.ErrorIs(t, err, entity.ErrUserNotFound, "Delete сбрасывает запись")
+	assert.Zero(t, next.replicaReads.Load(), "промахи читают primary: реплика может отставать от инвалидации")
+}
+
+func TestCachedUserRepositoryListPages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	next := seedMemory(t)
+	repo := newTestCachedRepo(t, next, nil)
+
+	page, err := repo.GetAllUsers(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	page[0].Name = "mutated by caller"
+
+	page, err = repo.GetAllUsers(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", page[0].Name)
+	assert.Equal(t, int32(1), next.lists.Load())
+
+	_, err = repo.GetAllUsers(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), next.lists.Load(), "страницы кэшируются по offset и limit")
+
+	_, err = repo.InsertUser(ctx, &entity.User{Name: "carol"})
+	require.NoError(t, err)
+	page, err = repo.GetAllUsers(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, page, 3, "Insert сбрасывает страницы")
+	assert.Zero(t, next.replicaReads.Load(), "промахи читают primary")
+}
+
+func TestCachedUserRepositorySingleflight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	next := seedMemory(t)
+	next.gate = make(chan struct{})
+	repo := newTestCachedRepo(t, next, nil)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			user, err := repo.GetUserByID(ctx, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, "alice", user.Name)
+		})
+	}
+	require.Eventually(t, func() bool { return next.gets.Load() == 1 }, time.Second, time.Millisecond)
+	close(next.gate)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), next.gets.Load(), "одновременные промахи — один запрос к источнику")
+
+	// Ждущий с отменённым ctx не держится за чужую загрузку.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := repo.GetUserByID(cancelled, 2)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCachedUserRepositoryDiscardsStaleLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	next := seedMemory(t)
+	next.gate = make(chan struct{})
+	repo := newTestCachedRepo(t, next, nil)
+
+	// Чтение застряло в источнике, пока идёт запись.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = repo.GetUserByID(ctx, 1)
+	}()
+	require.Eventually(t, func() bool { return next.gets.Load() == 1 }, time.Second, time.Millisecond)
+
+	_, err := repo.UpdateUser(ctx, &entity.User{ID: 1, Name: "alice v2"})
+	require.NoError(t, err)
+	close(next.gate)
+	<-done
+
+	user, err := repo.GetUserByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice v2", user.Name, "загрузка, начатая до записи, в кэш не попала")
+}
+
+func TestCachedUserRepositoryBroadcastsInvalidation(t *testing.T) {
+	t.Parallel()
+
+	mockDb, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDb.Close(context.Background()) })
+	db := tx.DBGetter(func(context.Context) tx.DB { return mockDb })
+
+	repo := newTestCachedRepo(t, seedMemory(t), db)
+	ctx := context.Background()
+
+	notify := `SELECT pg_notify\(\$1, \$2\)`
+	mockDb.ExpectExec(notify).
+		WithArgs(UserCacheChannel, `{"ids":[1],"lists":true}`).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mockDb.ExpectExec(notify).
+		WithArgs(UserCacheChannel, `{"ids":[1,2]}`).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mockDb.ExpectExec(notify).
+		WithArgs(UserCacheChannel, `{"lists":true}`).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	_, err = repo.UpdateUser(ctx, &entity.User{ID: 1, Name: "alice v2"})
+	require.NoError(t, err)
+	require.NoError(t, repo.TransferMoney(ctx, entity.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 1}))
+	_, err = repo.InsertUser(ctx, &entity.User{Name: "carol"})
+	require.NoError(t, err)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+// TestCachedUserRepositoryListenPostgres — две «реплики» над одной схемой:
+// запись через одну сбрасывает кэш другой через LISTEN/NOTIFY.
+func TestCachedUserRepositoryListenPostgres(t *testing.T) {
+	pool := pgtest.Pool(t)
+	dsn := pgtest.URL(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, pool, "users", "outbox")
+	transactor, dbGetter := tx.NewTransactorFromPool(pool)
+	pgRepo := NewUserRepository(dbGetter, dbGetter, transactor)
+
+	writer := newTestCachedRepo(t, pgRepo, dbGetter)
+	reader := newTestCachedRepo(t, pgRepo, dbGetter)
+
+	listenCtx, stop := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		reader.Listen(listenCtx, func(ctx context.Context) (*pgx.Conn, error) {
+			return pgx.Connect(ctx, dsn)
+		})
+	})
+	t.Cleanup(func() { stop(); wg.Wait() })
+
+	// Уведомления до LISTEN теряются — ждём, пока сессия слушателя его выполнит.
+	require.Eventually(t, func() bool {
+		var listening bool
+		err := pool.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM pg_stat_activity
+			               WHERE query = $1 AND state = 'idle')`,
+			"LISTEN "+pgx.Identifier{UserCacheChannel}.Sanitize()).Scan(&listening)
+		return err == nil && listening
+	}, 5*time.Second, 10*time.Millisecond)
+
+	user, err := writer.InsertUser(ctx, &entity.User{Name: "alice"})
+	require.NoError(t, err)
+	cachedUser, err := reader.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "alice", cachedUser.Name)
+
+	_, err = writer.UpdateUser(ctx, &entity.User{ID: user.ID, Name: "alice v2"})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		u, err := reader.GetUserByID(ctx, user.ID)
+		return err == nil && u.Name == "alice v2"
+	}, 5*time.Second, 10*time.Millisecond, "кэш другой реплики сброшен уведомлением")
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferScenario — параметры прогона драйвера переводов. План переводов
+// детерминирован seed-ом, порядок исполнения — нет: его задаёт планировщик.
+type transferScenario struct {
+	seed      uint64
+	accounts  int
+	transfers int
+	workers   int
+	// maxAmount — верхняя граница суммы перевода; начальные балансы — до
+	// 3·maxAmount, поэтому часть переводов упирается в нехватку средств.
+	maxAmount int64
+}
+
+// newTransferScenario приводит произвольный вход фаззера к допустимым
+// границам: 2–8 счетов, до 1000 переводов, до 16 воркеров.
+func newTransferScenario(seed uint64, accounts, transfers, workers, maxAmount uint16) transferScenario {
+	return transferScenario{
+		seed:      seed,
+		accounts:  2 + int(accounts%7),
+		transfers: 1 + int(transfers%1000),
+		workers:   1 + int(workers%16),
+		maxAmount: 1 + int64(maxAmount),
+	}
+}
+
+// plannedTransfer — перевод плана.
+type plannedTransfer struct {
+	from, to, amount int64
+}
+
+// plan раскладывает переводы между счетами ids: треть — встречные к
+// предыдущему (A→B сразу за B→A), часть — с несуществующим счётом.
+func (s transferScenario) plan(ids []int64) []plannedTransfer {
+	rng := rand.New(rand.NewPCG(s.seed, s.seed^0x9e3779b97f4a7c15))
+	missing := ids[len(ids)-1] + 1000
+
+	list := make([]plannedTransfer, 0, s.transfers)
+	for i := range s.transfers {
+		p := plannedTransfer{amount: 1 + rng.Int64N(s.maxAmount)}
+		switch {
+		case i > 0 && rng.IntN(3) == 0:
+			prev := list[i-1]
+			p.from, p.to = prev.to, prev.from
+		case rng.IntN(50) == 0:
+			p.from, p.to = missing, ids[rng.IntN(len(ids))]
+			if rng.IntN(2) == 0 {
+				p.from, p.to = p.to, p.from
+			}
+		default:
+			from := rng.IntN(len(ids))
+			to := (from + 1 + rng.IntN(len(ids)-1)) % len(ids)
+			p.from, p.to = ids[from], ids[to]
+		}
+		list = append(list, p)
+	}
+
+	return list
+}
+
+// runTransferDriver гоняет план сценария конкурентно через любую реализацию
+// UserRepository и проверяет инварианты:
+//   - сумма балансов не меняется, ни один баланс не уходит в минус;
+//   - баланс каждого счёта — начальный плюс успешные зачисления минус
+//     успешные списания;
+//   - журнал переводов (если реализация его ведёт) совпадает с успешными
+//     переводами один к одному.
+func runTransferDriver(t *testing.T, f userRepoFixture, s transferScenario) {
+	t.Helper()
+	ctx := context.Background()
+	rng := rand.New(rand.NewPCG(s.seed, 0))
+
+	ids := make([]int64, 0, s.accounts)
+	initial := make(map[int64]int64, s.accounts)
+	var total int64
+	for range s.accounts {
+		user, err := f.repo.InsertUser(ctx, &entity.User{Name: "account"})
+		require.NoError(t, err)
+		id := int64(user.ID)
+		initial[id] = rng.Int64N(3*s.maxAmount + 1)
+		f.setBalance(t, user.ID, initial[id])
+		ids = append(ids, id)
+		total += initial[id]
+	}
+
+	plan := s.plan(ids)
+	jobs := make(chan plannedTransfer)
+	var (
+		mu        sync.Mutex
+		succeeded []plannedTransfer
+		wg        sync.WaitGroup
+	)
+	for range s.workers {
+		wg.Go(func() {
+			for p := range jobs {
+				err := f.repo.TransferMoney(ctx, entity.Transfer{FromAccountID: p.from, ToAccountID: p.to, Amount: p.amount})
+				switch {
+				case err == nil:
+					mu.Lock()
+					succeeded = append(succeeded, p)
+					mu.Unlock()
+				case errors.Is(err, entity.ErrInsufficientFunds),
+					errors.Is(err, entity.ErrSourceAccountNotFound),
+					errors.Is(err, entity.ErrDestAccountNotFound):
+				default:
+					t.Errorf("transfer %d→%d (%d): unexpected error: %v", p.from, p.to, p.amount, err)
+				}
+			}
+		})
+	}
+	for _, p := range plan {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+
+	want := maps.Clone(initial)
+	for _, p := range succeeded {
+		want[p.from] -= p.amount
+		want[p.to] += p.amount
+	}
+
+	var sum int64
+	for _, id := range ids {
+		balance := f.balance(t, int(id))
+		sum += balance
+		assert.GreaterOrEqual(t, balance, int64(0), "счёт %d ушёл в минус", id)
+		assert.Equal(t, want[id], balance, "счёт %d не сходится с успешными переводами", id)
+	}
+	assert.Equal(t, total, sum, "сумма балансов изменилась")
+
+	if f.transactions == nil {
+		return
+	}
+	logged := make(map[plannedTransfer]int)
+	for _, tr := range f.transactions(t) {
+		logged[plannedTransfer{from: tr.FromUserID, to: tr.ToUserID, amount: tr.Amount}]++
+	}
+	expected := make(map[plannedTransfer]int)
+	for _, p := range succeeded {
+		expected[p]++
+	}
+	assert.Equal(t, expected, logged, "журнал transactions расходится с успешными переводами")
+}
+
+// userRepoFixtures — реализации под драйвером; Postgres пропускается без
+// TEST_DATABASE_URL.
+var userRepoFixtures = map[string]func(t *testing.T) userRepoFixture{
+	"memory":   newMemoryFixture,
+	"postgres": newPostgresFixture,
+}
+
+func TestTransferMoneyConservation(t *testing.T) {
+	scenarios := map[string]transferScenario{
+		"opposite pair":             {seed: 1, accounts: 2, transfers: 2000, workers: 16, maxAmount: 100},
+		"many accounts":             {seed: 2, accounts: 8, transfers: 3000, workers: 16, maxAmount: 500},
+		"mostly insufficient funds": {seed: 3, accounts: 3, transfers: 1000, workers: 8, maxAmount: 10_000},
+	}
+
+	for backend, newFixture := range userRepoFixtures {
+		t.Run(backend, func(t *testing.T) {
+			for name, s := range scenarios {
+				t.Run(name, func(t *testing.T) {
+					runTransferDriver(t, newFixture(t), s)
+				})
+			}
+		})
+	}
+}
+
+// FuzzTransferMoneyConservation — тот же драйвер на случайных сценариях.
+// Сид-корпус — в testdata/fuzz/FuzzTransferMoneyConservation; обычный
+// go test прогоняет только его, поиск — go test -fuzz=FuzzTransferMoneyConservation.
+func FuzzTransferMoneyConservation(f *testing.F) {
+	f.Add(uint64(42), uint16(0), uint16(500), uint16(8), uint16(50))
+
+	f.Fuzz(func(t *testing.T, seed uint64, accounts, transfers, workers, maxAmount uint16) {
+		s := newTransferScenario(seed, accounts, transfers, workers, maxAmount)
+		for backend, newFixture := range userRepoFixtures {
+			t.Run(backend, func(t *testing.T) {
+				runTransferDriver(t, newFixture(t), s)
+			})
+		}
+	})
+}
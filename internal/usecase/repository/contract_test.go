@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/pgtest"
+	"clean-arch-template/internal/usecase"
+	"context"
+	"sync"
+	"testing"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userRepoFixture — реализация UserRepository под контрактом и доступ к
+// балансам: в entity.User их нет, а переводы без них не проверить.
+// transactions — журнал переводов; nil, если реализация его не ведёт.
+type userRepoFixture struct {
+	repo         usecase.UserRepository
+	setBalance   func(t *testing.T, id int, balance int64)
+	balance      func(t *testing.T, id int) int64
+	transactions func(t *testing.T) []entity.Transaction
+}
+
+func newMemoryFixture(*testing.T) userRepoFixture {
+	repo := NewMemoryUserRepository()
+	return userRepoFixture{
+		repo: repo,
+		setBalance: func(t *testing.T, id int, balance int64) {
+			require.NoError(t, repo.SetBalance(id, balance))
+		},
+		balance: func(t *testing.T, id int) int64 {
+			balance, err := repo.Balance(id)
+			require.NoError(t, err)
+			return balance
+		},
+	}
+}
+
+// newPostgresFixture — UserRepository над схемой пакета (pgtest) с
+// очищенными таблицами. Без TEST_DATABASE_URL тест пропускается.
+func newPostgresFixture(t *testing.T) userRepoFixture {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	transactor, dbGetter := tx.NewTransactorFromPool(pool)
+
+	pgtest.Truncate(t, pool, "users", "outbox")
+
+	return userRepoFixture{
+		repo: NewUserRepository(dbGetter, dbGetter, transactor),
+		setBalance: func(t *testing.T, id int, balance int64) {
+			_, err := pool.Exec(ctx, "UPDATE users SET balance = $2 WHERE id = $1", id, balance)
+			require.NoError(t, err)
+		},
+		balance: func(t *testing.T, id int) int64 {
+			var balance int64
+			require.NoError(t, pool.QueryRow(ctx, "SELECT balance FROM users WHERE id = $1", id).Scan(&balance))
+			return balance
+		},
+		transactions: func(t *testing.T) []entity.Transaction {
+			rows, err := pool.Query(ctx, "SELECT id, from_user_id, to_user_id, amount::bigint, created_at FROM transactions ORDER BY id")
+			require.NoError(t, err)
+			list, err := pgx.CollectRows(rows, pgx.RowToStructByPos[entity.Transaction])
+			require.NoError(t, err)
+			return list
+		},
+	}
+}
+
+func TestMemoryUserRepositoryContract(t *testing.T) {
+	t.Parallel()
+
+	testUserRepositoryContract(t, newMemoryFixture)
+}
+
+// TestPostgresUserRepositoryContract гоняет контракт на настоящем Postgres
+// (pgtest: схема пакета из TEST_DATABASE_URL, без переменной — пропуск).
+func TestPostgresUserRepositoryContract(t *testing.T) {
+	pgtest.Pool(t)
+
+	testUserRepositoryContract(t, newPostgresFixture)
+}
+
+// testUserRepositoryContract — поведение, общее для всех реализаций
+// usecase.UserRepository. Случаи идут последовательно: фикстура Postgres
+// очищает общие таблицы.
+func testUserRepositoryContract(t *testing.T, newFixture func(t *testing.T) userRepoFixture) {
+	ctx := context.Background()
+
+	insert := func(t *testing.T, repo usecase.UserRepository, name string) int {
+		t.Helper()
+		user, err := repo.InsertUser(ctx, &entity.User{Name: name})
+		require.NoError(t, err)
+		require.NotZero(t, user.ID)
+		return user.ID
+	}
+
+	t.Run("insert and get", func(t *testing.T) {
+		f := newFixture(t)
+
+		id := insert(t, f.repo, "alice")
+		user, err := f.repo.GetUserByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, entity.User{ID: id, Name: "alice"}, *user)
+		assert.Zero(t, f.balance(t, id), "новый счёт — с нулевым балансом")
+
+		_, err = f.repo.GetUserByID(ctx, id+1000)
+		require.ErrorIs(t, err, entity.ErrUserNotFound)
+	})
+
+	t.Run("list pages by id", func(t *testing.T) {
+		f := newFixture(t)
+
+		ids := []int{insert(t, f.repo, "a"), insert(t, f.repo, "b"), insert(t, f.repo, "c")}
+
+		page, err := f.repo.GetAllUsers(ctx, 1, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []entity.User{{ID: ids[1], Name: "b"}, {ID: ids[2], Name: "c"}}, page)
+
+		page, err = f.repo.GetAllUsers(ctx, 3, 5)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+
+		withOrders, err := f.repo.GetAllUsersWithOrders(ctx, 0, 2)
+		require.NoError(t, err)
+		require.Len(t, withOrders, 2)
+		assert.Equal(t, ids[0], withOrders[0].ID)
+		assert.NotNil(t, withOrders[0].Orders)
+		assert.Empty(t, withOrders[0].Orders)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		f := newFixture(t)
+
+		id := insert(t, f.repo, "old")
+		updated, err := f.repo.UpdateUser(ctx, &entity.User{ID: id, Name: "new"})
+		require.NoError(t, err)
+		assert.Equal(t, "new", updated.Name)
+
+		user, err := f.repo.GetUserByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "new", user.Name)
+
+		_, err = f.repo.UpdateUser(ctx, &entity.User{ID: id + 1000, Name: "ghost"})
+		require.ErrorIs(t, err, entity.ErrUserNotFound)
+
+		require.NoError(t, f.repo.DeleteUser(ctx, id))
+		require.ErrorIs(t, f.repo.DeleteUser(ctx, id), entity.ErrUserNotFound)
+		_, err = f.repo.GetUserByID(ctx, id)
+		require.ErrorIs(t, err, entity.ErrUserNotFound)
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		f := newFixture(t)
+
+		from, to := insert(t, f.repo, "from"), insert(t, f.repo, "to")
+		f.setBalance(t, from, 1000)
+
+		require.NoError(t, f.repo.TransferMoney(ctx, entity.Transfer{FromAccountID: int64(from), ToAccountID: int64(to), Amount: 300}))
+		assert.Equal(t, int64(700), f.balance(t, from))
+		assert.Equal(t, int64(300), f.balance(t, to))
+
+		// Ровно весь остаток — можно, на копейку больше — нет.
+		err := f.repo.TransferMoney(ctx, entity.Transfer{FromAccountID: int64(from), ToAccountID: int64(to), Amount: 701})
+		require.ErrorIs(t, err, entity.ErrInsufficientFunds)
+		require.NoError(t, f.repo.TransferMoney(ctx, entity.Transfer{FromAccountID: int64(from), ToAccountID: int64(to), Amount: 700}))
+		assert.Zero(t, f.balance(t, from))
+		assert.Equal(t, int64(1000), f.balance(t, to))
+	})
+
+	t.Run("transfer errors leave balances unchanged", func(t *testing.T) {
+		f := newFixture(t)
+
+		from, to := insert(t, f.repo, "from"), insert(t, f.repo, "to")
+		f.setBalance(t, from, 100)
+		missing := int64(to + 1000)
+
+		tests := []struct {
+			transfer entity.Transfer
+			want     error
+		}{
+			{entity.Transfer{FromAccountID: missing, ToAccountID: int64(to), Amount: 1}, entity.ErrSourceAccountNotFound},
+			{entity.Transfer{FromAccountID: int64(from), ToAccountID: missing, Amount: 1}, entity.ErrDestAccountNotFound},
+			// Оба счёта отсутствуют — сначала проверяется источник.
+			{entity.Transfer{FromAccountID: missing, ToAccountID: missing + 1, Amount: 1}, entity.ErrSourceAccountNotFound},
+			{entity.Transfer{FromAccountID: int64(from), ToAccountID: int64(to), Amount: 101}, entity.ErrInsufficientFunds},
+		}
+		for _, tc := range tests {
+			require.ErrorIs(t, f.repo.TransferMoney(ctx, tc.transfer), tc.want)
+		}
+
+		assert.Equal(t, int64(100), f.balance(t, from))
+		assert.Zero(t, f.balance(t, to))
+	})
+
+	t.Run("concurrent opposite transfers conserve money", func(t *testing.T) {
+		f := newFixture(t)
+
+		a, b := insert(t, f.repo, "a"), insert(t, f.repo, "b")
+		f.setBalance(t, a, 500)
+		f.setBalance(t, b, 500)
+
+		var wg sync.WaitGroup
+		for i := range 50 {
+			from, to := a, b
+			if i%2 == 1 {
+				from, to = b, a
+			}
+			wg.Go(func() {
+				err := f.repo.TransferMoney(ctx, entity.Transfer{FromAccountID: int64(from), ToAccountID: int64(to), Amount: 30})
+				if err != nil {
+					assert.ErrorIs(t, err, entity.ErrInsufficientFunds)
+				}
+			})
+		}
+		wg.Wait()
+
+		balanceA, balanceB := f.balance(t, a), f.balance(t, b)
+		assert.Equal(t, int64(1000), balanceA+balanceB)
+		assert.GreaterOrEqual(t, balanceA, int64(0))
+		assert.GreaterOrEqual(t, balanceB, int64(0))
+	})
+}
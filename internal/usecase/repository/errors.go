@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE таймаутов сервера.
+const (
+	pgQueryCanceled         = "57014" // statement_timeout и отмена запроса
+	pgLockNotAvailable      = "55P03" // lock_timeout, NOWAIT
+	pgIdleInTxSessionExpire = "25P03" // idle_in_transaction_session_timeout
+)
+
+// dbError добавляет к таймаутам Postgres доменную ошибку: транспорт отдаёт
+// их как 503/504, а не generic 500. Исходная ошибка остаётся в цепочке —
+// для логов и повторов транзакции (database.RetryableSQLState).
+func dbError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch {
+	// 57014 приходит и при отмене по ctx: её отличает текст сообщения, а
+	// вызывающий и так видит context.Canceled.
+	case pgErr.Code == pgQueryCanceled && strings.Contains(pgErr.Message, "statement timeout"),
+		pgErr.Code == pgIdleInTxSessionExpire:
+		return fmt.Errorf("%w: %w", entity.ErrQueryTimeout, err)
+	case pgErr.Code == pgLockNotAvailable:
+		return fmt.Errorf("%w: %w", entity.ErrResourceBusy, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"clean-arch-template/internal/pgtest"
+	"os"
+	"testing"
+)
+
+// Тесты на Postgres делят схему пакета (pgtest): она удаляется после всех тестов.
+func TestMain(m *testing.M) { os.Exit(pgtest.Main(m)) }
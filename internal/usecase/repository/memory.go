@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"slices"
+	"sync"
+)
+
+// memoryAccount — строка users: баланс в entity.User не входит, но нужен
+// переводам.
+type memoryAccount struct {
+	name    string
+	balance int64
+}
+
+// MemoryUserRepository — usecase.UserRepository в памяти процесса: для тестов
+// без Postgres и демо-режима (DB_BACKEND=memory). Семантика ошибок и
+// атомарность переводов — как у UserRepository (проверяется общим
+// контрактным тестом). Событий outbox, истории переводов и заказов нет:
+// GetAllUsersWithOrders отдаёт пользователей с пустым списком заказов.
+type MemoryUserRepository struct {
+	mu       sync.RWMutex
+	accounts map[int]*memoryAccount
+	lastID   int
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{accounts: make(map[int]*memoryAccount)}
+}
+
+func (r *MemoryUserRepository) GetAllUsers(ctx context.Context, offset, limit int) ([]entity.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := r.page(offset, limit)
+	users := make([]entity.User, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, entity.User{ID: id, Name: r.accounts[id].name})
+	}
+
+	return users, nil
+}
+
+func (r *MemoryUserRepository) GetAllUsersWithOrders(ctx context.Context, offset, limit int) ([]entity.UserOrders, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := r.page(offset, limit)
+	users := make([]entity.UserOrders, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, entity.UserOrders{ID: id, Name: r.accounts[id].name, Orders: []entity.Order{}})
+	}
+
+	return users, nil
+}
+
+func (r *MemoryUserRepository) GetUserByID(ctx context.Context, id int) (*entity.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account, ok := r.accounts[id]
+	if !ok {
+		return nil, entity.ErrUserNotFound
+	}
+
+	return &entity.User{ID: id, Name: account.name}, nil
+}
+
+func (r *MemoryUserRepository) InsertUser(ctx context.Context, input *entity.User) (*entity.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// id не переиспользуются, как у BIGSERIAL.
+	r.lastID++
+	r.accounts[r.lastID] = &memoryAccount{name: input.Name}
+	input.ID = r.lastID
+
+	return input, nil
+}
+
+func (r *MemoryUserRepository) UpdateUser(ctx context.Context, input *entity.User) (*entity.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.accounts[input.ID]
+	if !ok {
+		return nil, entity.ErrUserNotFound
+	}
+	account.name = input.Name
+
+	return input, nil
+}
+
+func (r *MemoryUserRepository) DeleteUser(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.accounts[id]; !ok {
+		return entity.ErrUserNotFound
+	}
+	delete(r.accounts, id)
+
+	return nil
+}
+
+// TransferMoney проверяет счета в том же порядке, что и UserRepository:
+// источник, получатель, средства. Перевод целиком под одной блокировкой —
+// встречные переводы не видят промежуточных балансов.
+func (r *MemoryUserRepository) TransferMoney(ctx context.Context, transfer entity.Transfer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from, ok := r.accounts[int(transfer.FromAccountID)]
+	if !ok {
+		return entity.ErrSourceAccountNotFound
+	}
+	to, ok := r.accounts[int(transfer.ToAccountID)]
+	if !ok {
+		return entity.ErrDestAccountNotFound
+	}
+	if from.balance < transfer.Amount {
+		return entity.ErrInsufficientFunds
+	}
+
+	from.balance -= transfer.Amount
+	to.balance += transfer.Amount
+
+	return nil
+}
+
+// SetBalance задаёт баланс счёта: наполнение демо-данными и тесты (у
+// UserRepository это делает SQL сида).
+func (r *MemoryUserRepository) SetBalance(id int, balance int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.accounts[id]
+	if !ok {
+		return entity.ErrUserNotFound
+	}
+	account.balance = balance
+
+	return nil
+}
+
+// Balance — текущий баланс счёта.
+func (r *MemoryUserRepository) Balance(id int) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account, ok := r.accounts[id]
+	if !ok {
+		return 0, entity.ErrUserNotFound
+	}
+
+	return account.balance, nil
+}
+
+// page — id страницы по возрастанию, как ORDER BY id OFFSET LIMIT.
+func (r *MemoryUserRepository) page(offset, limit int) []int {
+	ids := make([]int, 0, len(r.accounts))
+	for id := range r.accounts {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	offset, limit = max(offset, 0), max(limit, 0)
+	if offset >= len(ids) {
+		return nil
+	}
+	return ids[offset:min(offset+limit, len(ids))]
+}
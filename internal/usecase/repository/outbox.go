@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// addEvent пишет доменное событие в таблицу outbox через r.db(ctx): вызванный
+// внутри WithinTransaction, он попадает в ту же транзакцию, что и изменение
+// данных, — событие не теряется при падении и не публикуется при откате.
+func (r *UserRepository) addEvent(ctx context.Context, eventType entity.EventType, aggregateID int64, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+
+	_, err = r.db(ctx).Exec(ctx,
+		"INSERT INTO outbox(event_type, aggregate_id, payload) VALUES($1, $2, $3)",
+		string(eventType), aggregateID, data,
+	)
+	if err != nil {
+		return fmt.Errorf("insert %s event: %w", eventType, err)
+	}
+
+	return nil
+}
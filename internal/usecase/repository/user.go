@@ -18,13 +18,17 @@ type Transactor interface {
 }
 
 type UserRepository struct {
-	db         tx.DBGetter
+	db tx.DBGetter
+	// read — для методов только на чтение; может вести на реплику
+	// (database.Postgres.ReadDBGetter). Внутри транзакции совпадает с db.
+	read       tx.DBGetter
 	transactor Transactor
 }
 
-func NewUserRepository(db tx.DBGetter, transactor Transactor) *UserRepository {
+func NewUserRepository(db, read tx.DBGetter, transactor Transactor) *UserRepository {
 	return &UserRepository{
 		db:         db,
+		read:       read,
 		transactor: transactor,
 	}
 }
@@ -38,14 +42,14 @@ func (r *UserRepository) GetAllUsers(ctx context.Context, offset, limit int) ([]
 		OFFSET $1 LIMIT $2
 	`
 
-	raw, err := r.db(ctx).Query(ctx, query, offset, limit)
+	raw, err := r.read(ctx).Query(ctx, query, offset, limit)
 	if err != nil {
-		return nil, fmt.Errorf("query users: %w", err)
+		return nil, dbError(fmt.Errorf("query users: %w", err))
 	}
 
 	users, err := pgx.CollectRows(raw, pgx.RowToStructByName[entity.User])
 	if err != nil {
-		return nil, fmt.Errorf("collect users: %w", err)
+		return nil, dbError(fmt.Errorf("collect users: %w", err))
 	}
 
 	return users, nil
@@ -64,9 +68,9 @@ func (r *UserRepository) GetAllUsersWithOrders(ctx context.Context, offset, limi
 		OFFSET $1 LIMIT $2
 	`
 
-	raw, err := r.db(ctx).Query(ctx, query, offset, limit)
+	raw, err := r.read(ctx).Query(ctx, query, offset, limit)
 	if err != nil {
-		return nil, fmt.Errorf("query users with orders: %w", err)
+		return nil, dbError(fmt.Errorf("query users with orders: %w", err))
 	}
 
 	type row struct {
@@ -78,7 +82,7 @@ func (r *UserRepository) GetAllUsersWithOrders(ctx context.Context, offset, limi
 
 	rows, err := pgx.CollectRows(raw, pgx.RowToStructByName[row])
 	if err != nil {
-		return nil, fmt.Errorf("collect users with orders: %w", err)
+		return nil, dbError(fmt.Errorf("collect users with orders: %w", err))
 	}
 
 	result := make([]entity.UserOrders, 0, len(rows))
@@ -117,56 +121,74 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*entity.User,
 
 	var user entity.User
 
-	err := r.db(ctx).QueryRow(ctx, query, id).Scan(&user.ID, &user.Name)
+	err := r.read(ctx).QueryRow(ctx, query, id).Scan(&user.ID, &user.Name)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, entity.ErrUserNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("query user by id: %w", err)
+		return nil, dbError(fmt.Errorf("query user by id: %w", err))
 	}
 
 	return &user, nil
 }
 
 func (r *UserRepository) InsertUser(ctx context.Context, input *entity.User) (*entity.User, error) {
-	err := r.db(ctx).QueryRow(ctx, "INSERT INTO users(name) VALUES($1) RETURNING id", input.Name).Scan(&input.ID)
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := r.db(ctx).QueryRow(ctx, "INSERT INTO users(name) VALUES($1) RETURNING id", input.Name).Scan(&input.ID)
+		if err != nil {
+			return fmt.Errorf("insert user: %w", err)
+		}
+
+		return r.addEvent(ctx, entity.EventUserCreated, int64(input.ID), input)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("insert user: %w", err)
+		return nil, dbError(err)
 	}
 
 	return input, nil
 }
 
 func (r *UserRepository) UpdateUser(ctx context.Context, input *entity.User) (*entity.User, error) {
-	// Одним запросом, без предварительного чтения: RETURNING отличает
-	// «обновлено» от «не найдено» атомарно.
-	err := r.db(ctx).
-		QueryRow(ctx, "UPDATE users SET name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING id, name", input.ID, input.Name).
-		Scan(&input.ID, &input.Name)
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, entity.ErrUserNotFound
-	}
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Одним запросом, без предварительного чтения: RETURNING отличает
+		// «обновлено» от «не найдено» атомарно.
+		err := r.db(ctx).
+			QueryRow(ctx, "UPDATE users SET name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING id, name", input.ID, input.Name).
+			Scan(&input.ID, &input.Name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("update user: %w", err)
+		}
+
+		return r.addEvent(ctx, entity.EventUserUpdated, int64(input.ID), input)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("update user: %w", err)
+		return nil, dbError(err)
 	}
 
 	return input, nil
 }
 
 func (r *UserRepository) DeleteUser(ctx context.Context, id int) error {
-	ct, err := r.db(ctx).Exec(ctx, "DELETE FROM users WHERE id = $1", id)
-	if err != nil {
-		return fmt.Errorf("delete user: %w", err)
-	}
-	if ct.RowsAffected() == 0 {
-		return entity.ErrUserNotFound
-	}
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ct, err := r.db(ctx).Exec(ctx, "DELETE FROM users WHERE id = $1", id)
+		if err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+		if ct.RowsAffected() == 0 {
+			return entity.ErrUserNotFound
+		}
 
-	return nil
+		return r.addEvent(ctx, entity.EventUserDeleted, int64(id), entity.UserDeleted{ID: id})
+	})
+
+	return dbError(err)
 }
 
 func (r *UserRepository) TransferMoney(ctx context.Context, transfer entity.Transfer) error {
-	return r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Блокируем обе строки одним запросом в детерминированном порядке (ORDER BY id),
 		// иначе встречные переводы A→B и B→A взаимно блокируются (deadlock).
 		raw, err := r.db(ctx).Query(ctx,
@@ -223,14 +245,22 @@ func (r *UserRepository) TransferMoney(ctx context.Context, transfer entity.Tran
 			return fmt.Errorf("update destination account: %w", err)
 		}
 
+		// NOTIFY внутри транзакции доставляется слушателям только после COMMIT:
+		// SSE-потоки не увидят перевод, который откатился.
 		_, err = r.db(ctx).Exec(ctx, `
-			INSERT INTO transactions(from_user_id, to_user_id, amount)
-			VALUES($1, $2, $3)
-		`, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount)
+			WITH created AS (
+				INSERT INTO transactions(from_user_id, to_user_id, amount)
+				VALUES($1, $2, $3)
+				RETURNING id, from_user_id, to_user_id, amount, created_at
+			)
+			SELECT pg_notify($4, row_to_json(created)::text) FROM created
+		`, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount, ActivityChannel)
 		if err != nil {
 			return fmt.Errorf("create transaction: %w", err)
 		}
 
-		return nil
+		return r.addEvent(ctx, entity.EventMoneyTransferred, transfer.FromAccountID, transfer)
 	})
+
+	return dbError(err)
 }
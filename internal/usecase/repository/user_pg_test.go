@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/pgtest"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockAccountSQL — блокировка одного счёта, как её взял бы перевод без
+// упорядочивания.
+const lockAccountSQL = "SELECT balance FROM users WHERE id = $1 FOR UPDATE"
+
+// seedAccounts заводит счета с одинаковым балансом в очищенной схеме пакета.
+func seedAccounts(t *testing.T, pool *pgxpool.Pool, n int, balance int64) []int64 {
+	t.Helper()
+
+	pgtest.Truncate(t, pool, "users", "outbox")
+
+	ids := make([]int64, 0, n)
+	for range n {
+		var id int64
+		err := pool.QueryRow(context.Background(),
+			"INSERT INTO users (name, balance) VALUES ('account', $1) RETURNING id", balance).Scan(&id)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// TestTransferMoneyConcurrentOppositePostgres подтверждает, что блокировка
+// счетов в порядке id исключает deadlock: встречные переводы A→B/B→A и
+// «кольцо» A→B→C→A идут одновременно через транзактор без повторов, и любой
+// deadlock всплыл бы ошибкой 40P01.
+func TestTransferMoneyConcurrentOppositePostgres(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+
+	const (
+		perPair = 40
+		amount  = 7
+		initial = 10_000
+	)
+	ids := seedAccounts(t, pool, 3, initial)
+
+	transactor, dbGetter := tx.NewTransactorFromPool(pool)
+	repo := NewUserRepository(dbGetter, dbGetter, transactor)
+
+	// Все упорядоченные пары: у каждой есть встречная.
+	var pairs [][2]int64
+	for _, from := range ids {
+		for _, to := range ids {
+			if from != to {
+				pairs = append(pairs, [2]int64{from, to})
+			}
+		}
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range perPair * len(pairs) {
+		pair := pairs[i%len(pairs)]
+		wg.Go(func() {
+			<-start
+			err := repo.TransferMoney(ctx, entity.Transfer{FromAccountID: pair[0], ToAccountID: pair[1], Amount: amount})
+			assert.NoError(t, err, "перевод %d→%d", pair[0], pair[1])
+		})
+	}
+	close(start)
+	wg.Wait()
+
+	// Каждый счёт отдал и получил поровну — балансы вернулись к исходным.
+	for _, id := range ids {
+		var balance int64
+		require.NoError(t, pool.QueryRow(ctx, "SELECT balance FROM users WHERE id = $1", id).Scan(&balance))
+		assert.Equal(t, int64(initial), balance, "счёт %d", id)
+	}
+
+	var transfers int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT count(*) FROM transactions").Scan(&transfers))
+	assert.Equal(t, perPair*len(pairs), transfers, "каждый перевод записан в журнал ровно раз")
+}
+
+// TestNaiveLockOrderDeadlocksPostgres — контрольный случай: те же встречные
+// блокировки, взятые в порядке запроса, а не id, Postgres прерывает как
+// deadlock. Без него зелёный тест выше ничего бы не доказывал.
+func TestNaiveLockOrderDeadlocksPostgres(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+
+	ids := seedAccounts(t, pool, 2, 100)
+
+	// Каждая транзакция сначала берёт «свой» счёт, затем чужой.
+	txs := make([]pgx.Tx, 2)
+	for i := range txs {
+		txn, err := pool.Begin(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = txn.Rollback(ctx) })
+		_, err = txn.Exec(ctx, lockAccountSQL, ids[i])
+		require.NoError(t, err)
+		txs[i] = txn
+	}
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i, txn := range txs {
+		wg.Go(func() {
+			_, errs[i] = txn.Exec(ctx, lockAccountSQL, ids[1-i])
+		})
+	}
+	wg.Wait()
+
+	var deadlocks int
+	for _, err := range errs {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "40P01" {
+			deadlocks++
+		} else {
+			require.NoError(t, err)
+		}
+	}
+	assert.Equal(t, 1, deadlocks, "Postgres прерывает ровно одну из транзакций")
+}
@@ -3,10 +3,12 @@ package repository
 import (
 	"clean-arch-template/internal/entity"
 	"context"
+	"errors"
 	"testing"
 
 	tx "github.com/Thiht/transactor/pgx"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,7 +33,7 @@ func newMockDB(t *testing.T) (pgxmock.PgxConnIface, *UserRepository) {
 		return mockDb
 	})
 
-	repo := NewUserRepository(dbGetter, fakeTransactor{})
+	repo := NewUserRepository(dbGetter, dbGetter, fakeTransactor{})
 
 	return mockDb, repo
 }
@@ -50,6 +52,9 @@ func TestUserRepository(t *testing.T) {
 		mockDb.ExpectQuery("INSERT INTO users").
 			WithArgs(user.Name).
 			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mockDb.ExpectExec("INSERT INTO outbox").
+			WithArgs("user.created", int64(1), []byte(`{"id":1,"name":"test"}`)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		result, err := repo.InsertUser(ctx, &user)
 		require.NoError(t, err)
@@ -67,6 +72,9 @@ func TestUserRepository(t *testing.T) {
 		mockDb.ExpectQuery("UPDATE users").
 			WithArgs(user.ID, user.Name).
 			WillReturnRows(pgxmock.NewRows([]string{"id", "name"}).AddRow(1, "test"))
+		mockDb.ExpectExec("INSERT INTO outbox").
+			WithArgs("user.updated", int64(1), []byte(`{"id":1,"name":"test"}`)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		result, err := repo.UpdateUser(ctx, &user)
 		require.NoError(t, err)
@@ -76,6 +84,25 @@ func TestUserRepository(t *testing.T) {
 		require.NoError(t, mockDb.ExpectationsWereMet())
 	})
 
+	t.Run("test InsertUser fails when outbox write fails", func(t *testing.T) {
+		mockDb, repo := newMockDB(t)
+
+		mockDb.ExpectQuery("INSERT INTO users").
+			WithArgs("test").
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mockDb.ExpectExec("INSERT INTO outbox").
+			WithArgs("user.created", int64(1), pgxmock.AnyArg()).
+			WillReturnError(errors.New("outbox unavailable"))
+
+		// Ошибка outbox откатывает транзакцию целиком: пользователь без
+		// события не должен появиться.
+		result, err := repo.InsertUser(ctx, &entity.User{Name: "test"})
+		require.Error(t, err)
+		assert.Nil(t, result)
+
+		require.NoError(t, mockDb.ExpectationsWereMet())
+	})
+
 	t.Run("test UpdateUser not found", func(t *testing.T) {
 		mockDb, repo := newMockDB(t)
 
@@ -96,6 +123,9 @@ func TestUserRepository(t *testing.T) {
 		mockDb.ExpectExec("DELETE FROM users").
 			WithArgs(1).
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mockDb.ExpectExec("INSERT INTO outbox").
+			WithArgs("user.deleted", int64(1), []byte(`{"id":1}`)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err := repo.DeleteUser(ctx, 1)
 		require.NoError(t, err)
@@ -136,6 +166,64 @@ func TestUserRepository(t *testing.T) {
 		require.NoError(t, mockDb.ExpectationsWereMet())
 	})
 
+	t.Run("reads go to read getter, writes to primary", func(t *testing.T) {
+		primary, err := pgxmock.NewConn()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = primary.Close(context.Background()) })
+		replica, err := pgxmock.NewConn()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = replica.Close(context.Background()) })
+
+		repo := NewUserRepository(
+			func(context.Context) tx.DB { return primary },
+			func(context.Context) tx.DB { return replica },
+			fakeTransactor{},
+		)
+
+		replica.ExpectQuery("SELECT (.+) FROM users").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name"}).AddRow(1, "test"))
+		primary.ExpectQuery("UPDATE users").
+			WithArgs(1, "renamed").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name"}).AddRow(1, "renamed"))
+		primary.ExpectExec("INSERT INTO outbox").
+			WithArgs("user.updated", int64(1), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		_, err = repo.GetUserByID(ctx, 1)
+		require.NoError(t, err)
+		_, err = repo.UpdateUser(ctx, &entity.User{ID: 1, Name: "renamed"})
+		require.NoError(t, err)
+
+		require.NoError(t, replica.ExpectationsWereMet())
+		require.NoError(t, primary.ExpectationsWereMet())
+	})
+
+	t.Run("database timeouts map to domain errors", func(t *testing.T) {
+		tests := []struct {
+			pgErr *pgconn.PgError
+			want  error
+		}{
+			{&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}, entity.ErrQueryTimeout},
+			{&pgconn.PgError{Code: "25P03", Message: "terminating connection due to idle-in-transaction timeout"}, entity.ErrQueryTimeout},
+			{&pgconn.PgError{Code: "55P03", Message: "canceling statement due to lock timeout"}, entity.ErrResourceBusy},
+		}
+		for _, tc := range tests {
+			mockDb, repo := newMockDB(t)
+			mockDb.ExpectQuery("SELECT (.+) FROM users").WithArgs(1).WillReturnError(tc.pgErr)
+
+			_, err := repo.GetUserByID(ctx, 1)
+			require.ErrorIs(t, err, tc.want, tc.pgErr.Code)
+
+			var pgErr *pgconn.PgError
+			require.ErrorAs(t, err, &pgErr, "исходная ошибка остаётся в цепочке")
+		}
+
+		// Отмена по ctx — тот же 57014, но не таймаут сервера.
+		canceled := &pgconn.PgError{Code: "57014", Message: "canceling statement due to user request"}
+		assert.NotErrorIs(t, dbError(canceled), entity.ErrQueryTimeout)
+	})
+
 	t.Run("test GetUserByID not found", func(t *testing.T) {
 		mockDb, repo := newMockDB(t)
 
@@ -252,7 +340,7 @@ func TestTransferMoney(t *testing.T) {
 		return r
 	}
 
-	t.Run("successful transfer locks both accounts and writes transaction", func(t *testing.T) {
+	t.Run("successful transfer locks both accounts and writes transaction and event", func(t *testing.T) {
 		mockDb, repo := newMockDB(t)
 
 		mockDb.ExpectQuery("SELECT id, balance FROM users").
@@ -265,7 +353,10 @@ func TestTransferMoney(t *testing.T) {
 			WithArgs(int64(300), int64(2)).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 		mockDb.ExpectExec("INSERT INTO transactions").
-			WithArgs(int64(1), int64(2), int64(300)).
+			WithArgs(int64(1), int64(2), int64(300), ActivityChannel).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mockDb.ExpectExec("INSERT INTO outbox").
+			WithArgs("transfer.completed", int64(1), []byte(`{"from_account_id":1,"to_account_id":2,"amount":300}`)).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err := repo.TransferMoney(ctx, transfer)
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// pgForeignKeyViolation — SQLSTATE нарушения внешнего ключа.
+const pgForeignKeyViolation = "23503"
+
+type WebhookRepository struct {
+	db tx.DBGetter
+}
+
+func NewWebhookRepository(db tx.DBGetter) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// webhookRow — строка webhook_subscriptions; event_types в БД — TEXT[],
+// поэтому сканируется в []string и переводится в entity.EventType.
+type webhookRow struct {
+	ID         int64     `db:"id"`
+	AccountID  int64     `db:"account_id"`
+	URL        string    `db:"url"`
+	EventTypes []string  `db:"event_types"`
+	Secret     string    `db:"secret"`
+	Enabled    bool      `db:"enabled"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (row webhookRow) toEntity() entity.WebhookSubscription {
+	eventTypes := make([]entity.EventType, 0, len(row.EventTypes))
+	for _, eventType := range row.EventTypes {
+		eventTypes = append(eventTypes, entity.EventType(eventType))
+	}
+
+	return entity.WebhookSubscription{
+		ID:         row.ID,
+		AccountID:  row.AccountID,
+		URL:        row.URL,
+		EventTypes: eventTypes,
+		Secret:     row.Secret,
+		Enabled:    row.Enabled,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+const webhookColumns = "id, account_id, url, event_types, secret, enabled, created_at"
+
+func (r *WebhookRepository) InsertWebhook(ctx context.Context, input *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	raw, err := r.db(ctx).Query(ctx, `
+		INSERT INTO webhook_subscriptions(account_id, url, event_types, secret, enabled)
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING `+webhookColumns,
+		input.AccountID, input.URL, eventTypeStrings(input.EventTypes), input.Secret, input.Enabled,
+	)
+	if isForeignKeyViolation(err) {
+		return nil, entity.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, dbError(fmt.Errorf("insert webhook: %w", err))
+	}
+
+	// pgx отдаёт ошибку сервера либо из Query, либо при чтении строк.
+	row, err := pgx.CollectExactlyOneRow(raw, pgx.RowToStructByName[webhookRow])
+	if isForeignKeyViolation(err) {
+		return nil, entity.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, dbError(fmt.Errorf("insert webhook: %w", err))
+	}
+
+	sub := row.toEntity()
+
+	return &sub, nil
+}
+
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id int64) (*entity.WebhookSubscription, error) {
+	raw, err := r.db(ctx).Query(ctx, "SELECT "+webhookColumns+" FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return nil, dbError(fmt.Errorf("query webhook by id: %w", err))
+	}
+
+	row, err := pgx.CollectExactlyOneRow(raw, pgx.RowToStructByName[webhookRow])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, entity.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, dbError(fmt.Errorf("collect webhook by id: %w", err))
+	}
+
+	sub := row.toEntity()
+
+	return &sub, nil
+}
+
+func (r *WebhookRepository) GetWebhooksByAccountID(ctx context.Context, accountID int64) ([]entity.WebhookSubscription, error) {
+	raw, err := r.db(ctx).Query(ctx,
+		"SELECT "+webhookColumns+" FROM webhook_subscriptions WHERE account_id = $1 ORDER BY id",
+		accountID,
+	)
+	if err != nil {
+		return nil, dbError(fmt.Errorf("query webhooks by account: %w", err))
+	}
+
+	rows, err := pgx.CollectRows(raw, pgx.RowToStructByName[webhookRow])
+	if err != nil {
+		return nil, dbError(fmt.Errorf("collect webhooks by account: %w", err))
+	}
+
+	subs := make([]entity.WebhookSubscription, 0, len(rows))
+	for _, row := range rows {
+		subs = append(subs, row.toEntity())
+	}
+
+	return subs, nil
+}
+
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, input *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	// Пустой секрет сохраняет текущий; включение подписки обнуляет счётчик
+	// неудач, иначе первая же ошибка выключила бы её снова.
+	raw, err := r.db(ctx).Query(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = $2,
+		    event_types = $3,
+		    enabled = $4,
+		    secret = COALESCE(NULLIF($5, ''), secret),
+		    consecutive_failures = CASE WHEN $4 THEN 0 ELSE consecutive_failures END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+webhookColumns,
+		input.ID, input.URL, eventTypeStrings(input.EventTypes), input.Enabled, input.Secret,
+	)
+	if err != nil {
+		return nil, dbError(fmt.Errorf("update webhook: %w", err))
+	}
+
+	row, err := pgx.CollectExactlyOneRow(raw, pgx.RowToStructByName[webhookRow])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, entity.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, dbError(fmt.Errorf("collect updated webhook: %w", err))
+	}
+
+	sub := row.toEntity()
+
+	return &sub, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	ct, err := r.db(ctx).Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return dbError(fmt.Errorf("delete webhook: %w", err))
+	}
+	if ct.RowsAffected() == 0 {
+		return entity.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func eventTypeStrings(eventTypes []entity.EventType) []string {
+	out := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		out = append(out, string(eventType))
+	}
+
+	return out
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
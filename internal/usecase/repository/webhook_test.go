@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"testing"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookMockDB(t *testing.T) (pgxmock.PgxConnIface, *WebhookRepository) {
+	t.Helper()
+
+	mockDb, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDb.Close(context.Background()) })
+
+	return mockDb, NewWebhookRepository(tx.DBGetter(func(ctx context.Context) tx.DB {
+		return mockDb
+	}))
+}
+
+func webhookRows() *pgxmock.Rows {
+	return pgxmock.NewRows([]string{"id", "account_id", "url", "event_types", "secret", "enabled", "created_at"}).
+		AddRow(int64(1), int64(2), "https://partner.example.com", []string{"transfer.debited"}, "0123456789abcdef", true, time.Unix(0, 0))
+}
+
+func TestWebhookRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("test InsertWebhook", func(t *testing.T) {
+		mockDb, repo := newWebhookMockDB(t)
+
+		mockDb.ExpectQuery("INSERT INTO webhook_subscriptions").
+			WithArgs(int64(2), "https://partner.example.com", []string{"transfer.debited"}, "0123456789abcdef", true).
+			WillReturnRows(webhookRows())
+
+		sub, err := repo.InsertWebhook(ctx, &entity.WebhookSubscription{
+			AccountID:  2,
+			URL:        "https://partner.example.com",
+			EventTypes: []entity.EventType{entity.EventTransferDebited},
+			Secret:     "0123456789abcdef",
+			Enabled:    true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), sub.ID)
+		assert.Equal(t, []entity.EventType{entity.EventTransferDebited}, sub.EventTypes)
+
+		require.NoError(t, mockDb.ExpectationsWereMet())
+	})
+
+	t.Run("test InsertWebhook unknown account", func(t *testing.T) {
+		mockDb, repo := newWebhookMockDB(t)
+
+		mockDb.ExpectQuery("INSERT INTO webhook_subscriptions").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(&pgconn.PgError{Code: pgForeignKeyViolation})
+
+		_, err := repo.InsertWebhook(ctx, &entity.WebhookSubscription{AccountID: 42})
+		require.ErrorIs(t, err, entity.ErrUserNotFound)
+	})
+
+	t.Run("test GetWebhookByID not found", func(t *testing.T) {
+		mockDb, repo := newWebhookMockDB(t)
+
+		mockDb.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE id").
+			WithArgs(int64(9)).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "account_id", "url", "event_types", "secret", "enabled", "created_at"}))
+
+		_, err := repo.GetWebhookByID(ctx, 9)
+		require.ErrorIs(t, err, entity.ErrWebhookNotFound)
+	})
+
+	t.Run("test DeleteWebhook not found", func(t *testing.T) {
+		mockDb, repo := newWebhookMockDB(t)
+
+		mockDb.ExpectExec("DELETE FROM webhook_subscriptions").
+			WithArgs(int64(9)).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		require.ErrorIs(t, repo.DeleteWebhook(ctx, 9), entity.ErrWebhookNotFound)
+	})
+}
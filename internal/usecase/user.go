@@ -2,21 +2,33 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"unicode/utf8"
 
 	// !!! NO UPSTREAM DEPENDENCIES HERE, ONLY ENTITY/DOMAIN !!!
 	"clean-arch-template/internal/entity"
+
+	"go.opentelemetry.io/otel"
 )
 
+// tracerName — спаны use case встают между спаном хендлера и спанами pgx.
+// OTel API — сквозная инфраструктура наблюдаемости, как context, а не
+// внешний слой: без настроенного SDK вызовы ничего не делают.
+const tracerName = "usecase"
+
 type UserUseCase struct {
 	userRepo UserRepository
+	metrics  UserMetrics
 }
 
-func NewUserUseCase(ur UserRepository) *UserUseCase {
-	return &UserUseCase{userRepo: ur}
+func NewUserUseCase(ur UserRepository, m UserMetrics) *UserUseCase {
+	return &UserUseCase{userRepo: ur, metrics: m}
 }
 
 func (uc *UserUseCase) FindAllUsers(ctx context.Context, cmd FindAllUsersCommand) ([]entity.User, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "UserUseCase.FindAllUsers")
+	defer span.End()
+
 	if cmd.Page < 1 || cmd.Size < 1 {
 		return nil, entity.ErrInvalidPagination
 	}
@@ -28,18 +40,33 @@ func (uc *UserUseCase) FindAllUsers(ctx context.Context, cmd FindAllUsersCommand
 }
 
 func (uc *UserUseCase) FindUserByID(ctx context.Context, cmd FindUserByIDCommand) (*entity.User, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "UserUseCase.FindUserByID")
+	defer span.End()
+
 	return uc.userRepo.GetUserByID(ctx, cmd.ID)
 }
 
 func (uc *UserUseCase) CreateUser(ctx context.Context, cmd CreateUpdateUserCommand) (*entity.User, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "UserUseCase.CreateUser")
+	defer span.End()
+
 	if err := validateUserName(cmd.User.Name); err != nil {
 		return nil, err
 	}
 
-	return uc.userRepo.InsertUser(ctx, &cmd.User)
+	user, err := uc.userRepo.InsertUser(ctx, &cmd.User)
+	if err != nil {
+		return nil, err
+	}
+	uc.metrics.UserCreated(ctx)
+
+	return user, nil
 }
 
 func (uc *UserUseCase) UpdateUser(ctx context.Context, cmd CreateUpdateUserCommand) (*entity.User, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "UserUseCase.UpdateUser")
+	defer span.End()
+
 	if err := validateUserName(cmd.User.Name); err != nil {
 		return nil, err
 	}
@@ -48,10 +75,33 @@ func (uc *UserUseCase) UpdateUser(ctx context.Context, cmd CreateUpdateUserComma
 }
 
 func (uc *UserUseCase) DeleteUser(ctx context.Context, cmd DeleteUserByIDCommand) error {
-	return uc.userRepo.DeleteUser(ctx, cmd.ID)
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "UserUseCase.DeleteUser")
+	defer span.End()
+
+	if err := uc.userRepo.DeleteUser(ctx, cmd.ID); err != nil {
+		return err
+	}
+	uc.metrics.UserDeleted(ctx)
+
+	return nil
 }
 
 func (uc *UserUseCase) TransferMoney(ctx context.Context, cmd TransferMoneyCommand) error {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "UserUseCase.TransferMoney")
+	defer span.End()
+
+	uc.metrics.TransferAttempted(ctx)
+
+	if err := uc.transferMoney(ctx, cmd); err != nil {
+		uc.metrics.TransferFailed(ctx, TransferFailureReason(err))
+		return err
+	}
+	uc.metrics.TransferSucceeded(ctx, cmd.Amount)
+
+	return nil
+}
+
+func (uc *UserUseCase) transferMoney(ctx context.Context, cmd TransferMoneyCommand) error {
 	if cmd.Amount <= 0 {
 		return entity.ErrNegativeAmount
 	}
@@ -62,6 +112,32 @@ func (uc *UserUseCase) TransferMoney(ctx context.Context, cmd TransferMoneyComma
 	return uc.userRepo.TransferMoney(ctx, cmd.Transfer)
 }
 
+// TransferFailureReason — метка причины неудачного перевода: доменная
+// ошибка или internal. Набор значений конечен, чтобы не раздувать
+// кардинальность метрики.
+func TransferFailureReason(err error) string {
+	switch {
+	case errors.Is(err, entity.ErrInsufficientFunds):
+		return "insufficient_funds"
+	case errors.Is(err, entity.ErrNegativeAmount):
+		return "invalid_amount"
+	case errors.Is(err, entity.ErrSameAccount):
+		return "same_account"
+	case errors.Is(err, entity.ErrSourceAccountNotFound):
+		return "source_not_found"
+	case errors.Is(err, entity.ErrDestAccountNotFound):
+		return "destination_not_found"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	case errors.Is(err, entity.ErrQueryTimeout):
+		return "timeout"
+	case errors.Is(err, entity.ErrResourceBusy):
+		return "busy"
+	default:
+		return "internal"
+	}
+}
+
 func validateUserName(name string) error {
 	if name == "" || !utf8.ValidString(name) {
 		return entity.ErrInvalidUserName
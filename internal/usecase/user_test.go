@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"clean-arch-template/internal/entity"
+	"clean-arch-template/pkg/metrics"
 	"context"
 	"errors"
 	"testing"
@@ -17,7 +18,17 @@ func newUseCase(t *testing.T) (*UserUseCase, *MockUserRepository) {
 
 	repo := NewMockUserRepository(gomock.NewController(t))
 
-	return NewUserUseCase(repo), repo
+	return NewUserUseCase(repo, metrics.Nop()), repo
+}
+
+func newUseCaseWithMetrics(t *testing.T) (*UserUseCase, *MockUserRepository, *MockUserMetrics) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	repo := NewMockUserRepository(ctrl)
+	m := NewMockUserMetrics(ctrl)
+
+	return NewUserUseCase(repo, m), repo, m
 }
 
 func TestFindAllUsers(t *testing.T) {
@@ -286,6 +297,8 @@ func TestTransferMoney(t *testing.T) {
 		transfer entity.Transfer
 		mock     func(repo *MockUserRepository)
 		err      error
+		// reason — ожидаемая метка TransferFailed; пусто — успех.
+		reason string
 	}{
 		{
 			name:     "transfer success",
@@ -301,18 +314,21 @@ func TestTransferMoney(t *testing.T) {
 			transfer: entity.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 0},
 			mock:     func(repo *MockUserRepository) {},
 			err:      entity.ErrNegativeAmount,
+			reason:   "invalid_amount",
 		},
 		{
 			name:     "negative amount is rejected without repository call",
 			transfer: entity.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: -5},
 			mock:     func(repo *MockUserRepository) {},
 			err:      entity.ErrNegativeAmount,
+			reason:   "invalid_amount",
 		},
 		{
 			name:     "same account is rejected without repository call",
 			transfer: entity.Transfer{FromAccountID: 1, ToAccountID: 1, Amount: 100},
 			mock:     func(repo *MockUserRepository) {},
 			err:      entity.ErrSameAccount,
+			reason:   "same_account",
 		},
 		{
 			name:     "repository error is propagated",
@@ -322,7 +338,8 @@ func TestTransferMoney(t *testing.T) {
 					TransferMoney(gomock.Any(), entity.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 100}).
 					Return(entity.ErrInsufficientFunds)
 			},
-			err: entity.ErrInsufficientFunds,
+			err:    entity.ErrInsufficientFunds,
+			reason: "insufficient_funds",
 		},
 	}
 
@@ -330,8 +347,14 @@ func TestTransferMoney(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			userUseCase, repo := newUseCase(t)
+			userUseCase, repo, m := newUseCaseWithMetrics(t)
 			tc.mock(repo)
+			m.EXPECT().TransferAttempted(gomock.Any())
+			if tc.reason == "" {
+				m.EXPECT().TransferSucceeded(gomock.Any(), tc.transfer.Amount)
+			} else {
+				m.EXPECT().TransferFailed(gomock.Any(), tc.reason)
+			}
 
 			err := userUseCase.TransferMoney(context.Background(), TransferMoneyCommand{Transfer: tc.transfer})
 
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"clean-arch-template/pkg/netguard"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+
+	// !!! NO UPSTREAM DEPENDENCIES HERE, ONLY ENTITY/DOMAIN !!!
+	"clean-arch-template/internal/entity"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	minWebhookSecretLength = 16
+	generatedSecretBytes   = 32
+)
+
+// subscribableEvents — события, на которые партнёр может подписать свой счёт.
+var subscribableEvents = []entity.EventType{
+	entity.EventTransferDebited,
+	entity.EventTransferCredited,
+}
+
+type WebhookUseCase struct {
+	webhookRepo WebhookRepository
+}
+
+func NewWebhookUseCase(wr WebhookRepository) *WebhookUseCase {
+	return &WebhookUseCase{webhookRepo: wr}
+}
+
+// CreateWebhook возвращает подписку вместе с секретом: это единственный
+// момент, когда сгенерированный секрет можно показать клиенту.
+func (uc *WebhookUseCase) CreateWebhook(ctx context.Context, cmd CreateWebhookCommand) (*entity.WebhookSubscription, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "WebhookUseCase.CreateWebhook")
+	defer span.End()
+
+	eventTypes, err := validateWebhook(cmd.URL, cmd.EventTypes, cmd.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := cmd.Secret
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	return uc.webhookRepo.InsertWebhook(ctx, &entity.WebhookSubscription{
+		AccountID:  cmd.AccountID,
+		URL:        cmd.URL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Enabled:    true,
+	})
+}
+
+func (uc *WebhookUseCase) FindWebhookByID(ctx context.Context, cmd FindWebhookByIDCommand) (*entity.WebhookSubscription, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "WebhookUseCase.FindWebhookByID")
+	defer span.End()
+
+	return uc.webhookRepo.GetWebhookByID(ctx, cmd.ID)
+}
+
+func (uc *WebhookUseCase) FindAccountWebhooks(ctx context.Context, cmd FindAccountWebhooksCommand) ([]entity.WebhookSubscription, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "WebhookUseCase.FindAccountWebhooks")
+	defer span.End()
+
+	return uc.webhookRepo.GetWebhooksByAccountID(ctx, cmd.AccountID)
+}
+
+// UpdateWebhook заменяет URL, типы событий и флаг Enabled; включение
+// подписки обратно сбрасывает счётчик неудачных доставок.
+func (uc *WebhookUseCase) UpdateWebhook(ctx context.Context, cmd UpdateWebhookCommand) (*entity.WebhookSubscription, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "WebhookUseCase.UpdateWebhook")
+	defer span.End()
+
+	eventTypes, err := validateWebhook(cmd.URL, cmd.EventTypes, cmd.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.webhookRepo.UpdateWebhook(ctx, &entity.WebhookSubscription{
+		ID:         cmd.ID,
+		URL:        cmd.URL,
+		EventTypes: eventTypes,
+		Secret:     cmd.Secret,
+		Enabled:    cmd.Enabled,
+	})
+}
+
+func (uc *WebhookUseCase) DeleteWebhook(ctx context.Context, cmd DeleteWebhookByIDCommand) error {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "WebhookUseCase.DeleteWebhook")
+	defer span.End()
+
+	return uc.webhookRepo.DeleteWebhook(ctx, cmd.ID)
+}
+
+// validateWebhook проверяет URL, типы событий и (если задан) секрет;
+// возвращает типы событий без повторов.
+func validateWebhook(rawURL string, eventTypes []entity.EventType, secret string) ([]entity.EventType, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, entity.ErrInvalidWebhookURL
+	}
+
+	// Защита от SSRF: внутренние адреса отклоняем сразу; имена проверяются
+	// повторно при соединении, после резолва (см. pkg/netguard).
+	if netguard.CheckHost(u.Hostname()) != nil {
+		return nil, entity.ErrInvalidWebhookURL
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, entity.ErrInvalidEventTypes
+	}
+
+	unique := make([]entity.EventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(subscribableEvents, eventType) {
+			return nil, entity.ErrInvalidEventTypes
+		}
+		if !slices.Contains(unique, eventType) {
+			unique = append(unique, eventType)
+		}
+	}
+
+	if secret != "" && len(secret) < minWebhookSecretLength {
+		return nil, entity.ErrInvalidWebhookSecret
+	}
+
+	return unique, nil
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, generatedSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"clean-arch-template/internal/entity"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newWebhookUseCase(t *testing.T) (*WebhookUseCase, *MockWebhookRepository) {
+	t.Helper()
+
+	repo := NewMockWebhookRepository(gomock.NewController(t))
+
+	return NewWebhookUseCase(repo), repo
+}
+
+func TestCreateWebhookGeneratesSecret(t *testing.T) {
+	t.Parallel()
+
+	uc, repo := newWebhookUseCase(t)
+
+	repo.EXPECT().InsertWebhook(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+			sub.ID = 1
+			return sub, nil
+		})
+
+	sub, err := uc.CreateWebhook(context.Background(), CreateWebhookCommand{
+		AccountID:  1,
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []entity.EventType{entity.EventTransferDebited, entity.EventTransferDebited},
+	})
+	require.NoError(t, err)
+	assert.Len(t, sub.Secret, 2*generatedSecretBytes, "секрет — hex от 32 случайных байт")
+	assert.Equal(t, []entity.EventType{entity.EventTransferDebited}, sub.EventTypes, "повторы типов схлопываются")
+	assert.True(t, sub.Enabled)
+}
+
+func TestCreateWebhookValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cmd  CreateWebhookCommand
+		err  error
+	}{
+		{
+			name: "non-http scheme",
+			cmd:  CreateWebhookCommand{URL: "ftp://partner.example.com", EventTypes: []entity.EventType{entity.EventTransferDebited}},
+			err:  entity.ErrInvalidWebhookURL,
+		},
+		{
+			name: "relative url",
+			cmd:  CreateWebhookCommand{URL: "/hooks", EventTypes: []entity.EventType{entity.EventTransferDebited}},
+			err:  entity.ErrInvalidWebhookURL,
+		},
+		{
+			name: "loopback address",
+			cmd:  CreateWebhookCommand{URL: "http://127.0.0.1:8000/hooks", EventTypes: []entity.EventType{entity.EventTransferDebited}},
+			err:  entity.ErrInvalidWebhookURL,
+		},
+		{
+			name: "private network",
+			cmd:  CreateWebhookCommand{URL: "https://10.0.0.5/hooks", EventTypes: []entity.EventType{entity.EventTransferDebited}},
+			err:  entity.ErrInvalidWebhookURL,
+		},
+		{
+			name: "cloud metadata",
+			cmd:  CreateWebhookCommand{URL: "http://169.254.169.254/latest/meta-data/", EventTypes: []entity.EventType{entity.EventTransferDebited}},
+			err:  entity.ErrInvalidWebhookURL,
+		},
+		{
+			name: "localhost name",
+			cmd:  CreateWebhookCommand{URL: "http://localhost/hooks", EventTypes: []entity.EventType{entity.EventTransferDebited}},
+			err:  entity.ErrInvalidWebhookURL,
+		},
+		{
+			name: "no event types",
+			cmd:  CreateWebhookCommand{URL: "https://partner.example.com"},
+			err:  entity.ErrInvalidEventTypes,
+		},
+		{
+			name: "internal event type is not subscribable",
+			cmd:  CreateWebhookCommand{URL: "https://partner.example.com", EventTypes: []entity.EventType{entity.EventUserCreated}},
+			err:  entity.ErrInvalidEventTypes,
+		},
+		{
+			name: "short secret",
+			cmd: CreateWebhookCommand{
+				URL:        "https://partner.example.com",
+				EventTypes: []entity.EventType{entity.EventTransferCredited},
+				Secret:     "short",
+			},
+			err: entity.ErrInvalidWebhookSecret,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Репозиторий не вызывается: мок без ожиданий упадёт на любом вызове.
+			uc, _ := newWebhookUseCase(t)
+
+			_, err := uc.CreateWebhook(context.Background(), tc.cmd)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestUpdateWebhookKeepsSecretWhenEmpty(t *testing.T) {
+	t.Parallel()
+
+	uc, repo := newWebhookUseCase(t)
+
+	repo.EXPECT().UpdateWebhook(gomock.Any(), &entity.WebhookSubscription{
+		ID:         3,
+		URL:        "https://partner.example.com/v2",
+		EventTypes: []entity.EventType{entity.EventTransferCredited},
+		Enabled:    true,
+	}).Return(&entity.WebhookSubscription{ID: 3}, nil)
+
+	_, err := uc.UpdateWebhook(context.Background(), UpdateWebhookCommand{
+		ID:         3,
+		URL:        "https://partner.example.com/v2",
+		EventTypes: []entity.EventType{entity.EventTransferCredited},
+		Enabled:    true,
+	})
+	require.NoError(t, err)
+}
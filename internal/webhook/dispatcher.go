@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"clean-arch-template/internal/entity"
+	"clean-arch-template/internal/outbox"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Dispatcher — outbox.Publisher, который не отправляет ничего сам, а ставит
+// доставки в очередь webhook_deliveries: по одной на каждую подходящую
+// подписку. Повторная публикация того же события идемпотентна (UNIQUE).
+type Dispatcher struct {
+	db DB
+}
+
+var _ outbox.Publisher = (*Dispatcher)(nil)
+
+func NewDispatcher(db DB) *Dispatcher {
+	return &Dispatcher{db: db}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, msg outbox.Message) error {
+	if msg.Type != string(entity.EventMoneyTransferred) {
+		return nil
+	}
+
+	var transfer entity.Transfer
+	if err := json.Unmarshal(msg.Payload, &transfer); err != nil {
+		return fmt.Errorf("dispatch event %d: decode transfer: %w", msg.ID, err)
+	}
+
+	if err := d.enqueue(ctx, msg.ID, entity.EventTransferDebited, entity.TransferNotification{
+		AccountID:      transfer.FromAccountID,
+		CounterpartyID: transfer.ToAccountID,
+		Amount:         transfer.Amount,
+	}); err != nil {
+		return err
+	}
+
+	return d.enqueue(ctx, msg.ID, entity.EventTransferCredited, entity.TransferNotification{
+		AccountID:      transfer.ToAccountID,
+		CounterpartyID: transfer.FromAccountID,
+		Amount:         transfer.Amount,
+	})
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, eventID int64, eventType entity.EventType, n entity.TransferNotification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("dispatch event %d: marshal %s: %w", eventID, eventType, err)
+	}
+
+	_, err = d.db.Exec(ctx, `
+		INSERT INTO webhook_deliveries(subscription_id, event_id, event_type, payload)
+		SELECT s.id, $1, $2, $3
+		FROM webhook_subscriptions s
+		WHERE s.account_id = $4 AND s.enabled AND $2 = ANY(s.event_types)
+		ON CONFLICT (subscription_id, event_id, event_type) DO NOTHING
+	`, eventID, string(eventType), payload, n.AccountID)
+	if err != nil {
+		return fmt.Errorf("dispatch event %d: enqueue %s: %w", eventID, eventType, err)
+	}
+
+	return nil
+}
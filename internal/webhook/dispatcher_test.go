@@ -0,0 +1,60 @@
+package webhook_test
+
+import (
+	"clean-arch-template/internal/outbox"
+	"clean-arch-template/internal/webhook"
+	"context"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockDB(t *testing.T) pgxmock.PgxConnIface {
+	t.Helper()
+
+	mockDb, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDb.Close(context.Background()) })
+
+	return mockDb
+}
+
+func TestDispatcherEnqueuesBothSidesOfTransfer(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	dispatcher := webhook.NewDispatcher(mockDb)
+
+	mockDb.ExpectExec("INSERT INTO webhook_deliveries").
+		WithArgs(int64(7), "transfer.debited", []byte(`{"account_id":1,"counterparty_id":2,"amount":100}`), int64(1)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockDb.ExpectExec("INSERT INTO webhook_deliveries").
+		WithArgs(int64(7), "transfer.credited", []byte(`{"account_id":2,"counterparty_id":1,"amount":100}`), int64(2)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+	err := dispatcher.Publish(context.Background(), outbox.Message{
+		ID:      7,
+		Type:    "transfer.completed",
+		Payload: []byte(`{"from_account_id":1,"to_account_id":2,"amount":100}`),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestDispatcherIgnoresOtherEvents(t *testing.T) {
+	t.Parallel()
+
+	// Мок без ожиданий: любой запрос провалит тест.
+	mockDb := newMockDB(t)
+
+	err := webhook.NewDispatcher(mockDb).Publish(context.Background(), outbox.Message{
+		ID:      8,
+		Type:    "user.created",
+		Payload: []byte(`{"id":1,"name":"test"}`),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"clean-arch-template/pkg/logger"
+	"net/http"
+	"time"
+)
+
+// Option -.
+type Option func(*Worker)
+
+// BatchSize — сколько доставок Worker забирает за один проход.
+func BatchSize(size int) Option {
+	return func(w *Worker) {
+		w.batchSize = size
+	}
+}
+
+// PollInterval — пауза между проходами, когда очередь пуста.
+func PollInterval(interval time.Duration) Option {
+	return func(w *Worker) {
+		w.pollInterval = interval
+	}
+}
+
+// MaxAttempts — после стольких неудачных попыток доставка получает статус failed.
+func MaxAttempts(attempts int) Option {
+	return func(w *Worker) {
+		w.maxAttempts = attempts
+	}
+}
+
+// DisableAfter — после стольких неудачных доставок подряд подписка выключается.
+func DisableAfter(failures int) Option {
+	return func(w *Worker) {
+		w.disableAfter = failures
+	}
+}
+
+// ClaimLease — на сколько захваченная пачка скрыта от других реплик. Меньше
+// двух бюджетов доставки (2 × таймаут клиента) не опускается.
+func ClaimLease(lease time.Duration) Option {
+	return func(w *Worker) {
+		w.claimLease = lease
+	}
+}
+
+// WithHTTPClient задаёт клиент отправки (таймаут, транспорт). По умолчанию —
+// netguard.NewHTTPClient, который не соединяется с внутренними адресами.
+func WithHTTPClient(client *http.Client) Option {
+	return func(w *Worker) {
+		w.client = client
+	}
+}
+
+// WithLogger -.
+func WithLogger(l logger.Logger) Option {
+	return func(w *Worker) {
+		w.log = l
+	}
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"event_id":1}`)
+	signature := Sign("0123456789abcdef", now, body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	require.NoError(t, Verify("0123456789abcdef", timestamp, signature, body, 5*time.Minute, now))
+
+	assert.ErrorIs(t, Verify("another-secret!!", timestamp, signature, body, 5*time.Minute, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("0123456789abcdef", timestamp, signature, []byte(`{"event_id":2}`), 5*time.Minute, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("0123456789abcdef", timestamp, signature, body, 5*time.Minute, now.Add(time.Hour)), ErrStaleTimestamp,
+		"перехваченная доставка не должна приниматься вне окна tolerance")
+}
+
+func TestSignKnownVector(t *testing.T) {
+	t.Parallel()
+
+	// printf '0.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"sha256=4bbe8d39de1ffea42cffafb4727e02f761bef8ed2886b51575ce417d542e918f",
+		Sign("secret", time.Unix(0, 0), []byte(`{}`)))
+}
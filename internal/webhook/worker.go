@@ -0,0 +1,378 @@
+package webhook
+
+import (
+	"bytes"
+	"clean-arch-template/pkg/backoff"
+	"clean-arch-template/pkg/logger"
+	"clean-arch-template/pkg/netguard"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	_defaultBatchSize    = 50
+	_defaultPollInterval = time.Second
+	_defaultMaxAttempts  = 8
+	_defaultDisableAfter = 20
+	_defaultTimeout      = 5 * time.Second
+
+	// _defaultClaimLease — как и у outbox relay: захваченная доставка скрыта от
+	// других реплик, пока идёт HTTP-запрос; упавшая реплика вернёт её в оборот.
+	_defaultClaimLease = time.Minute
+
+	baseRetryDelay = 10 * time.Second
+	maxRetryDelay  = time.Hour
+
+	// maxErrorBodySize — сколько тела ответа партнёра сохраняется в журнал попыток.
+	maxErrorBodySize = 512
+)
+
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// DB — подмножество pgxpool.Pool, нужное Dispatcher и Worker.
+type DB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Delivery — захваченная доставка вместе с адресом и секретом подписки.
+type Delivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        int64
+	EventType      string
+	Payload        json.RawMessage
+	Attempts       int
+	URL            string
+	Secret         string
+}
+
+// Envelope — тело запроса к партнёру.
+type Envelope struct {
+	EventID   int64           `json:"event_id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Worker доставляет очередь webhook_deliveries: подписанный POST на URL
+// подписки, журнал каждой попытки в webhook_delivery_attempts, ретраи с
+// экспоненциальным backoff и выключение подписки после DisableAfter
+// неудачных доставок подряд.
+type Worker struct {
+	db     DB
+	client *http.Client
+
+	batchSize    int
+	pollInterval time.Duration
+	maxAttempts  int
+	disableAfter int
+	claimLease   time.Duration
+
+	log logger.Logger
+	now func() time.Time
+}
+
+func NewWorker(db DB, opts ...Option) *Worker {
+	w := &Worker{
+		db:           db,
+		client:       netguard.NewHTTPClient(_defaultTimeout),
+		batchSize:    _defaultBatchSize,
+		pollInterval: _defaultPollInterval,
+		maxAttempts:  _defaultMaxAttempts,
+		disableAfter: _defaultDisableAfter,
+		claimLease:   _defaultClaimLease,
+		log:          logger.Nop(),
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Run крутит цикл доставки до отмены ctx; ошибки прохода логируются и не
+// останавливают цикл.
+func (w *Worker) Run(ctx context.Context) {
+	for {
+		n, err := w.ProcessBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.log.Error(ctx, "webhook: process batch", "error", err.Error())
+		}
+
+		if err == nil && n == w.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// ProcessBatch захватывает до batchSize готовых доставок по включённым
+// подпискам и отправляет их по одной. Возвращает число захваченных доставок.
+//
+// Как и outbox relay, доставку начинаем, только если до конца lease хватает
+// бюджета на запрос и запись результата; остаток пачки возвращается в очередь.
+func (w *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	lease := w.lease()
+	deadline := time.Now().Add(lease)
+
+	deliveries, err := w.claim(ctx, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, d := range deliveries {
+		if ctx.Err() != nil {
+			return len(deliveries), ctx.Err()
+		}
+
+		if time.Until(deadline) < w.deliveryBudget() {
+			return len(deliveries), w.release(ctx, deliveries[i:])
+		}
+
+		if err := w.deliver(ctx, d); err != nil {
+			return len(deliveries), err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// deliveryBudget — сколько lease должно оставаться, чтобы начать доставку:
+// таймаут HTTP-клиента плюс такой же запас на запись попытки.
+func (w *Worker) deliveryBudget() time.Duration {
+	timeout := w.client.Timeout
+	if timeout <= 0 {
+		timeout = _defaultTimeout
+	}
+
+	return 2 * timeout
+}
+
+// lease — claimLease, но не меньше двух бюджетов доставки.
+func (w *Worker) lease() time.Duration {
+	return max(w.claimLease, 2*w.deliveryBudget())
+}
+
+func (w *Worker) claim(ctx context.Context, lease time.Duration) ([]Delivery, error) {
+	raw, err := w.db.Query(ctx, `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT wd.id
+			FROM webhook_deliveries wd
+			JOIN webhook_subscriptions ws ON ws.id = wd.subscription_id
+			WHERE wd.status = 'pending' AND wd.next_attempt_at <= CURRENT_TIMESTAMP AND ws.enabled
+			ORDER BY wd.id
+			LIMIT $1
+			FOR UPDATE OF wd SKIP LOCKED
+		)
+		RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.attempts, s.url, s.secret
+	`, w.batchSize, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	deliveries, err := pgx.CollectRows(raw, func(row pgx.CollectableRow) (Delivery, error) {
+		var d Delivery
+		err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Attempts, &d.URL, &d.Secret)
+		return d, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// release возвращает неотправленный остаток пачки в очередь, не дожидаясь
+// истечения lease; попытка при этом не засчитывается.
+func (w *Worker) release(ctx context.Context, deliveries []Delivery) error {
+	ids := make([]int64, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+	}
+
+	_, err := w.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($1) AND status = 'pending'
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("release %d webhook deliveries: %w", len(ids), err)
+	}
+
+	w.log.Warn(ctx, "webhook: batch ran out of lease, remaining deliveries released",
+		"released", len(ids), "lease", w.lease().String(), "timeout", w.client.Timeout.String())
+
+	return nil
+}
+
+// attemptResult — итог одного HTTP-запроса для журнала попыток.
+type attemptResult struct {
+	statusCode int
+	err        error
+	duration   time.Duration
+}
+
+func (w *Worker) deliver(ctx context.Context, d Delivery) error {
+	result := w.send(ctx, d)
+
+	attempt := d.Attempts + 1
+	status := StatusDelivered
+	if result.err != nil {
+		status = StatusPending
+		if attempt >= w.maxAttempts {
+			status = StatusFailed
+		}
+	}
+
+	disabled, err := w.record(ctx, d, attempt, status, result)
+	if err != nil {
+		return err
+	}
+
+	args := []any{
+		"delivery_id", d.ID, "subscription_id", d.SubscriptionID, "event_id", d.EventID,
+		"event_type", d.EventType, "attempt", attempt, "status_code", result.statusCode,
+		"duration_ms", result.duration.Milliseconds(),
+	}
+
+	switch status {
+	case StatusDelivered:
+		w.log.Info(ctx, "webhook: delivered", args...)
+	case StatusFailed:
+		w.log.Error(ctx, "webhook: delivery failed permanently", append(args, "error", result.err.Error())...)
+	default:
+		w.log.Warn(ctx, "webhook: delivery attempt failed, will retry", append(args, "error", result.err.Error())...)
+	}
+
+	if disabled {
+		w.log.Warn(ctx, "webhook: subscription disabled after consecutive failures",
+			"subscription_id", d.SubscriptionID, "failures", w.disableAfter)
+	}
+
+	return nil
+}
+
+// send выполняет один подписанный POST. Успех — только 2xx; редиректы не
+// следуют за партнёром, чтобы подпись не уходила на чужой адрес.
+func (w *Worker) send(ctx context.Context, d Delivery) attemptResult {
+	body, err := json.Marshal(Envelope{
+		EventID:   d.EventID,
+		Type:      d.EventType,
+		CreatedAt: w.now().UTC(),
+		Data:      d.Payload,
+	})
+	if err != nil {
+		return attemptResult{err: fmt.Errorf("marshal envelope: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return attemptResult{err: fmt.Errorf("build request: %w", err)}
+	}
+
+	timestamp := w.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderEventID, strconv.FormatInt(d.EventID, 10))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, body))
+
+	client := *w.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return attemptResult{err: err, duration: duration}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return attemptResult{
+			statusCode: resp.StatusCode,
+			err:        fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet)),
+			duration:   duration,
+		}
+	}
+
+	// Дочитываем тело, чтобы соединение вернулось в keep-alive пул.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+
+	return attemptResult{statusCode: resp.StatusCode, duration: duration}
+}
+
+// record одним запросом пишет попытку в журнал, обновляет доставку и счётчик
+// неудач подписки; возвращает true, если этой попыткой подписка выключена.
+func (w *Worker) record(ctx context.Context, d Delivery, attempt int, status string, result attemptResult) (bool, error) {
+	var (
+		statusCode *int
+		errText    *string
+	)
+	if result.statusCode != 0 {
+		statusCode = &result.statusCode
+	}
+	if result.err != nil {
+		text := result.err.Error()
+		errText = &text
+	}
+
+	var disabled bool
+	err := w.db.QueryRow(ctx, `
+		WITH attempt AS (
+			INSERT INTO webhook_delivery_attempts(delivery_id, attempt, status_code, error, duration_ms)
+			VALUES ($1, $2, $3, $4, $5)
+		), delivery AS (
+			UPDATE webhook_deliveries
+			SET status = $6,
+			    attempts = $2,
+			    next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $7),
+			    delivered_at = CASE WHEN $6 = 'delivered' THEN CURRENT_TIMESTAMP END
+			WHERE id = $1
+		)
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = CASE WHEN $6 = 'delivered' THEN 0 ELSE consecutive_failures + 1 END,
+		    enabled = enabled AND ($6 = 'delivered' OR consecutive_failures + 1 < $9),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING $6 <> 'delivered' AND NOT enabled
+	`, d.ID, attempt, statusCode, errText, result.duration.Milliseconds(), status,
+		backoff.Exponential(attempt, baseRetryDelay, maxRetryDelay).Seconds(), d.SubscriptionID, w.disableAfter,
+	).Scan(&disabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Подписку удалили между захватом и записью: доставки ушли каскадом.
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("record webhook delivery %d attempt %d: %w", d.ID, attempt, err)
+	}
+
+	return disabled, nil
+}
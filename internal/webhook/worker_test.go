@@ -0,0 +1,212 @@
+package webhook_test
+
+import (
+	"clean-arch-template/internal/webhook"
+	"clean-arch-template/pkg/logger/loggertest"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef"
+
+func claimedDeliveries(url string, attempts int) *pgxmock.Rows {
+	return pgxmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "payload", "attempts", "url", "secret"}).
+		AddRow(int64(11), int64(3), int64(7), "transfer.debited",
+			[]byte(`{"account_id":1,"counterparty_id":2,"amount":100}`), attempts, url, testSecret)
+}
+
+func TestWorkerDeliversSignedRequest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gotBody   []byte
+		gotHeader http.Header
+	)
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeader = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(partner.Close)
+
+	mockDb := newMockDB(t)
+	fakeLog := &loggertest.Fake{}
+	worker := webhook.NewWorker(mockDb, webhook.BatchSize(10), webhook.WithHTTPClient(partner.Client()),
+		webhook.WithLogger(fakeLog))
+
+	mockDb.ExpectQuery("UPDATE webhook_deliveries").
+		WithArgs(10, pgxmock.AnyArg()).
+		WillReturnRows(claimedDeliveries(partner.URL, 0))
+	mockDb.ExpectQuery("INSERT INTO webhook_delivery_attempts").
+		WithArgs(int64(11), 1, pgxmock.AnyArg(), (*string)(nil), pgxmock.AnyArg(),
+			webhook.StatusDelivered, pgxmock.AnyArg(), int64(3), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"disabled"}).AddRow(false))
+
+	n, err := worker.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.NoError(t, webhook.Verify(testSecret,
+		gotHeader.Get(webhook.HeaderTimestamp), gotHeader.Get(webhook.HeaderSignature),
+		gotBody, time.Minute, time.Now()))
+	assert.Equal(t, "transfer.debited", gotHeader.Get(webhook.HeaderEvent))
+	assert.Equal(t, "7", gotHeader.Get(webhook.HeaderEventID))
+	assert.Equal(t, "11", gotHeader.Get(webhook.HeaderDelivery))
+
+	var envelope webhook.Envelope
+	require.NoError(t, json.Unmarshal(gotBody, &envelope))
+	assert.Equal(t, int64(7), envelope.EventID)
+	assert.JSONEq(t, `{"account_id":1,"counterparty_id":2,"amount":100}`, string(envelope.Data))
+
+	require.Len(t, fakeLog.Entries, 1)
+	assert.Equal(t, "INFO", fakeLog.Entries[0].Level)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestWorkerSchedulesRetryOnFailure(t *testing.T) {
+	t.Parallel()
+
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(partner.Close)
+
+	mockDb := newMockDB(t)
+	fakeLog := &loggertest.Fake{}
+	worker := webhook.NewWorker(mockDb, webhook.MaxAttempts(3), webhook.WithHTTPClient(partner.Client()),
+		webhook.WithLogger(fakeLog))
+
+	mockDb.ExpectQuery("UPDATE webhook_deliveries").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(claimedDeliveries(partner.URL, 0))
+	// Первая неудача из трёх: доставка остаётся pending, пауза — базовая (10s).
+	mockDb.ExpectQuery("INSERT INTO webhook_delivery_attempts").
+		WithArgs(int64(11), 1, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			webhook.StatusPending, float64(10), int64(3), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"disabled"}).AddRow(false))
+
+	_, err := worker.ProcessBatch(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, fakeLog.Entries, 1)
+	assert.Equal(t, "WARN", fakeLog.Entries[0].Level)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestWorkerFailsDeliveryAndReportsDisabledSubscription(t *testing.T) {
+	t.Parallel()
+
+	mockDb := newMockDB(t)
+	fakeLog := &loggertest.Fake{}
+
+	// Недоступный адрес: ошибка соединения тоже считается неудачной попыткой.
+	partner := httptest.NewServer(http.NotFoundHandler())
+	url := partner.URL
+	partner.Close()
+
+	worker := webhook.NewWorker(mockDb, webhook.MaxAttempts(3), webhook.DisableAfter(5),
+		webhook.WithHTTPClient(&http.Client{}), webhook.WithLogger(fakeLog))
+
+	mockDb.ExpectQuery("UPDATE webhook_deliveries").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(claimedDeliveries(url, 2))
+	mockDb.ExpectQuery("INSERT INTO webhook_delivery_attempts").
+		WithArgs(int64(11), 3, (*int)(nil), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			webhook.StatusFailed, pgxmock.AnyArg(), int64(3), 5).
+		WillReturnRows(pgxmock.NewRows([]string{"disabled"}).AddRow(true))
+
+	_, err := worker.ProcessBatch(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, fakeLog.Entries, 2)
+	assert.Equal(t, "ERROR", fakeLog.Entries[0].Level)
+	assert.Equal(t, "WARN", fakeLog.Entries[1].Level)
+	assert.Contains(t, fakeLog.Entries[1].Msg, "subscription disabled")
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestWorkerReleasesDeliveriesThatDoNotFitLease(t *testing.T) {
+	t.Parallel()
+
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(80 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(partner.Close)
+
+	client := partner.Client()
+	client.Timeout = 100 * time.Millisecond
+
+	mockDb := newMockDB(t)
+	fakeLog := &loggertest.Fake{}
+	// lease = 4 × таймаут = 400ms; бюджет доставки — 200ms: доставки
+	// начинаются на 0, 80 и 160ms, на 240ms осталось 160ms — остаток в очередь.
+	worker := webhook.NewWorker(mockDb, webhook.ClaimLease(0), webhook.WithHTTPClient(client),
+		webhook.WithLogger(fakeLog))
+
+	rows := pgxmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "payload", "attempts", "url", "secret"})
+	for id := int64(1); id <= 5; id++ {
+		rows.AddRow(id, int64(3), id, "transfer.debited", []byte(`{}`), 0, partner.URL, testSecret)
+	}
+	mockDb.ExpectQuery("UPDATE webhook_deliveries").
+		WithArgs(pgxmock.AnyArg(), 0.4).
+		WillReturnRows(rows)
+	for id := int64(1); id <= 3; id++ {
+		mockDb.ExpectQuery("INSERT INTO webhook_delivery_attempts").
+			WithArgs(id, 1, pgxmock.AnyArg(), (*string)(nil), pgxmock.AnyArg(),
+				webhook.StatusDelivered, pgxmock.AnyArg(), int64(3), pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"disabled"}).AddRow(false))
+	}
+	mockDb.ExpectExec("UPDATE webhook_deliveries SET next_attempt_at = CURRENT_TIMESTAMP").
+		WithArgs([]int64{4, 5}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	n, err := worker.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	require.NotEmpty(t, fakeLog.Entries)
+	last := fakeLog.Entries[len(fakeLog.Entries)-1]
+	assert.Equal(t, "WARN", last.Level)
+	assert.Contains(t, last.Msg, "ran out of lease")
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestWorkerRefusesInternalAddressByDefault(t *testing.T) {
+	t.Parallel()
+
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("request reached internal address")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(partner.Close)
+
+	mockDb := newMockDB(t)
+	worker := webhook.NewWorker(mockDb, webhook.MaxAttempts(3))
+
+	mockDb.ExpectQuery("UPDATE webhook_deliveries").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(claimedDeliveries(partner.URL, 0))
+	mockDb.ExpectQuery("INSERT INTO webhook_delivery_attempts").
+		WithArgs(int64(11), 1, (*int)(nil), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			webhook.StatusPending, pgxmock.AnyArg(), int64(3), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"disabled"}).AddRow(false))
+
+	_, err := worker.ProcessBatch(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
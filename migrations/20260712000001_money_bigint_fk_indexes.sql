@@ -1,4 +1,5 @@
 -- +goose Up
+-- migrate:destructive повторное применение умножает балансы на 100 (см. README, baseline)
 -- Деньги переводятся в BIGINT (минимальные единицы валюты, 100 центов = 1$):
 -- целочисленная арифметика без ошибок округления двоичной запятой.
 ALTER TABLE users
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Transactional outbox: события пишутся в той же транзакции, что и изменение
+-- данных, и доставляются relay-воркером (internal/outbox) at-least-once.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           BIGSERIAL PRIMARY KEY,
+    event_type   VARCHAR(255)             NOT NULL,
+    aggregate_id BIGINT                   NOT NULL,
+    payload      JSONB                    NOT NULL,
+    -- pending → published | dead (исчерпаны попытки доставки)
+    status       VARCHAR(16)              NOT NULL DEFAULT 'pending',
+    attempts     INT                      NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Частичный индекс под выборку relay: опубликованные строки в него не попадают.
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (available_at, id) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE outbox;
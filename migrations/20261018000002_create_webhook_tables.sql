@@ -0,0 +1,56 @@
+-- +goose Up
+-- Подписки партнёров на события по своему счёту.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id                   BIGSERIAL PRIMARY KEY,
+    account_id           BIGINT                   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    url                  TEXT                     NOT NULL,
+    event_types          TEXT[]                   NOT NULL,
+    secret               TEXT                     NOT NULL,
+    enabled              BOOLEAN                  NOT NULL DEFAULT TRUE,
+    -- Сбрасывается успешной доставкой; по достижении порога подписка выключается.
+    consecutive_failures INT                      NOT NULL DEFAULT 0,
+    created_at           TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at           TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_account_id ON webhook_subscriptions (account_id) WHERE enabled;
+
+-- Очередь доставок: одна строка на пару (подписка, событие).
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT                   NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        BIGINT                   NOT NULL, -- outbox.id: ключ идемпотентности для получателя
+    event_type      VARCHAR(255)             NOT NULL,
+    payload         JSONB                    NOT NULL,
+    -- pending → delivered | failed (исчерпаны попытки)
+    status          VARCHAR(16)              NOT NULL DEFAULT 'pending',
+    attempts        INT                      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at    TIMESTAMP WITH TIME ZONE,
+    -- Повторная публикация события outbox (at-least-once) не плодит дублей.
+    UNIQUE (subscription_id, event_id, event_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at, id) WHERE status = 'pending';
+
+-- Журнал попыток: каждая отправка, успешная или нет.
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts
+(
+    id          BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT                   NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    attempt     INT                      NOT NULL,
+    status_code INT,
+    error       TEXT,
+    duration_ms INT                      NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id);
+
+-- +goose Down
+DROP TABLE webhook_delivery_attempts;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
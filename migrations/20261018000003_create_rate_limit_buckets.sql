@@ -0,0 +1,15 @@
+-- +goose Up
+-- Состояние token bucket для RATE_LIMIT_BACKEND=postgres. UNLOGGED: таблица
+-- не пишется в WAL и не реплицируется — после падения сервера лимиты просто
+-- начинаются заново, это дешевле, чем журналировать каждый запрос.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets
+(
+    key        TEXT PRIMARY KEY,
+    tokens     DOUBLE PRECISION         NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS rate_limit_buckets;
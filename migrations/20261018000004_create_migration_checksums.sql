@@ -0,0 +1,13 @@
+-- +goose Up
+-- SHA-256 применённых SQL-миграций (internal/migrate): правка уже
+-- применённого файла обнаруживается при старте, а не расходится молча
+-- со схемой в БД.
+CREATE TABLE IF NOT EXISTS schema_migration_checksums
+(
+    version     BIGINT PRIMARY KEY,
+    checksum    TEXT                     NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS schema_migration_checksums;
@@ -0,0 +1,9 @@
+// Package migrations встраивает SQL-миграции в бинарь: схема едет вместе с
+// кодом, который на неё рассчитан, и не зависит от рабочей директории.
+// MIGRATIONS_DIR перекрывает встроенный набор при разработке.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,18 @@
+// Package backoff — общая политика пауз между повторными попытками.
+package backoff
+
+import "time"
+
+// Exponential возвращает паузу перед попыткой attempt+1: base, 2·base,
+// 4·base, … с потолком limit. attempt < 1 трактуется как первая попытка.
+func Exponential(attempt int, base, limit time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= limit {
+			return limit
+		}
+	}
+
+	return min(delay, limit)
+}
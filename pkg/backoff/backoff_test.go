@@ -0,0 +1,28 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponential(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 10, want: time.Minute},
+		{attempt: 1000, want: time.Minute},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, Exponential(tc.attempt, time.Second, time.Minute), "attempt %d", tc.attempt)
+	}
+}
@@ -0,0 +1,19 @@
+// Package cache — кэш значений по строковому ключу за интерфейсом Cache:
+// LRU с TTL в памяти процесса, общий бэкенд (Redis и т. п.) подключается
+// отдельной реализацией без изменений у потребителей.
+package cache
+
+import "context"
+
+// Cache хранит значения V по ключу. Ошибок у методов нет: сбой бэкенда для
+// потребителя — промах (Get) или пропущенная запись (Set), значение всегда
+// можно получить из источника. Реализация логирует сбои сама.
+type Cache[V any] interface {
+	// Get возвращает значение, если оно есть и не истекло.
+	Get(ctx context.Context, key string) (V, bool)
+	Set(ctx context.Context, key string, value V)
+	Delete(ctx context.Context, keys ...string)
+	// Clear удаляет все значения: после потери уведомлений об инвалидации
+	// неизвестно, какие из них устарели.
+	Clear(ctx context.Context)
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := NewLRU[int](2, 0)
+
+	c.Set(ctx, "a", 1)
+	c.Set(ctx, "b", 2)
+	_, _ = c.Get(ctx, "a") // b становится самым давним
+	c.Set(ctx, "c", 3)
+
+	_, ok := c.Get(ctx, "b")
+	assert.False(t, ok, "вытеснено давно не читанное")
+	v, ok := c.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 2, c.Len())
+
+	c.Set(ctx, "a", 10)
+	v, _ = c.Get(ctx, "a")
+	assert.Equal(t, 10, v, "Set существующего ключа заменяет значение")
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRUExpiresAfterTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	c := NewLRU[string](10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "k", "v")
+	now = now.Add(59 * time.Second)
+	v, ok := c.Get(ctx, "k")
+	assert.True(t, ok)
+	assert.Equal(t, "v", v)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get(ctx, "k")
+	assert.False(t, ok, "ровно через ttl значение истекло")
+	assert.Zero(t, c.Len(), "истёкшее удаляется при чтении")
+}
+
+func TestLRUDeleteAndClear(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := NewLRU[int](10, time.Minute)
+
+	c.Set(ctx, "a", 1)
+	c.Set(ctx, "b", 2)
+	c.Set(ctx, "c", 3)
+
+	c.Delete(ctx, "a", "missing")
+	_, ok := c.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	c.Clear(ctx)
+	assert.Zero(t, c.Len())
+	_, ok = c.Get(ctx, "b")
+	assert.False(t, ok)
+}
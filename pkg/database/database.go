@@ -15,8 +15,8 @@ const (
 	_defaultMaxPoolSize       = 4
 	_defaultMinPoolSize       = 1
 	_defaultConnAttempts      = 10
-	_defaultConnTimeout       = 1
-	_defaultHealthCheckPeriod = 1
+	_defaultConnTimeout       = time.Second
+	_defaultHealthCheckPeriod = time.Minute
 )
 
 // Postgres -.
@@ -25,26 +25,42 @@ type (
 		maxPoolSize       int32
 		minPoolSize       int32
 		connAttempts      int32
-		connTimeout       int
-		healthCheckPeriod int
+		connTimeout       time.Duration
+		healthCheckPeriod time.Duration
+		queryTracing      bool
+
+		timeouts Timeouts
+
+		txMaxAttempts int
+		txBaseDelay   time.Duration
+		txMaxDelay    time.Duration
+
+		replicaMaxLag      time.Duration
+		replicaCheckPeriod time.Duration
+		replicas           *replicaSet
 
 		logger logger.Logger
 
 		Pool       *pgxpool.Pool
-		Transactor *tx.Transactor
+		Transactor Transactor
 		DBGetter   tx.DBGetter
+		// ReadDBGetter — для методов только на чтение: реплика в ротации, а
+		// внутри транзакции, с WithPrimary или без живых реплик — как DBGetter.
+		ReadDBGetter tx.DBGetter
 	}
 )
 
 // New -.
 func New(cfg *config.Config, opts ...Option) (*Postgres, error) {
 	pg := &Postgres{
-		maxPoolSize:       _defaultMaxPoolSize,
-		minPoolSize:       _defaultMinPoolSize,
-		connAttempts:      _defaultConnAttempts,
-		connTimeout:       _defaultConnTimeout,
-		healthCheckPeriod: _defaultHealthCheckPeriod,
-		logger:            logger.Nop(),
+		maxPoolSize:        _defaultMaxPoolSize,
+		minPoolSize:        _defaultMinPoolSize,
+		connAttempts:       _defaultConnAttempts,
+		connTimeout:        _defaultConnTimeout,
+		healthCheckPeriod:  _defaultHealthCheckPeriod,
+		replicaMaxLag:      _defaultReplicaMaxLag,
+		replicaCheckPeriod: _defaultReplicaCheckPeriod,
+		logger:             logger.Nop(),
 	}
 
 	// Custom options
@@ -68,7 +84,7 @@ func New(cfg *config.Config, opts ...Option) (*Postgres, error) {
 
 		pg.logger.Debug(context.Background(), fmt.Sprintf("Postgres is trying to connect to %s, attempts left: %d", cfg.DBHost, pg.connAttempts))
 
-		time.Sleep(time.Duration(pg.connTimeout) * time.Second)
+		time.Sleep(pg.connTimeout)
 
 		pg.connAttempts--
 	}
@@ -80,13 +96,83 @@ func New(cfg *config.Config, opts ...Option) (*Postgres, error) {
 	// will use dbGetter in repositories
 	// DBGetter is used to get the current DB handler from the context.
 	// It returns the current transaction if there is one, otherwise it will return the original DB.
-	pg.Transactor, pg.DBGetter = tx.NewTransactorFromPool(pg.Pool)
+	// optionsPool — чтобы WithTxOptions доходил до BEGIN.
+	pg.Transactor, pg.DBGetter = tx.NewTransactor(optionsPool{pg.Pool})
+	if pg.queryTracing {
+		pg.Transactor = NewTracedTransactor(pg.Transactor)
+	}
+	// Повторы — снаружи трейсинга: каждая попытка — свой спан db.transaction.
+	pg.Transactor, err = NewRetryingTransactor(pg.Transactor, pg.txMaxAttempts, pg.txBaseDelay, pg.txMaxDelay)
+	if err != nil {
+		pg.Close()
+		return nil, fmt.Errorf("postgres - New - retrying transactor: %w", err)
+	}
+
+	if err := pg.setupReplicas(cfg); err != nil {
+		pg.Close()
+		return nil, err
+	}
+	pg.ReadDBGetter = pg.readDB
 
 	return pg, nil
 }
 
+// setupReplicas создаёт пулы реплик из cfg.ReplicaHosts с настройками пула
+// primary. Пулы подключаются лениво: недоступная реплика не мешает старту, а
+// остаётся вне ротации до успешной проверки.
+func (p *Postgres) setupReplicas(cfg *config.Config) error {
+	if len(cfg.ReplicaHosts) == 0 {
+		return nil
+	}
+
+	set := &replicaSet{
+		maxLag:  p.replicaMaxLag,
+		period:  p.replicaCheckPeriod,
+		timeout: max(p.connTimeout, time.Second),
+		log:     p.logger,
+	}
+	p.replicas = set
+
+	for _, host := range cfg.ReplicaHosts {
+		dsn, err := cfg.ReplicaDSN(host)
+		if err != nil {
+			return fmt.Errorf("postgres - New - replica: %w", err)
+		}
+
+		poolConfig, err := pgxpool.ParseConfig(dsn)
+		if err != nil {
+			return fmt.Errorf("postgres - New - replica %s - pgxpool.ParseConfig: %w", host, err)
+		}
+		setupPoolConfig(cfg, p, poolConfig)
+
+		pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+		if err != nil {
+			return fmt.Errorf("postgres - New - replica %s: %w", host, err)
+		}
+
+		set.replicas = append(set.replicas, &replica{host: host, db: pool, probe: poolProbe(pool)})
+	}
+
+	set.start()
+
+	return nil
+}
+
+func (p *Postgres) readDB(ctx context.Context) tx.DB {
+	if p.replicas == nil || PrimaryForced(ctx) || tx.IsWithinTransaction(ctx) {
+		return p.DBGetter(ctx)
+	}
+	if r := p.replicas.pick(); r != nil {
+		return r.db
+	}
+	return p.DBGetter(ctx)
+}
+
 // Close -.
 func (p *Postgres) Close() {
+	if p.replicas != nil {
+		p.replicas.close()
+	}
 	if p.Pool != nil {
 		p.Pool.Close()
 	}
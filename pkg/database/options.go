@@ -1,6 +1,9 @@
 package database
 
-import "clean-arch-template/pkg/logger"
+import (
+	"clean-arch-template/pkg/logger"
+	"time"
+)
 
 // Option -.
 type Option func(*Postgres)
@@ -26,20 +29,63 @@ func ConnAttempts(attempts int32) Option {
 	}
 }
 
-// ConnTimeout -.
-func ConnTimeout(timeout int) Option {
+// ConnTimeout — таймаут установки соединения.
+func ConnTimeout(timeout time.Duration) Option {
 	return func(c *Postgres) {
 		c.connTimeout = timeout
 	}
 }
 
-// HealthCheckPeriod -.
-func HealthCheckPeriod(period int) Option {
+// HealthCheckPeriod — период проверки простаивающих соединений пула.
+func HealthCheckPeriod(period time.Duration) Option {
 	return func(c *Postgres) {
 		c.healthCheckPeriod = period
 	}
 }
 
+// QueryTracing включает OTel-спаны на запросы и транзакции.
+func QueryTracing(enabled bool) Option {
+	return func(c *Postgres) {
+		c.queryTracing = enabled
+	}
+}
+
+// SessionTimeouts — таймауты каждого соединения пула (и пулов реплик);
+// отдельная транзакция переопределяет их через WithTimeouts.
+func SessionTimeouts(t Timeouts) Option {
+	return func(c *Postgres) {
+		c.timeouts = t
+	}
+}
+
+// TxRetry — повторы транзакций при 40001/40P01/55P03: attempts включает
+// первую попытку (1 — без повторов), паузы — от base, удваиваясь, до maxDelay.
+func TxRetry(attempts int, base, maxDelay time.Duration) Option {
+	return func(c *Postgres) {
+		c.txMaxAttempts = attempts
+		c.txBaseDelay = base
+		c.txMaxDelay = maxDelay
+	}
+}
+
+// ReplicaMaxLag — порог отставания, после которого реплика выводится из ротации.
+func ReplicaMaxLag(lag time.Duration) Option {
+	return func(c *Postgres) {
+		if lag > 0 {
+			c.replicaMaxLag = lag
+		}
+	}
+}
+
+// ReplicaCheckPeriod — период проверки доступности и отставания реплик.
+func ReplicaCheckPeriod(period time.Duration) Option {
+	return func(c *Postgres) {
+		if period > 0 {
+			c.replicaCheckPeriod = period
+		}
+	}
+}
+
 // WithLogger -.
 func WithLogger(l logger.Logger) Option {
 	return func(c *Postgres) {
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "pgx"
+
+// Атрибуты спанов БД.
+const (
+	attrDBSystem       = attribute.Key("db.system")
+	attrDBStatement    = attribute.Key("db.statement")
+	attrDBRowsAffected = attribute.Key("db.rows_affected")
+	attrTxOutcome      = attribute.Key("db.transaction.outcome")
+)
+
+// maxStatementLen — длиннее в атрибут не пишем: db.statement уходит в каждый спан.
+const maxStatementLen = 2048
+
+// Transactor запускает функцию внутри транзакции; реализуют tx.Transactor и
+// TracedTransactor.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, txFunc func(ctx context.Context) error) error
+}
+
+// OTelQueryTracer — pgx.QueryTracer, создающий client-спан на каждый запрос.
+// Спан создаётся только внутри существующего трейса: фоновые опросы воркеров
+// и health-check пула без родителя порождали бы поток корневых трейсов.
+type OTelQueryTracer struct {
+	tracer trace.Tracer
+}
+
+var _ pgx.QueryTracer = (*OTelQueryTracer)(nil)
+
+func NewOTelQueryTracer() *OTelQueryTracer {
+	return &OTelQueryTracer{tracer: otel.Tracer(tracerName)}
+}
+
+// querySpanKey помечает спан, созданный трейсером: в TraceQueryEnd нельзя
+// брать trace.SpanFromContext — без своего спана там окажется родительский.
+type querySpanKey struct{}
+
+func (t *OTelQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	statement := SanitizeSQL(data.SQL)
+	ctx, span := t.tracer.Start(ctx, spanName(statement),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attrDBSystem.String("postgresql"),
+			attrDBStatement.String(statement),
+		),
+	)
+
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+func (t *OTelQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, ok := ctx.Value(querySpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+
+	span.SetAttributes(attrDBRowsAffected.Int64(data.CommandTag.RowsAffected()))
+}
+
+// TracedTransactor оборачивает Transactor спаном db.transaction с исходом
+// commit/rollback. Вложенный вызов (транзакция уже в ctx) спан не создаёт:
+// исход решает внешний.
+type TracedTransactor struct {
+	next   Transactor
+	tracer trace.Tracer
+}
+
+var _ Transactor = (*TracedTransactor)(nil)
+
+func NewTracedTransactor(next Transactor) *TracedTransactor {
+	return &TracedTransactor{next: next, tracer: otel.Tracer(tracerName)}
+}
+
+func (t *TracedTransactor) WithinTransaction(ctx context.Context, txFunc func(ctx context.Context) error) error {
+	if tx.IsWithinTransaction(ctx) {
+		return t.next.WithinTransaction(ctx, txFunc)
+	}
+
+	ctx, span := t.tracer.Start(ctx, "db.transaction",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrDBSystem.String("postgresql")),
+	)
+	defer span.End()
+
+	err := t.next.WithinTransaction(ctx, txFunc)
+	if err != nil {
+		// Ошибка txFunc или COMMIT — транзакция в любом случае откатена.
+		span.SetAttributes(attrTxOutcome.String("rollback"))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	span.SetAttributes(attrTxOutcome.String("commit"))
+
+	return nil
+}
+
+var (
+	sqlStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumericLiteral = regexp.MustCompile(`\$?\b\d+(?:\.\d+)?\b`)
+	sqlWhitespace     = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeSQL готовит текст запроса для db.statement: строковые и числовые
+// литералы заменяются на "?" (параметры $N остаются), пробелы схлопываются.
+// Аргументы запроса в спан не попадают вовсе.
+func SanitizeSQL(sql string) string {
+	sql = sqlStringLiteral.ReplaceAllString(sql, "?")
+	sql = sqlNumericLiteral.ReplaceAllStringFunc(sql, func(m string) string {
+		if strings.HasPrefix(m, "$") {
+			return m
+		}
+		return "?"
+	})
+	sql = strings.TrimSpace(sqlWhitespace.ReplaceAllString(sql, " "))
+
+	if len(sql) > maxStatementLen {
+		sql = strings.ToValidUTF8(sql[:maxStatementLen], "")
+	}
+
+	return sql
+}
+
+// spanName — первое слово запроса (SELECT, INSERT, WITH, ...), как советует
+// semconv: полный текст — в db.statement.
+func spanName(statement string) string {
+	op, _, _ := strings.Cut(statement, " ")
+	if op == "" {
+		return "db.query"
+	}
+
+	return strings.ToUpper(op)
+}
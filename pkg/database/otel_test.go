@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"SELECT id\n\t FROM users WHERE id = $1":                        "SELECT id FROM users WHERE id = $1",
+		"UPDATE users SET name = 'O''Brien' WHERE id = 42":              "UPDATE users SET name = ? WHERE id = ?",
+		"SELECT * FROM t1 LIMIT 10 OFFSET $2":                           "SELECT * FROM t1 LIMIT ? OFFSET $2",
+		"INSERT INTO orders(amount) VALUES (12.50), ($1)":               "INSERT INTO orders(amount) VALUES (?), ($1)",
+		"  SELECT pg_notify('account_activity', row_to_json(x)::text) ": "SELECT pg_notify(?, row_to_json(x)::text)",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, SanitizeSQL(in))
+	}
+}
+
+func newRecordingTracer() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+
+	return out
+}
+
+func TestOTelQueryTracer(t *testing.T) {
+	t.Parallel()
+
+	provider, recorder := newRecordingTracer()
+	tracer := &OTelQueryTracer{tracer: provider.Tracer("test")}
+
+	// Без родительского спана запрос не трейсится.
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+	assert.Empty(t, recorder.Ended())
+
+	parentCtx, parent := provider.Tracer("test").Start(context.Background(), "handler")
+
+	ctx = tracer.TraceQueryStart(parentCtx, nil, pgx.TraceQueryStartData{SQL: "update users set name = 'x' where id = $1", Args: []any{1}})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 1")})
+
+	ctx = tracer.TraceQueryStart(parentCtx, nil, pgx.TraceQueryStartData{SQL: "SELECT broken"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("syntax error")})
+	parent.End()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 3)
+
+	update := ended[0]
+	assert.Equal(t, "UPDATE", update.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), update.Parent().SpanID())
+	a := attrs(update)
+	assert.Equal(t, "postgresql", a[attrDBSystem].AsString())
+	assert.Equal(t, "update users set name = ? where id = $1", a[attrDBStatement].AsString())
+	assert.Equal(t, int64(1), a[attrDBRowsAffected].AsInt64())
+
+	failed := ended[1]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.NotContains(t, attrs(failed), attrDBRowsAffected)
+}
+
+type fakeTransactor struct{ commitErr error }
+
+func (f fakeTransactor) WithinTransaction(ctx context.Context, txFunc func(ctx context.Context) error) error {
+	if err := txFunc(ctx); err != nil {
+		return err
+	}
+
+	return f.commitErr
+}
+
+func TestTracedTransactorRecordsOutcome(t *testing.T) {
+	t.Parallel()
+
+	provider, recorder := newRecordingTracer()
+	newTransactor := func(commitErr error) *TracedTransactor {
+		return &TracedTransactor{next: fakeTransactor{commitErr: commitErr}, tracer: provider.Tracer("test")}
+	}
+	ok := func(context.Context) error { return nil }
+
+	require.NoError(t, newTransactor(nil).WithinTransaction(context.Background(), ok))
+	require.Error(t, newTransactor(nil).WithinTransaction(context.Background(), func(context.Context) error {
+		return errors.New("insufficient funds")
+	}))
+	require.Error(t, newTransactor(errors.New("commit failed")).WithinTransaction(context.Background(), ok))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 3)
+	assert.Equal(t, "commit", attrs(ended[0])[attrTxOutcome].AsString())
+	assert.Equal(t, "rollback", attrs(ended[1])[attrTxOutcome].AsString())
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Equal(t, "rollback", attrs(ended[2])[attrTxOutcome].AsString())
+}
@@ -5,7 +5,6 @@ import (
 	"clean-arch-template/pkg/logger"
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/multitracer"
@@ -15,18 +14,29 @@ import (
 func setupPoolConfig(cfg *config.Config, pg *Postgres, poolConfig *pgxpool.Config) {
 	poolConfig.MinConns = pg.minPoolSize // Keep warm connections for health checks and latency spikes
 	poolConfig.MaxConns = pg.maxPoolSize
-	poolConfig.ConnConfig.ConnectTimeout = time.Duration(pg.connTimeout) * time.Second
-	poolConfig.HealthCheckPeriod = time.Duration(pg.healthCheckPeriod) * time.Minute
+	poolConfig.ConnConfig.ConnectTimeout = pg.connTimeout
+	poolConfig.HealthCheckPeriod = pg.healthCheckPeriod
+	// Таймауты — параметрами старта сессии: действуют и вне транзакций, без
+	// лишнего SET на каждое соединение.
+	for _, p := range pg.timeouts.params() {
+		poolConfig.ConnConfig.RuntimeParams[p[0]] = p[1]
+	}
+
+	// Трейсеры независимы: спаны — по queryTracing, логи запросов — по Debug.
+	// OTel идёт первым, чтобы лог запроса уже нёс span_id его спана.
+	tracer := &multitracer.Tracer{}
+	if pg.queryTracing {
+		tracer.QueryTracers = append(tracer.QueryTracers, NewOTelQueryTracer())
+	}
+	if cfg.Debug {
+		tracer.QueryTracers = append(tracer.QueryTracers, &SQLQueryTracer{log: pg.logger})
+		tracer.ConnectTracers = append(tracer.ConnectTracers, &ConnectTracer{log: pg.logger})
+	}
+	if len(tracer.QueryTracers) > 0 || len(tracer.ConnectTracers) > 0 {
+		poolConfig.ConnConfig.Tracer = tracer
+	}
 
 	if cfg.Debug {
-		poolConfig.ConnConfig.Tracer = &multitracer.Tracer{
-			QueryTracers: []pgx.QueryTracer{
-				&SQLQueryTracer{log: pg.logger},
-			},
-			ConnectTracers: []pgx.ConnectTracer{
-				&ConnectTracer{log: pg.logger},
-			},
-		}
 
 		poolConfig.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
 			pg.logger.Debug(ctx, "[PGPOOL] attempting to acquire connection")
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"clean-arch-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	_defaultReplicaMaxLag      = 5 * time.Second
+	_defaultReplicaCheckPeriod = 5 * time.Second
+)
+
+// replicaMaxSilence — сколько реплика может не получать сообщений от
+// primary, оставаясь в ротации. На простаивающем primary walsender шлёт
+// keepalive раз в wal_sender_timeout/2 (30s по умолчанию), поэтому порог —
+// с запасом над этим интервалом, а не DB_REPLICA_MAX_LAG.
+const replicaMaxSilence = time.Minute
+
+// replicaStatusQuery — состояние WAL receiver и отставание реплики.
+// Отставание — по времени последней проигранной транзакции; если весь
+// принятый WAL уже проигран, реплика догнала primary: без записей на primary
+// replay_timestamp стареет, но это не отставание. Само по себе равенство LSN
+// ничего не говорит, если репликация оборвалась, — поэтому нужен receiver в
+// статусе streaming, недавно получавший сообщения. Без receiver (сервер не
+// реплика или репликация остановлена) строка receiver — NULL.
+const replicaStatusQuery = `
+	SELECT r.pid, r.status, EXTRACT(EPOCH FROM now() - r.last_msg_receipt_time)::float8,
+		CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END::float8
+	FROM (SELECT 1) AS one
+	LEFT JOIN pg_stat_wal_receiver r ON true`
+
+type primaryKey struct{}
+
+// WithPrimary помечает ctx: чтения через ReadDBGetter пойдут в primary.
+// Нужен после записи, результат которой читается сразу (read-your-writes):
+// реплика может её ещё не получить.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryForced — ctx помечен WithPrimary.
+func PrimaryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryKey{}).(bool)
+	return forced
+}
+
+type replica struct {
+	host string
+	db   tx.DB
+	// probe возвращает текущее отставание; ошибка — реплика недоступна.
+	probe   func(ctx context.Context) (time.Duration, error)
+	healthy atomic.Bool
+	checked atomic.Bool
+}
+
+// replicaSet — реплики с фоновой проверкой отставания; чтения распределяются
+// round-robin по репликам в ротации.
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+
+	maxLag  time.Duration
+	period  time.Duration
+	timeout time.Duration
+	log     logger.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func poolProbe(pool *pgxpool.Pool) func(ctx context.Context) (time.Duration, error) {
+	return func(ctx context.Context) (time.Duration, error) {
+		var (
+			st      receiverStatus
+			seconds float64
+		)
+		if err := pool.QueryRow(ctx, replicaStatusQuery).Scan(&st.pid, &st.status, &st.silence, &seconds); err != nil {
+			return 0, err
+		}
+		if err := st.check(); err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+}
+
+// receiverStatus — строка pg_stat_wal_receiver; NULL-поля — nil.
+type receiverStatus struct {
+	pid     *int32
+	status  *string
+	silence *float64
+}
+
+// check — реплика получает WAL от primary: receiver есть, в статусе
+// streaming и слышал primary не дольше replicaMaxSilence назад.
+func (st receiverStatus) check() error {
+	switch {
+	case st.pid == nil:
+		return errors.New("no WAL receiver: server is not streaming from primary")
+	case st.status == nil:
+		// Без pg_read_all_stats видна только колонка pid.
+		return errors.New("pg_stat_wal_receiver details are hidden: grant pg_read_all_stats to the database user")
+	case *st.status != "streaming":
+		return fmt.Errorf("WAL receiver is %s, not streaming", *st.status)
+	case st.silence == nil || *st.silence > replicaMaxSilence.Seconds():
+		return fmt.Errorf("no message from primary for over %s", replicaMaxSilence)
+	}
+
+	return nil
+}
+
+// pick — следующая реплика в ротации; nil, если здоровых нет.
+func (s *replicaSet) pick() *replica {
+	n := uint64(len(s.replicas))
+	for range n {
+		r := s.replicas[s.next.Add(1)%n]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// check опрашивает все реплики и обновляет ротацию; переходы логируются.
+func (s *replicaSet) check(ctx context.Context) {
+	for _, r := range s.replicas {
+		probeCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		lag, err := r.probe(probeCtx)
+		cancel()
+
+		healthy := err == nil && lag <= s.maxLag
+		// Первая проверка логируется всегда: недоступная со старта реплика
+		// иначе молча осталась бы вне ротации.
+		changed := r.healthy.Swap(healthy) != healthy
+		if first := !r.checked.Swap(true); !changed && !first {
+			continue
+		}
+
+		switch {
+		case healthy:
+			s.log.Info(ctx, "database: replica in rotation", "host", r.host, "lag", lag.String())
+		case err != nil:
+			s.log.Warn(ctx, "database: replica out of rotation", "host", r.host, "error", err)
+		default:
+			s.log.Warn(ctx, "database: replica out of rotation", "host", r.host,
+				"lag", lag.String(), "max_lag", s.maxLag.String())
+		}
+	}
+}
+
+// start проверяет реплики сразу (до первой проверки реплика вне ротации) и
+// затем раз в period до close.
+func (s *replicaSet) start() {
+	s.stop = make(chan struct{})
+	s.check(context.Background())
+
+	s.wg.Go(func() {
+		ticker := time.NewTicker(s.period)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.check(context.Background())
+			}
+		}
+	})
+}
+
+func (s *replicaSet) close() {
+	if s.stop != nil {
+		close(s.stop)
+		s.wg.Wait()
+	}
+	for _, r := range s.replicas {
+		if pool, ok := r.db.(*pgxpool.Pool); ok {
+			pool.Close()
+		}
+	}
+}
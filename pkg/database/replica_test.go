@@ -0,0 +1,131 @@
+package database
+
+import (
+	"clean-arch-template/pkg/logger/loggertest"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedDB — tx.DB-заглушка: тесту важно лишь, какой пул выбран.
+type namedDB struct {
+	tx.DB
+	name string
+}
+
+func newTestReplica(name string, lag time.Duration, err error) *replica {
+	return &replica{
+		host: name,
+		db:   namedDB{name: name},
+		probe: func(context.Context) (time.Duration, error) {
+			return lag, err
+		},
+	}
+}
+
+func TestReplicaSetCheck(t *testing.T) {
+	t.Parallel()
+
+	fresh := newTestReplica("fresh", time.Second, nil)
+	lagging := newTestReplica("lagging", time.Minute, nil)
+	down := newTestReplica("down", 0, errors.New("connection refused"))
+
+	log := &loggertest.Fake{}
+	set := &replicaSet{
+		replicas: []*replica{fresh, lagging, down},
+		maxLag:   5 * time.Second,
+		timeout:  time.Second,
+		log:      log,
+	}
+	set.check(context.Background())
+
+	assert.True(t, fresh.healthy.Load())
+	assert.False(t, lagging.healthy.Load())
+	assert.False(t, down.healthy.Load())
+	require.Len(t, log.Entries, 3, "первая проверка логирует состояние каждой реплики")
+
+	for range 10 {
+		assert.Same(t, fresh, set.pick(), "в ротации только fresh")
+	}
+
+	// Повторная проверка без изменений не логирует; догнавшая реплика
+	// возвращается в ротацию.
+	lagging.probe = func(context.Context) (time.Duration, error) { return 0, nil }
+	set.check(context.Background())
+	require.Len(t, log.Entries, 4)
+	assert.Equal(t, "database: replica in rotation", log.Entries[3].Msg)
+
+	picked := map[string]int{}
+	for range 10 {
+		picked[set.pick().host]++
+	}
+	assert.Equal(t, map[string]int{"fresh": 5, "lagging": 5}, picked, "round-robin по здоровым")
+}
+
+func TestReadDBRouting(t *testing.T) {
+	t.Parallel()
+
+	primary, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = primary.Close(context.Background()) })
+
+	transactor, dbGetter := tx.NewTransactor(primary)
+	rep := newTestReplica("replica", 0, nil)
+	rep.healthy.Store(true)
+
+	pg := &Postgres{DBGetter: dbGetter, replicas: &replicaSet{replicas: []*replica{rep}}}
+	ctx := context.Background()
+
+	assert.Equal(t, rep.db, pg.readDB(ctx))
+	assert.Equal(t, primary, pg.readDB(WithPrimary(ctx)), "WithPrimary — чтение из primary")
+
+	primary.ExpectBegin()
+	primary.ExpectCommit()
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		assert.NotEqual(t, rep.db, pg.readDB(ctx), "внутри транзакции — её соединение")
+		return nil
+	})
+	require.NoError(t, err)
+
+	rep.healthy.Store(false)
+	assert.Equal(t, primary, pg.readDB(ctx), "без здоровых реплик — primary")
+
+	noReplicas := &Postgres{DBGetter: dbGetter}
+	assert.Equal(t, primary, noReplicas.readDB(ctx))
+}
+
+func TestReceiverStatusCheck(t *testing.T) {
+	t.Parallel()
+
+	pid := int32(42)
+	streaming, stopping := "streaming", "stopping"
+	recent, stale := 3.0, 120.0
+
+	tests := []struct {
+		name string
+		st   receiverStatus
+		err  string
+	}{
+		{name: "streaming", st: receiverStatus{pid: &pid, status: &streaming, silence: &recent}},
+		{name: "no receiver", st: receiverStatus{}, err: "no WAL receiver"},
+		{name: "no privileges", st: receiverStatus{pid: &pid}, err: "pg_read_all_stats"},
+		{name: "not streaming", st: receiverStatus{pid: &pid, status: &stopping, silence: &recent}, err: "stopping"},
+		{name: "primary silent", st: receiverStatus{pid: &pid, status: &streaming, silence: &stale}, err: "no message from primary"},
+		{name: "nothing received", st: receiverStatus{pid: &pid, status: &streaming}, err: "no message from primary"},
+	}
+
+	for _, tc := range tests {
+		err := tc.st.check()
+		if tc.err == "" {
+			assert.NoError(t, err, tc.name)
+			continue
+		}
+		assert.ErrorContains(t, err, tc.err, tc.name)
+	}
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	tx "github.com/Thiht/transactor/pgx"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	_defaultTxMaxAttempts = 5
+	_defaultTxBaseDelay   = 10 * time.Millisecond
+	_defaultTxMaxDelay    = time.Second
+)
+
+// Атрибуты повторов транзакций.
+const (
+	attrTxAttempt  = attribute.Key("db.transaction.attempt")
+	attrTxSQLState = attribute.Key("db.response.status_code")
+	attrTxDelay    = attribute.Key("db.transaction.retry_delay_ms")
+)
+
+// retryableCodes — SQLSTATE, после которых транзакцию безопасно повторить
+// целиком: сервер её уже откатил, а причина — конкуренция, а не данные.
+var retryableCodes = []string{
+	"40001", // serialization_failure
+	"40P01", // deadlock_detected
+	"55P03", // lock_not_available (lock_timeout, NOWAIT)
+}
+
+// RetryableSQLState возвращает SQLSTATE ошибки, если транзакцию с ней стоит
+// повторить, и "" в остальных случаях.
+func RetryableSQLState(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && slices.Contains(retryableCodes, pgErr.Code) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// RetryingTransactor повторяет транзакцию целиком при serialization failure,
+// deadlock и lock timeout: экспоненциальная пауза с полным jitter, не дольше
+// maxDelay и не за пределами дедлайна ctx. txFunc исполняется заново, поэтому
+// побочные эффекты вне БД в ней недопустимы. Каждый повтор — событие
+// db.transaction.retry на текущем спане и счётчик app.db.transaction.retries.
+type RetryingTransactor struct {
+	next        Transactor
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+
+	retries   metric.Int64Counter
+	exhausted metric.Int64Counter
+
+	// sleep ждёт паузу или отмену ctx; подменяется в тестах.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+var _ Transactor = (*RetryingTransactor)(nil)
+
+// NewRetryingTransactor — maxAttempts включает первую попытку; 1 отключает
+// повторы. Нулевые значения заменяются значениями по умолчанию.
+func NewRetryingTransactor(next Transactor, maxAttempts int, baseDelay, maxDelay time.Duration) (*RetryingTransactor, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = _defaultTxMaxAttempts
+	}
+	if baseDelay <= 0 {
+		baseDelay = _defaultTxBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = _defaultTxMaxDelay
+	}
+
+	meter := otel.Meter(tracerName)
+	retries, err := meter.Int64Counter("app.db.transaction.retries",
+		metric.WithDescription("Transactions retried after a serialization failure, deadlock or lock timeout."),
+		metric.WithUnit("{retry}"))
+	if err != nil {
+		return nil, err
+	}
+	exhausted, err := meter.Int64Counter("app.db.transaction.retries_exhausted",
+		metric.WithDescription("Transactions that failed with a retryable error after the last attempt or deadline."),
+		metric.WithUnit("{transaction}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RetryingTransactor{
+		next:        next,
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+		maxDelay:    maxDelay,
+		retries:     retries,
+		exhausted:   exhausted,
+		sleep:       sleepContext,
+	}, nil
+}
+
+func (t *RetryingTransactor) WithinTransaction(ctx context.Context, txFunc func(ctx context.Context) error) error {
+	// Вложенный вызов не повторяется: ошибка откатила внешнюю транзакцию,
+	// повторять её целиком — дело внешнего уровня.
+	if tx.IsWithinTransaction(ctx) {
+		return t.next.WithinTransaction(ctx, txFunc)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := t.next.WithinTransaction(ctx, txFunc)
+
+		code := RetryableSQLState(err)
+		if code == "" {
+			return err
+		}
+
+		state := metric.WithAttributes(attrTxSQLState.String(code))
+		delay := t.backoff(attempt)
+		if attempt >= t.maxAttempts || !fitsDeadline(ctx, delay) {
+			t.exhausted.Add(ctx, 1, state)
+			return err
+		}
+
+		t.retries.Add(ctx, 1, state)
+		trace.SpanFromContext(ctx).AddEvent("db.transaction.retry", trace.WithAttributes(
+			attrTxAttempt.Int(attempt),
+			attrTxSQLState.String(code),
+			attrTxDelay.Int64(delay.Milliseconds()),
+		))
+
+		if t.sleep(ctx, delay) != nil {
+			// ctx отменён во время паузы: вызывающему важнее исходная ошибка БД.
+			return err
+		}
+	}
+}
+
+// backoff — пауза перед повтором attempt: случайная в [0, min(base·2^(attempt-1), max)].
+func (t *RetryingTransactor) backoff(attempt int) time.Duration {
+	ceiling := t.maxDelay
+	if shift := attempt - 1; shift < 32 {
+		ceiling = min(t.baseDelay<<shift, t.maxDelay)
+	}
+	return rand.N(ceiling + 1)
+}
+
+// fitsDeadline — после паузы у ctx останется время на попытку.
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > delay
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
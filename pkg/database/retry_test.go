@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyTransactor падает с заданными ошибками по очереди, затем успешен.
+type flakyTransactor struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyTransactor) WithinTransaction(ctx context.Context, txFunc func(ctx context.Context) error) error {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return txFunc(ctx)
+}
+
+func newTestRetrying(t *testing.T, next Transactor, attempts int) (*RetryingTransactor, *[]time.Duration) {
+	t.Helper()
+
+	r, err := NewRetryingTransactor(next, attempts, 10*time.Millisecond, 40*time.Millisecond)
+	require.NoError(t, err)
+
+	var sleeps []time.Duration
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+
+	return r, &sleeps
+}
+
+func pgError(code string) error {
+	return &pgconn.PgError{Code: code}
+}
+
+func TestRetryingTransactorRetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	next := &flakyTransactor{errs: []error{pgError("40001"), pgError("40P01"), pgError("55P03")}}
+	r, sleeps := newTestRetrying(t, next, 5)
+
+	provider, recorder := newRecordingTracer()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "use case")
+
+	ran := 0
+	require.NoError(t, r.WithinTransaction(ctx, func(context.Context) error { ran++; return nil }))
+	span.End()
+
+	assert.Equal(t, 4, next.calls)
+	assert.Equal(t, 1, ran)
+	require.Len(t, *sleeps, 3)
+	for i, d := range *sleeps {
+		assert.LessOrEqual(t, d, min(10*time.Millisecond<<i, 40*time.Millisecond), "пауза %d", i+1)
+	}
+
+	events := recorder.Ended()[0].Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, "db.transaction.retry", events[0].Name)
+	assert.Contains(t, events[1].Attributes, attrTxSQLState.String("40P01"))
+	assert.Contains(t, events[2].Attributes, attrTxAttempt.Int(3))
+}
+
+func TestRetryingTransactorGivesUp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("non-retryable error", func(t *testing.T) {
+		t.Parallel()
+
+		next := &flakyTransactor{errs: []error{pgError("23505"), nil}}
+		r, _ := newTestRetrying(t, next, 5)
+
+		err := r.WithinTransaction(context.Background(), func(context.Context) error { return nil })
+		require.Equal(t, pgError("23505"), err)
+		assert.Equal(t, 1, next.calls)
+
+		next = &flakyTransactor{errs: []error{errors.New("insufficient funds")}}
+		r, _ = newTestRetrying(t, next, 5)
+		require.Error(t, r.WithinTransaction(context.Background(), func(context.Context) error { return nil }))
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		next := &flakyTransactor{errs: []error{pgError("40001"), pgError("40001"), pgError("40001")}}
+		r, sleeps := newTestRetrying(t, next, 3)
+
+		err := r.WithinTransaction(context.Background(), func(context.Context) error { return nil })
+		assert.Equal(t, "40001", RetryableSQLState(err), "наружу — последняя ошибка БД")
+		assert.Equal(t, 3, next.calls)
+		assert.Len(t, *sleeps, 2)
+	})
+
+	t.Run("deadline too close", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		assert.True(t, fitsDeadline(ctx, time.Second))
+		assert.False(t, fitsDeadline(ctx, time.Hour), "пауза не влезает в дедлайн — отказ сразу")
+		assert.True(t, fitsDeadline(context.Background(), time.Hour), "без дедлайна ограничивают только попытки")
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		t.Parallel()
+
+		next := &flakyTransactor{errs: []error{pgError("40P01")}}
+		r, _ := newTestRetrying(t, next, 5)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := r.WithinTransaction(ctx, func(context.Context) error { return nil })
+		assert.Equal(t, "40P01", RetryableSQLState(err))
+		assert.Equal(t, 1, next.calls)
+	})
+}
+
+func TestRetryableSQLState(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "40001", RetryableSQLState(pgError("40001")))
+	assert.Equal(t, "55P03", RetryableSQLState(errors.Join(errors.New("commit"), pgError("55P03"))))
+	assert.Empty(t, RetryableSQLState(pgError("23503")))
+	assert.Empty(t, RetryableSQLState(nil))
+}
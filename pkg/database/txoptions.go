@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Timeouts — таймауты Postgres: statement_timeout, lock_timeout,
+// idle_in_transaction_session_timeout. Нулевое поле значение не меняет.
+type Timeouts struct {
+	Statement time.Duration
+	Lock      time.Duration
+	IdleInTx  time.Duration
+}
+
+// params — параметры сервера в миллисекундах в порядке полей. Меньше
+// миллисекунды округляется вверх: 0 у Postgres означает «без таймаута».
+func (t Timeouts) params() [][2]string {
+	var out [][2]string
+	for _, p := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"statement_timeout", t.Statement},
+		{"lock_timeout", t.Lock},
+		{"idle_in_transaction_session_timeout", t.IdleInTx},
+	} {
+		if p.d > 0 {
+			out = append(out, [2]string{p.name, strconv.FormatInt(max(p.d.Milliseconds(), 1), 10)})
+		}
+	}
+	return out
+}
+
+type txOptionsKey struct{}
+
+// WithTxOptions задаёт параметры транзакции, которую откроет WithinTransaction
+// с этим ctx: уровень изоляции, read-only, deferrable. Без них — настройки
+// сервера по умолчанию (READ COMMITTED, READ WRITE). Во вложенном вызове
+// (транзакция уже в ctx) не действуют.
+func WithTxOptions(ctx context.Context, opts pgx.TxOptions) context.Context {
+	return context.WithValue(ctx, txOptionsKey{}, opts)
+}
+
+type timeoutsKey struct{}
+
+// WithTimeouts переопределяет таймауты пула для транзакции, которую откроет
+// WithinTransaction с этим ctx (SET LOCAL — действуют до её конца), например
+// длинный statement_timeout для выгрузки. Запросы вне транзакции идут с
+// таймаутами пула: долгое чтение оборачивают в WithinTransaction с read-only.
+func WithTimeouts(ctx context.Context, t Timeouts) context.Context {
+	return context.WithValue(ctx, timeoutsKey{}, t)
+}
+
+// optionsPool открывает транзакции с pgx.TxOptions и Timeouts из ctx:
+// библиотека transactor вызывает Begin без параметров.
+type optionsPool struct {
+	*pgxpool.Pool
+}
+
+func (p optionsPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	opts, _ := ctx.Value(txOptionsKey{}).(pgx.TxOptions)
+	tx, err := p.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if t, ok := ctx.Value(timeoutsKey{}).(Timeouts); ok {
+		if err := setLocal(ctx, tx, t); err != nil {
+			_ = tx.Rollback(ctx)
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// setLocal применяет таймауты к текущей транзакции одним запросом.
+// set_config(..., true) — то же, что SET LOCAL, но с параметрами.
+func setLocal(ctx context.Context, tx execer, t Timeouts) error {
+	params := t.params()
+	if len(params) == 0 {
+		return nil
+	}
+
+	calls := make([]string, 0, len(params))
+	args := make([]any, 0, len(params))
+	for i, p := range params {
+		calls = append(calls, fmt.Sprintf("set_config('%s', $%d, true)", p[0], i+1))
+		args = append(args, p[1])
+	}
+
+	if _, err := tx.Exec(ctx, "SELECT "+strings.Join(calls, ", "), args...); err != nil {
+		return fmt.Errorf("set local timeouts: %w", err)
+	}
+
+	return nil
+}
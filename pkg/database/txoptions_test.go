@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutsParams(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, Timeouts{}.params())
+	assert.Equal(t, [][2]string{
+		{"statement_timeout", "15000"},
+		{"idle_in_transaction_session_timeout", "1"},
+	}, Timeouts{Statement: 15 * time.Second, IdleInTx: time.Microsecond}.params(),
+		"меньше миллисекунды — 1ms, а не 0 (0 отключает таймаут)")
+}
+
+func TestSetLocal(t *testing.T) {
+	t.Parallel()
+
+	conn, err := pgxmock.NewConn(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close(context.Background()) })
+
+	conn.ExpectExec("SELECT set_config('statement_timeout', $1, true), set_config('lock_timeout', $2, true)").
+		WithArgs("300000", "500").
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	require.NoError(t, setLocal(context.Background(), conn, Timeouts{Statement: 5 * time.Minute, Lock: 500 * time.Millisecond}))
+	require.NoError(t, setLocal(context.Background(), conn, Timeouts{}), "без таймаутов запроса нет")
+	require.NoError(t, conn.ExpectationsWereMet())
+}
@@ -7,6 +7,7 @@ import (
 	"clean-arch-template/config"
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 )
 
@@ -26,16 +27,36 @@ const (
 
 // New — фабрика логгера по cfg.Log.Backend (env LOG_BACKEND).
 func New(cfg *config.Config) (Logger, error) {
-	switch cfg.Backend {
+	switch cfg.Log.Backend {
 	case BackendSlog, "":
 		return newSlogLogger(cfg, os.Stdout), nil
 	case BackendZerolog:
 		return newZeroLogger(cfg, os.Stdout), nil
 	default:
-		return nil, fmt.Errorf("unknown log backend %q (supported: %s, %s)", cfg.Backend, BackendSlog, BackendZerolog)
+		return nil, fmt.Errorf("unknown log backend %q (supported: %s, %s)", cfg.Log.Backend, BackendSlog, BackendZerolog)
 	}
 }
 
+// SetLevel меняет уровень l на лету по cfg (LOG_LEVEL, DEBUG=true → Debug) —
+// для горячей перезагрузки конфига. Меняется уровень и у всех логгеров,
+// полученных из l через With. false — реализация уровень не меняет (Nop,
+// фейки в тестах).
+func SetLevel(l Logger, cfg *config.Config) bool {
+	s, ok := l.(interface{ setLevel(slog.Level) })
+	if ok {
+		s.setLevel(levelOf(cfg))
+	}
+	return ok
+}
+
+// levelOf — уровень по cfg: LOG_LEVEL, DEBUG=true → Debug.
+func levelOf(cfg *config.Config) slog.Level {
+	if cfg.Debug {
+		return slog.LevelDebug
+	}
+	return cfg.Level
+}
+
 // Nop — логгер-заглушка для необязательных зависимостей.
 func Nop() Logger { return nopLogger{} }
 
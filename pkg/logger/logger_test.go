@@ -3,8 +3,10 @@ package logger
 import (
 	"bytes"
 	"clean-arch-template/config"
+	"clean-arch-template/pkg/requestid"
 	"context"
 	"encoding/json"
+	"log/slog"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -159,6 +161,34 @@ func TestZeroLoggerTraceCorrelation(t *testing.T) {
 	assert.Equal(t, "0200000000000000", entry["span_id"])
 }
 
+func TestRequestIDInEveryEntry(t *testing.T) {
+	t.Parallel()
+
+	for name, newLogger := range map[string]func(*bytes.Buffer) Logger{
+		"slog":    func(buf *bytes.Buffer) Logger { return newSlogLogger(prodConfig(), buf) },
+		"zerolog": func(buf *bytes.Buffer) Logger { return newZeroLogger(prodConfig(), buf) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			ctx := requestid.NewContext(ctxWithSpan(t), "req-1")
+
+			newLogger(&buf).Info(ctx, "handled")
+
+			entry := lastJSONLine(t, &buf)
+			assert.Equal(t, "req-1", entry["request_id"])
+			assert.Equal(t, "01000000000000000000000000000000", entry["trace_id"], "request_id не вытесняет trace_id")
+		})
+	}
+}
+
+func TestContextArgsWithoutRequestID(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, contextArgs(context.Background()))
+}
+
 func TestNewFactory(t *testing.T) {
 	t.Parallel()
 
@@ -244,3 +274,35 @@ func TestZeroLoggerWithDoesNotMutateOriginal(t *testing.T) {
 	entry := lastJSONLine(t, &buf)
 	assert.NotContains(t, entry, "component")
 }
+
+func TestSetLevelAppliesToDerivedLoggers(t *testing.T) {
+	t.Parallel()
+
+	for name, newLogger := range map[string]func(*bytes.Buffer) Logger{
+		"slog":    func(buf *bytes.Buffer) Logger { return newSlogLogger(prodConfig(), buf) },
+		"zerolog": func(buf *bytes.Buffer) Logger { return newZeroLogger(prodConfig(), buf) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			root := newLogger(&buf)
+			child := root.With("component", "test")
+
+			cfg := prodConfig()
+			cfg.Log.Level = slog.LevelDebug
+			require.True(t, SetLevel(root, cfg))
+
+			child.Debug(context.Background(), "visible")
+			assert.Equal(t, "visible", lastJSONLine(t, &buf)["message"])
+
+			buf.Reset()
+			cfg.Log.Level = slog.LevelError
+			require.True(t, SetLevel(child, cfg))
+			root.Warn(context.Background(), "invisible")
+			assert.Empty(t, buf.Bytes())
+		})
+	}
+
+	assert.False(t, SetLevel(Nop(), prodConfig()))
+}
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"clean-arch-template/pkg/requestid"
+	"context"
+)
+
+// contextArgs — атрибуты корреляции из ctx, которые логгер добавляет к
+// каждой записи: request_id (есть всегда в рамках запроса) и trace_id/span_id
+// (только для активного спана).
+func contextArgs(ctx context.Context) []any {
+	tr := traceArgs(ctx)
+
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		return tr
+	}
+
+	return append([]any{"request_id", id}, tr...)
+}
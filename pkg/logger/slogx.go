@@ -11,6 +11,8 @@ import (
 
 type slogLogger struct {
 	l *slog.Logger
+	// level общий с логгерами из With: SetLevel меняет уровень всем.
+	level *slog.LevelVar
 }
 
 var _ Logger = (*slogLogger)(nil)
@@ -18,14 +20,12 @@ var _ Logger = (*slogLogger)(nil)
 // newSlogLogger — реализация на stdlib slog: prod → JSON с ключом message,
 // иначе text с source-позициями; уровень из LOG_LEVEL, DEBUG=true → Debug.
 func newSlogLogger(cfg *config.Config, out io.Writer) *slogLogger {
-	level := cfg.Level
-	if cfg.Debug {
-		level = slog.LevelDebug
-	}
+	level := new(slog.LevelVar)
+	level.Set(levelOf(cfg))
 
 	var handler slog.Handler
 
-	if cfg.Environment == "prod" {
+	if cfg.Environment == config.EnvProd {
 		renameMsgKey := func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key == slog.MessageKey {
 				a.Key = "message"
@@ -43,7 +43,7 @@ func newSlogLogger(cfg *config.Config, out io.Writer) *slogLogger {
 		})
 	}
 
-	return &slogLogger{l: slog.New(handler)}
+	return &slogLogger{l: slog.New(handler), level: level}
 }
 
 func (s *slogLogger) Debug(ctx context.Context, msg string, args ...any) {
@@ -63,7 +63,11 @@ func (s *slogLogger) Error(ctx context.Context, msg string, args ...any) {
 }
 
 func (s *slogLogger) With(args ...any) Logger {
-	return &slogLogger{l: s.l.With(args...)}
+	return &slogLogger{l: s.l.With(args...), level: s.level}
+}
+
+func (s *slogLogger) setLevel(level slog.Level) {
+	s.level.Set(level)
 }
 
 func (s *slogLogger) log(ctx context.Context, level slog.Level, msg string, args []any) {
@@ -79,7 +83,7 @@ func (s *slogLogger) log(ctx context.Context, level slog.Level, msg string, args
 
 	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
 
-	if tr := traceArgs(ctx); tr != nil {
+	if tr := contextArgs(ctx); tr != nil {
 		args = append(append(make([]any, 0, len(args)+len(tr)), args...), tr...)
 	}
 	r.Add(args...)
@@ -5,12 +5,16 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"sync/atomic"
 
 	"github.com/rs/zerolog"
 )
 
 type zeroLogger struct {
 	l zerolog.Logger
+	// level общий с логгерами из With: SetLevel меняет уровень всем.
+	// Фильтрует log, а не сам zerolog.Logger — его уровень неизменяем.
+	level *atomic.Int32
 }
 
 var _ Logger = (*zeroLogger)(nil)
@@ -18,28 +22,25 @@ var _ Logger = (*zeroLogger)(nil)
 // newZeroLogger — реализация на rs/zerolog с теми же правилами, что slogx:
 // prod → JSON в out, иначе — ConsoleWriter; уровень из LOG_LEVEL, DEBUG=true → Debug.
 func newZeroLogger(cfg *config.Config, out io.Writer) *zeroLogger {
-	if cfg.Environment != "prod" {
+	if cfg.Environment != config.EnvProd {
 		out = zerolog.ConsoleWriter{Out: out}
 	}
 
-	l := zerolog.New(out).
-		Level(zerologLevel(cfg)).
-		With().Timestamp().Logger()
+	l := zerolog.New(out).With().Timestamp().Logger()
 
-	return &zeroLogger{l: l}
-}
+	z := &zeroLogger{l: l, level: new(atomic.Int32)}
+	z.setLevel(levelOf(cfg))
 
-func zerologLevel(cfg *config.Config) zerolog.Level {
-	if cfg.Debug {
-		return zerolog.DebugLevel
-	}
+	return z
+}
 
+func zerologLevel(level slog.Level) zerolog.Level {
 	switch {
-	case cfg.Level <= slog.LevelDebug:
+	case level <= slog.LevelDebug:
 		return zerolog.DebugLevel
-	case cfg.Level <= slog.LevelInfo:
+	case level <= slog.LevelInfo:
 		return zerolog.InfoLevel
-	case cfg.Level <= slog.LevelWarn:
+	case level <= slog.LevelWarn:
 		return zerolog.WarnLevel
 	default:
 		return zerolog.ErrorLevel
@@ -47,19 +48,19 @@ func zerologLevel(cfg *config.Config) zerolog.Level {
 }
 
 func (z *zeroLogger) Debug(ctx context.Context, msg string, args ...any) {
-	z.log(ctx, z.l.Debug(), msg, args)
+	z.log(ctx, z.event(zerolog.DebugLevel), msg, args)
 }
 
 func (z *zeroLogger) Info(ctx context.Context, msg string, args ...any) {
-	z.log(ctx, z.l.Info(), msg, args)
+	z.log(ctx, z.event(zerolog.InfoLevel), msg, args)
 }
 
 func (z *zeroLogger) Warn(ctx context.Context, msg string, args ...any) {
-	z.log(ctx, z.l.Warn(), msg, args)
+	z.log(ctx, z.event(zerolog.WarnLevel), msg, args)
 }
 
 func (z *zeroLogger) Error(ctx context.Context, msg string, args ...any) {
-	z.log(ctx, z.l.Error(), msg, args)
+	z.log(ctx, z.event(zerolog.ErrorLevel), msg, args)
 }
 
 func (z *zeroLogger) With(args ...any) Logger {
@@ -68,11 +69,23 @@ func (z *zeroLogger) With(args ...any) Logger {
 		lctx = lctx.Interface(k, v)
 	}
 
-	return &zeroLogger{l: lctx.Logger()}
+	return &zeroLogger{l: lctx.Logger(), level: z.level}
+}
+
+func (z *zeroLogger) setLevel(level slog.Level) {
+	z.level.Store(int32(zerologLevel(level)))
+}
+
+// event — событие уровня level или nil, если уровень выключен.
+func (z *zeroLogger) event(level zerolog.Level) *zerolog.Event {
+	if level < zerolog.Level(z.level.Load()) {
+		return nil
+	}
+	return z.l.WithLevel(level)
 }
 
 func (z *zeroLogger) log(ctx context.Context, e *zerolog.Event, msg string, args []any) {
-	// zerolog возвращает nil-событие для выключенного уровня: выходим сразу,
+	// event возвращает nil для выключенного уровня: выходим сразу,
 	// не тратя горячий путь на разбор пар и извлечение спана из ctx.
 	if e == nil {
 		return
@@ -81,7 +94,7 @@ func (z *zeroLogger) log(ctx context.Context, e *zerolog.Event, msg string, args
 	for k, v := range pairs(args) {
 		e = e.Interface(k, v)
 	}
-	for k, v := range pairs(traceArgs(ctx)) {
+	for k, v := range pairs(contextArgs(ctx)) {
 		e = e.Interface(k, v)
 	}
 	e.Msg(msg)
@@ -0,0 +1,106 @@
+// Package metrics — OpenTelemetry-метрики приложения: доменные (переводы,
+// пользователи), пул соединений и состояние миграций. Use case видит только
+// интерфейс usecase.UserMetrics; инструменты создаются здесь, а куда они
+// уходят (Prometheus /metrics или OTLP) решает MeterProvider
+// (tracing.InitMeterProvider).
+//
+// Имена выбраны так, чтобы после перевода в формат Prometheus совпадать с
+// прежними (app_transfers_attempted_total и т. д.): дашборды не меняются.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "clean-arch-template/pkg/metrics"
+
+// Metrics — доменные метрики и состояние миграций. Методы принимают ctx:
+// по его спану SDK прикладывает exemplar с trace_id к точке гистограммы.
+type Metrics struct {
+	transfersAttempted metric.Int64Counter
+	transfersSucceeded metric.Int64Counter
+	transfersFailed    metric.Int64Counter
+	transferAmount     metric.Int64Histogram
+	usersCreated       metric.Int64Counter
+	usersDeleted       metric.Int64Counter
+
+	migrationVersion metric.Int64Gauge
+	migrationApplied metric.Int64Gauge
+	migrationLastRun metric.Int64Gauge
+}
+
+// New создаёт инструменты в mp (обычно otel.GetMeterProvider()).
+func New(mp metric.MeterProvider) (*Metrics, error) {
+	meter := mp.Meter(meterName)
+
+	var m Metrics
+	var errs [9]error
+
+	m.transfersAttempted, errs[0] = meter.Int64Counter("app.transfers.attempted",
+		metric.WithDescription("Money transfers requested, including rejected ones."))
+	m.transfersSucceeded, errs[1] = meter.Int64Counter("app.transfers.succeeded",
+		metric.WithDescription("Money transfers committed."))
+	m.transfersFailed, errs[2] = meter.Int64Counter("app.transfers.failed",
+		metric.WithDescription("Money transfers failed, by reason (domain error or internal)."))
+	// Суммы — в минимальных единицах валюты: от 1 до 1 000 000 единиц.
+	m.transferAmount, errs[3] = meter.Int64Histogram("app.transfer.amount",
+		metric.WithDescription("Committed transfer amounts in minor currency units."),
+		metric.WithUnit("{minor_unit}"),
+		metric.WithExplicitBucketBoundaries(1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8))
+	m.usersCreated, errs[4] = meter.Int64Counter("app.users.created",
+		metric.WithDescription("Users created."))
+	m.usersDeleted, errs[5] = meter.Int64Counter("app.users.deleted",
+		metric.WithDescription("Users deleted."))
+	m.migrationVersion, errs[6] = meter.Int64Gauge("app.migration.version",
+		metric.WithDescription("Schema version after startup migrations."))
+	m.migrationApplied, errs[7] = meter.Int64Gauge("app.migrations.applied",
+		metric.WithDescription("Migrations applied at the last startup."))
+	m.migrationLastRun, errs[8] = meter.Int64Gauge("app.migration.last_run_timestamp",
+		metric.WithDescription("Unix time of the last successful migration run."),
+		metric.WithUnit("s"))
+
+	if err := errors.Join(errs[:]...); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (m *Metrics) TransferAttempted(ctx context.Context) { m.transfersAttempted.Add(ctx, 1) }
+
+func (m *Metrics) TransferSucceeded(ctx context.Context, amount int64) {
+	m.transfersSucceeded.Add(ctx, 1)
+	m.transferAmount.Record(ctx, amount)
+}
+
+func (m *Metrics) TransferFailed(ctx context.Context, reason string) {
+	m.transfersFailed.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+func (m *Metrics) UserCreated(ctx context.Context) { m.usersCreated.Add(ctx, 1) }
+
+func (m *Metrics) UserDeleted(ctx context.Context) { m.usersDeleted.Add(ctx, 1) }
+
+// MigrationsApplied фиксирует итог миграций при старте.
+func (m *Metrics) MigrationsApplied(ctx context.Context, version int64, applied int) {
+	m.migrationVersion.Record(ctx, version)
+	m.migrationApplied.Record(ctx, int64(applied))
+	m.migrationLastRun.Record(ctx, time.Now().Unix())
+}
+
+// Nop — метрики-заглушка для тестов и необязательных зависимостей.
+func Nop() NopMetrics { return NopMetrics{} }
+
+type NopMetrics struct{}
+
+func (NopMetrics) TransferAttempted(context.Context)             {}
+func (NopMetrics) TransferSucceeded(context.Context, int64)      {}
+func (NopMetrics) TransferFailed(context.Context, string)        {}
+func (NopMetrics) UserCreated(context.Context)                   {}
+func (NopMetrics) UserDeleted(context.Context)                   {}
+func (NopMetrics) MigrationsApplied(context.Context, int64, int) {}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterPool публикует pgxpool.Stat асинхронными инструментами: значения
+// снимаются в момент сбора (скрейп или push), своих счётчиков нет, поэтому
+// метрики не расходятся с пулом. Registration.Unregister снимает callback.
+func RegisterPool(mp metric.MeterProvider, pool *pgxpool.Pool) (metric.Registration, error) {
+	meter := mp.Meter(meterName)
+
+	var errs []error
+	gauge := func(name, desc string) metric.Int64ObservableGauge {
+		g, err := meter.Int64ObservableGauge("app.pgxpool."+name, metric.WithDescription(desc))
+		errs = append(errs, err)
+		return g
+	}
+	counter := func(name, desc string) metric.Int64ObservableCounter {
+		c, err := meter.Int64ObservableCounter("app.pgxpool."+name, metric.WithDescription(desc))
+		errs = append(errs, err)
+		return c
+	}
+	seconds := func(name, desc string) metric.Float64ObservableCounter {
+		c, err := meter.Float64ObservableCounter("app.pgxpool."+name, metric.WithDescription(desc), metric.WithUnit("s"))
+		errs = append(errs, err)
+		return c
+	}
+
+	acquired := gauge("acquired_conns", "Connections currently checked out of the pool.")
+	idle := gauge("idle_conns", "Idle connections in the pool.")
+	constructing := gauge("constructing_conns", "Connections being established.")
+	total := gauge("total_conns", "All connections owned by the pool.")
+	maxConns := gauge("max_conns", "Pool size limit.")
+	acquires := counter("acquires", "Successful connection acquires.")
+	acquireDuration := seconds("acquire_duration", "Total time spent acquiring connections.")
+	canceled := counter("canceled_acquires", "Acquires canceled by context before a connection was available.")
+	waited := counter("waited_acquires", "Acquires that had to wait for a connection (pool exhausted).")
+	waitDuration := seconds("wait_duration", "Total time acquires spent waiting for a free connection.")
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := pool.Stat()
+
+		o.ObserveInt64(acquired, int64(s.AcquiredConns()))
+		o.ObserveInt64(idle, int64(s.IdleConns()))
+		o.ObserveInt64(constructing, int64(s.ConstructingConns()))
+		o.ObserveInt64(total, int64(s.TotalConns()))
+		o.ObserveInt64(maxConns, int64(s.MaxConns()))
+		o.ObserveInt64(acquires, s.AcquireCount())
+		o.ObserveFloat64(acquireDuration, s.AcquireDuration().Seconds())
+		o.ObserveInt64(canceled, s.CanceledAcquireCount())
+		o.ObserveInt64(waited, s.EmptyAcquireCount())
+		o.ObserveFloat64(waitDuration, s.EmptyAcquireWaitTime().Seconds())
+
+		return nil
+	}, acquired, idle, constructing, total, maxConns, acquires, acquireDuration, canceled, waited, waitDuration)
+}
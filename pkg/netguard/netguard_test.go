@@ -0,0 +1,73 @@
+package netguard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:2800:220:1::1", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "::1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "172.16.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "255.255.255.255", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "64:ff9b::a9fe:a9fe", want: false},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, Allowed(netip.MustParseAddr(tc.addr)), tc.addr)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	t.Parallel()
+
+	for _, host := range []string{"partner.example.com", "93.184.216.34", "[2606:2800:220:1::1]"} {
+		assert.NoError(t, CheckHost(host), host)
+	}
+
+	for _, host := range []string{"127.0.0.1", "10.0.0.5", "169.254.169.254", "[::1]", "localhost", "api.localhost", "LOCALHOST.", "metadata.google.internal"} {
+		assert.ErrorIs(t, CheckHost(host), ErrForbiddenAddress, host)
+	}
+}
+
+func TestHTTPClientRefusesLoopback(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	resp, err := NewHTTPClient(time.Second).Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+}
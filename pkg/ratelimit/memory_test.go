@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryStore() (*MemoryStore, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	return store, &now
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	store, now := newTestMemoryStore()
+	limit := PerPeriod(2, 10*time.Second) // ведро на 2, токен каждые 5s
+	ctx := context.Background()
+
+	res, err := store.Take(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+	assert.Equal(t, 5*time.Second, res.Reset)
+
+	res, _ = store.Take(ctx, "k", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, _ = store.Take(ctx, "k", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 5*time.Second, res.RetryAfter)
+
+	*now = now.Add(2 * time.Second)
+	res, _ = store.Take(ctx, "k", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 3*time.Second, res.RetryAfter, "отказ не сбрасывает накопленное пополнение")
+
+	*now = now.Add(3 * time.Second)
+	res, _ = store.Take(ctx, "k", limit)
+	assert.True(t, res.Allowed)
+
+	res, _ = store.Take(ctx, "other", limit)
+	assert.True(t, res.Allowed, "у каждого ключа своё ведро")
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	t.Parallel()
+
+	store, now := newTestMemoryStore()
+	ctx := context.Background()
+
+	_, _ = store.Take(ctx, "idle", PerPeriod(1, time.Second))
+	_, _ = store.Take(ctx, "busy", PerPeriod(1, time.Hour))
+
+	*now = now.Add(sweepInterval)
+	_, _ = store.Take(ctx, "new", PerPeriod(1, time.Second))
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy", "неполное ведро удалять нельзя — это сбросило бы лимит")
+}
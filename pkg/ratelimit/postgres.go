@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"clean-arch-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const _defaultRetention = time.Hour
+
+// DB — подмножество pgxpool.Pool, нужное PostgresStore.
+type DB interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// PostgresStore — вёдра в таблице rate_limit_buckets: лимит общий для всех
+// реплик. Каждое решение — один запрос к БД (при отказе — два), поэтому
+// хранилище занимает соединение пула на время проверки.
+type PostgresStore struct {
+	db        DB
+	retention time.Duration
+	log       logger.Logger
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore: retention — сколько хранится неиспользуемое ведро; должно
+// быть не меньше самого длинного периода квот, тогда удалённое ведро уже
+// полное и удаление ничего не меняет.
+func NewPostgresStore(db DB, retention time.Duration, log logger.Logger) *PostgresStore {
+	if retention <= 0 {
+		retention = _defaultRetention
+	}
+
+	return &PostgresStore{db: db, retention: retention, log: log}
+}
+
+// Take пополняет и списывает токен одним UPSERT: строка блокируется на время
+// запроса, так что конкурентные реплики не тратят один токен дважды. Если
+// токена нет, UPDATE не выполняется (WHERE) и состояние читается отдельно.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	var tokens float64
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+		VALUES ($1, $2::float8 - 1, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE
+		SET tokens = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - b.updated_at)::float8 * $3::float8) - 1,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - b.updated_at)::float8 * $3::float8) >= 1
+		RETURNING tokens
+	`, key, limit.Burst, limit.Rate).Scan(&tokens)
+	if err == nil {
+		return newResult(limit, tokens, true), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, fmt.Errorf("take rate limit token: %w", err)
+	}
+
+	err = s.db.QueryRow(ctx, `
+		SELECT LEAST($2::float8, tokens + EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - updated_at)::float8 * $3::float8)
+		FROM rate_limit_buckets
+		WHERE key = $1
+	`, key, limit.Burst, limit.Rate).Scan(&tokens)
+	if err != nil {
+		return Result{}, fmt.Errorf("read rate limit bucket: %w", err)
+	}
+
+	return newResult(limit, tokens, false), nil
+}
+
+// Run периодически удаляет вёдра старше retention, пока не отменён ctx.
+func (s *PostgresStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.retention)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ct, err := s.db.Exec(ctx,
+			"DELETE FROM rate_limit_buckets WHERE updated_at < CURRENT_TIMESTAMP - make_interval(secs => $1)",
+			s.retention.Seconds(),
+		)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.log.Error(ctx, "ratelimit: sweep buckets", "error", err.Error())
+			}
+			continue
+		}
+		s.log.Debug(ctx, "ratelimit: swept buckets", "deleted", ct.RowsAffected())
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"clean-arch-template/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPostgresStore(t *testing.T) (pgxmock.PgxConnIface, *PostgresStore) {
+	t.Helper()
+
+	mockDb, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDb.Close(context.Background()) })
+
+	return mockDb, NewPostgresStore(mockDb, time.Minute, logger.Nop())
+}
+
+func TestPostgresStoreAllows(t *testing.T) {
+	t.Parallel()
+
+	mockDb, store := newPostgresStore(t)
+	limit := PerPeriod(10, time.Minute)
+
+	mockDb.ExpectQuery("INSERT INTO rate_limit_buckets").
+		WithArgs("k", 10, limit.Rate).
+		WillReturnRows(pgxmock.NewRows([]string{"tokens"}).AddRow(8.5))
+
+	res, err := store.Take(context.Background(), "k", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 8, res.Remaining)
+	assert.Equal(t, 10, res.Limit)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
+
+func TestPostgresStoreDeniesWhenBucketIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	mockDb, store := newPostgresStore(t)
+	limit := PerPeriod(10, time.Minute) // токен каждые 6s
+
+	mockDb.ExpectQuery("INSERT INTO rate_limit_buckets").
+		WithArgs("k", 10, limit.Rate).
+		WillReturnError(pgx.ErrNoRows)
+	mockDb.ExpectQuery("SELECT (.+) FROM rate_limit_buckets").
+		WithArgs("k", 10, limit.Rate).
+		WillReturnRows(pgxmock.NewRows([]string{"tokens"}).AddRow(0.5))
+
+	res, err := store.Take(context.Background(), "k", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 3*time.Second, res.RetryAfter)
+
+	require.NoError(t, mockDb.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+// Package ratelimit — token bucket с подключаемым хранилищем состояния:
+// MemoryStore для одного инстанса, PostgresStore для общих лимитов реплик.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit — ведро ёмкостью Burst, пополняемое на Rate токенов в секунду.
+// Запрос стоит один токен.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerPeriod — n запросов за period: до n подряд, дальше по одному каждые period/n.
+func PerPeriod(n int, period time.Duration) Limit {
+	return Limit{Rate: float64(n) / period.Seconds(), Burst: n}
+}
+
+// Period — время полного пополнения ведра.
+func (l Limit) Period() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// ParseLimit разбирает квоту вида "<запросов>/<период>", например "10/1m".
+func ParseLimit(s string) (Limit, error) {
+	count, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q: want <requests>/<period>", s)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("rate limit %q: requests must be a positive integer", s)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: period must be a positive duration", s)
+	}
+
+	return PerPeriod(n, d), nil
+}
+
+// Result — решение по запросу и данные для заголовков RateLimit-*.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter — через сколько появится токен (только при отказе).
+	RetryAfter time.Duration
+	// Reset — через сколько ведро заполнится полностью.
+	Reset time.Duration
+}
+
+// Store хранит состояние вёдер. Take атомарно пополняет ведро key по limit и
+// списывает токен, если он есть.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult считает Result по числу токенов после решения.
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+
+	return res
+}
+
+// secondsToDuration округляет до миллисекунды, чтобы погрешность float не
+// превращала 3s в 2.999999999s.
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Round(max(s, 0)*1000)) * time.Millisecond
+}
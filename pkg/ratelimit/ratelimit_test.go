@@ -0,0 +1,24 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	t.Parallel()
+
+	limit, err := ParseLimit("10/1m")
+	require.NoError(t, err)
+	assert.Equal(t, 10, limit.Burst)
+	assert.InDelta(t, 10.0/60, limit.Rate, 1e-9)
+	assert.Equal(t, time.Minute, limit.Period())
+
+	for _, bad := range []string{"", "10", "0/1m", "-1/1m", "10/abc", "10/0s", "x/1m"} {
+		_, err := ParseLimit(bad)
+		assert.Error(t, err, bad)
+	}
+}
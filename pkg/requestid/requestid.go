@@ -0,0 +1,57 @@
+// Package requestid — идентификатор запроса в context: его кладёт
+// транспортный middleware, а логгер добавляет в каждую запись. В отличие от
+// trace_id он есть всегда, даже если трейс отброшен сэмплером.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header — заголовок, в котором ID приходит от клиента и возвращается в ответе.
+const Header = "X-Request-ID"
+
+// maxLen ограничивает чужой ID: он попадает в логи и заголовки ответа.
+const maxLen = 128
+
+type ctxKey struct{}
+
+// NewContext возвращает ctx с идентификатором запроса.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса или "", если его нет.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// New генерирует ID — 16 случайных байт в hex.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // crypto/rand.Read не возвращает ошибок
+
+	return hex.EncodeToString(b[:])
+}
+
+// Valid — можно ли принять ID от клиента как есть: непустой, не длиннее
+// maxLen, только [A-Za-z0-9._:-]. Кавычки и управляющие символы сломали бы
+// JSON access-лога и позволили бы подделывать строки лога.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+
+	for i := range len(id) {
+		switch c := id[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
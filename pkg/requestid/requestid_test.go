@@ -0,0 +1,36 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, "abc", FromContext(NewContext(context.Background(), "abc")))
+}
+
+func TestNewIsValidAndUnique(t *testing.T) {
+	t.Parallel()
+
+	a, b := New(), New()
+	assert.Len(t, a, 32)
+	assert.True(t, Valid(a))
+	assert.NotEqual(t, a, b)
+}
+
+func TestValid(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []string{"abc", "0f8fad5b-d9cb-469f-a165-70867728950e", "svc:req_1.2"} {
+		assert.True(t, Valid(id), id)
+	}
+	for _, id := range []string{"", `a"b`, "a b", "line\nbreak", "юникод", strings.Repeat("a", maxLen+1)} {
+		assert.False(t, Valid(id), id)
+	}
+}
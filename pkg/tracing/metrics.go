@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"clean-arch-template/config"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/otlptranslator"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+)
+
+// Читатели метрик (config.Metrics.Readers).
+const (
+	ReaderPrometheus = "prometheus"
+	ReaderOTLPGRPC   = "otlp-grpc"
+	ReaderOTLPHTTP   = "otlp-http"
+)
+
+// InitMeterProvider создаёт MeterProvider с читателями из cfg.Metrics.Readers
+// и делает его глобальным. prometheus — мост в prometheus.DefaultRegisterer:
+// метрики отдаёт существующий /metrics; otlp-* — периодический push в
+// коллектор. Читателей может быть несколько; инструменты от выбора не зависят.
+func InitMeterProvider(ctx context.Context, cfg *config.Config, log logger.Logger) (*sdkmetric.MeterProvider, error) {
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	for _, name := range cfg.Metrics.Readers {
+		reader, err := newMetricReader(ctx, cfg, prometheus.DefaultRegisterer, strings.TrimSpace(name))
+		if err != nil {
+			log.Error(ctx, "metric reader could not be created", "reader", name, "error", err.Error())
+			return nil, err
+		}
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+
+	provider := sdkmetric.NewMeterProvider(opts...)
+	otel.SetMeterProvider(provider)
+
+	log.Info(ctx, "metrics initialized", "readers", strings.Join(cfg.Metrics.Readers, ","))
+
+	return provider, nil
+}
+
+func newMetricReader(ctx context.Context, cfg *config.Config, reg prometheus.Registerer, name string) (sdkmetric.Reader, error) {
+	switch name {
+	case ReaderPrometheus:
+		// Имена в стиле Prometheus (суффиксы единиц и _total) — на них
+		// завязаны дашборды. Exemplars экспортёр отдаёт в OpenMetrics.
+		return otelprom.New(
+			otelprom.WithRegisterer(reg),
+			otelprom.WithTranslationStrategy(otlptranslator.UnderscoreEscapingWithSuffixes),
+			otelprom.WithoutScopeInfo(),
+		)
+	case ReaderOTLPGRPC, ReaderOTLPHTTP:
+		exporter, err := newMetricExporter(ctx, cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(cfg.Metrics.Interval)), nil
+	default:
+		return nil, fmt.Errorf("unknown metric reader %q (supported: %s, %s, %s)",
+			name, ReaderPrometheus, ReaderOTLPGRPC, ReaderOTLPHTTP)
+	}
+}
+
+// newMetricExporter — OTLP-экспортёр метрик. Коллектор по умолчанию тот же,
+// что у трейсов; TLS и заголовки общие (config.Tracing).
+func newMetricExporter(ctx context.Context, cfg *config.Config, name string) (sdkmetric.Exporter, error) {
+	endpoint := cfg.Metrics.URL
+	if endpoint == "" {
+		endpoint = cfg.Tracing.URL
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("metrics url is required for %s reader", name)
+	}
+
+	tc := cfg.Tracing
+	var tlsConfig *tls.Config
+	if !tc.Insecure {
+		var err error
+		if tlsConfig, err = newTLSConfig(tc); err != nil {
+			return nil, err
+		}
+	}
+
+	isURL := strings.Contains(endpoint, "://")
+
+	if name == ReaderOTLPHTTP {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithHeaders(tc.Headers)}
+		if isURL {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(endpoint))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(endpoint))
+		}
+		if tlsConfig == nil {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		} else {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+		}
+
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithHeaders(tc.Headers)}
+	if isURL {
+		opts = append(opts, otlpmetricgrpc.WithEndpointURL(endpoint))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(endpoint))
+	}
+	if tlsConfig == nil {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
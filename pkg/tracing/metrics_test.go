@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"clean-arch-template/config"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestNewMetricReader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := &config.Config{}
+	cfg.Metrics.Interval = time.Minute
+
+	reader, err := newMetricReader(ctx, cfg, prometheus.NewRegistry(), ReaderPrometheus)
+	require.NoError(t, err)
+	assert.NotNil(t, reader)
+
+	_, err = newMetricReader(ctx, cfg, prometheus.NewRegistry(), "statsd")
+	require.ErrorContains(t, err, "unknown metric reader")
+
+	_, err = newMetricReader(ctx, cfg, prometheus.NewRegistry(), ReaderOTLPHTTP)
+	require.ErrorContains(t, err, "url is required")
+
+	// Без METRICS_URL метрики идут в коллектор трейсов.
+	cfg.Tracing.URL = "collector:4318"
+	cfg.Tracing.Insecure = true
+	reader, err = newMetricReader(ctx, cfg, prometheus.NewRegistry(), ReaderOTLPHTTP)
+	require.NoError(t, err)
+	assert.IsType(t, &sdkmetric.PeriodicReader{}, reader)
+	require.NoError(t, reader.Shutdown(ctx))
+}
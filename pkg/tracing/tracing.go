@@ -1,54 +1,219 @@
-// Package tracing otlp
+// Package tracing — настройка OpenTelemetry по config.Tracing: экспортёр,
+// сэмплер, ресурс и пропагаторы.
 package tracing
 
 import (
 	"clean-arch-template/config"
 	"clean-arch-template/pkg/logger"
+	"clean-arch-template/version"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"google.golang.org/grpc/credentials"
 )
 
-const fraction = 0.6
+// Экспортёры (config.Tracing.Exporter).
+const (
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterStdout   = "stdout"
+	ExporterNone     = "none"
+)
 
-// InitOpenTelemetryGRPC oltp init.
-func InitOpenTelemetryGRPC(ctx context.Context, cfg *config.Config, log logger.Logger) (*trace.TracerProvider, error) {
-	exporter, err := otlptracegrpc.New(
-		ctx,
-		otlptracegrpc.WithEndpoint(cfg.URL),
-		otlptracegrpc.WithInsecure(),
-	)
+// InitOpenTelemetry создаёт TracerProvider по cfg.Tracing и делает его
+// глобальным вместе с пропагаторами W3C TraceContext и Baggage.
+func InitOpenTelemetry(ctx context.Context, cfg *config.Config, log logger.Logger) (*trace.TracerProvider, error) {
+	sampler, err := newSampler(cfg.Tracing.Sampler, cfg.Tracing.SampleRatio)
 	if err != nil {
-		log.Error(ctx, "tracing exporter could not be created", "error", err.Error())
 		return nil, err
 	}
 
-	// labels/tags/resources that are common to all traces.
-	res := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceNameKey.String(cfg.Name),
-		// attribute.String("some-attribute", "some-value"), ...
-	)
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	provider := trace.NewTracerProvider(
-		trace.WithBatcher(exporter),
+	opts := []trace.TracerProviderOption{
 		trace.WithResource(res),
-		// set the sampling rate based on the parent span to 60%
-		trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(fraction))),
-	)
+		trace.WithSampler(sampler),
+	}
+
+	exporter, err := newExporter(ctx, cfg.Tracing)
+	if err != nil {
+		log.Error(ctx, "tracing exporter could not be created", "exporter", cfg.Tracing.Exporter, "error", err.Error())
+		return nil, err
+	}
+	// Без экспортёра спаны всё равно создаются: trace_id попадает в логи и
+	// пробрасывается дальше, просто никуда не отправляется.
+	if exporter != nil {
+		opts = append(opts, trace.WithBatcher(exporter))
+	}
+
+	provider := trace.NewTracerProvider(opts...)
 
 	otel.SetTracerProvider(provider)
 
 	otel.SetTextMapPropagator(
 		propagation.NewCompositeTextMapPropagator(
 			propagation.TraceContext{}, // W3C Trace Context format; https://www.w3.org/TR/trace-context/
+			propagation.Baggage{},      // W3C Baggage; https://www.w3.org/TR/baggage/
 		),
 	)
 
+	log.Info(ctx, "tracing initialized",
+		"exporter", cfg.Tracing.Exporter, "sampler", cfg.Tracing.Sampler, "ratio", cfg.Tracing.SampleRatio)
+
 	return provider, nil
 }
+
+// newSampler — сэмплер по имени из спецификации OTEL_TRACES_SAMPLER.
+func newSampler(name string, ratio float64) (trace.Sampler, error) {
+	if ratio < 0 || ratio > 1 {
+		return nil, fmt.Errorf("tracing sample ratio %v: must be within [0, 1]", ratio)
+	}
+
+	switch name {
+	case "always_on":
+		return trace.AlwaysSample(), nil
+	case "always_off":
+		return trace.NeverSample(), nil
+	case "traceidratio":
+		return trace.TraceIDRatioBased(ratio), nil
+	case "parentbased_always_on":
+		return trace.ParentBased(trace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return trace.ParentBased(trace.NeverSample()), nil
+	case "parentbased_traceidratio", "":
+		return trace.ParentBased(trace.TraceIDRatioBased(ratio)), nil
+	default:
+		return nil, fmt.Errorf("unknown tracing sampler %q", name)
+	}
+}
+
+// newResource — атрибуты, общие для всех спанов сервиса.
+func newResource(ctx context.Context, cfg *config.Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(cfg.Name),
+		semconv.ServiceVersion(version.Version),
+		semconv.DeploymentEnvironmentNameKey.String(string(cfg.Environment)),
+	}
+	for k, v := range cfg.Tracing.ResourceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithHost(),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing resource: %w", err)
+	}
+
+	return res, nil
+}
+
+// newExporter возвращает nil для ExporterNone.
+func newExporter(ctx context.Context, cfg config.Tracing) (trace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterNone:
+		return nil, nil //nolint:nilnil // отсутствие экспортёра — валидная конфигурация
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLPGRPC, ExporterOTLPHTTP:
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q (supported: %s, %s, %s, %s)",
+			cfg.Exporter, ExporterOTLPGRPC, ExporterOTLPHTTP, ExporterStdout, ExporterNone)
+	}
+
+	if cfg.URL == "" {
+		return nil, errors.New("tracing url is required for otlp exporters")
+	}
+
+	var tlsConfig *tls.Config
+	if !cfg.Insecure {
+		var err error
+		if tlsConfig, err = newTLSConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// Полный URL задаёт и схему, и путь; host:port — только адрес.
+	isURL := strings.Contains(cfg.URL, "://")
+
+	if cfg.Exporter == ExporterOTLPHTTP {
+		opts := []otlptracehttp.Option{otlptracehttp.WithHeaders(cfg.Headers)}
+		if isURL {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.URL))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.URL))
+		}
+		if tlsConfig == nil {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		}
+
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(cfg.Headers)}
+	if isURL {
+		opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.URL))
+	} else {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.URL))
+	}
+	if tlsConfig == nil {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newTLSConfig — TLS к коллектору: CAFile вместо системных CA, клиентский
+// сертификат — только парой CertFile + KeyFile.
+func newTLSConfig(cfg config.Tracing) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tracing ca file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tracing ca file %s: no PEM certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("tracing cert_file and key_file must be set together")
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tracing client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
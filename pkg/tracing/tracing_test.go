@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"clean-arch-template/config"
+	"clean-arch-template/pkg/logger"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestNewSampler(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"always_on":                "AlwaysOnSampler",
+		"always_off":               "AlwaysOffSampler",
+		"traceidratio":             "TraceIDRatioBased{0.25}",
+		"parentbased_traceidratio": "ParentBased{root:TraceIDRatioBased{0.25}",
+		"":                         "ParentBased{root:TraceIDRatioBased{0.25}",
+	}
+	for name, want := range tests {
+		sampler, err := newSampler(name, 0.25)
+		require.NoError(t, err, name)
+		assert.Contains(t, sampler.Description(), want, name)
+	}
+
+	_, err := newSampler("sometimes", 0.5)
+	require.Error(t, err)
+
+	_, err = newSampler("traceidratio", 1.5)
+	require.Error(t, err)
+}
+
+func TestNewExporterValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	exporter, err := newExporter(ctx, config.Tracing{Exporter: ExporterNone})
+	require.NoError(t, err)
+	assert.Nil(t, exporter)
+
+	_, err = newExporter(ctx, config.Tracing{Exporter: "zipkin"})
+	require.ErrorContains(t, err, "unknown tracing exporter")
+
+	_, err = newExporter(ctx, config.Tracing{Exporter: ExporterOTLPHTTP})
+	require.ErrorContains(t, err, "url is required")
+
+	_, err = newExporter(ctx, config.Tracing{Exporter: ExporterOTLPGRPC, URL: "collector:4317", CertFile: "client.pem"})
+	require.ErrorContains(t, err, "must be set together")
+}
+
+func TestInitOpenTelemetryWithoutCollector(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.Name = "test"
+	cfg.Tracing.Exporter = ExporterNone
+	cfg.Tracing.Sampler = "always_on"
+	cfg.Tracing.ResourceAttributes = map[string]string{"team": "payments"}
+
+	provider, err := InitOpenTelemetry(context.Background(), cfg, logger.Nop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	_, span := provider.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	assert.True(t, span.SpanContext().IsValid(), "без экспортёра спаны всё равно создаются")
+
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"runtime/debug"
+	"strings"
 )
 
 var Version = "dev"
@@ -13,3 +15,27 @@ var Version = "dev"
 func PrintVersion(cfg *config.Config, log logger.Logger) {
 	log.Info(context.Background(), fmt.Sprintf("Application %s version %s", cfg.Name, Version))
 }
+
+// BuildInfo — версия и сведения о сборке из runtime/debug: версия Go, коммит
+// и время коммита (go build записывает их из VCS), флаг незакоммиченных
+// изменений.
+func BuildInfo() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "version: %s\n", Version)
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "go: %s\n", info.GoVersion)
+	fmt.Fprintf(&b, "module: %s\n", info.Main.Path)
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified", "GOOS", "GOARCH", "CGO_ENABLED":
+			fmt.Fprintf(&b, "%s: %s\n", s.Key, s.Value)
+		}
+	}
+
+	return b.String()
+}